@@ -25,6 +25,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -37,14 +38,11 @@ import (
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/driver"
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
+	"github.com/vhive-serverless/loader/pkg/metric"
 	"github.com/vhive-serverless/loader/pkg/trace"
 
 	log "github.com/sirupsen/logrus"
-	tracer "github.com/vhive-serverless/vSwarm/utils/tracing/go"
-)
-
-const (
-	zipkinAddr = "http://localhost:9411/api/v2/spans"
 )
 
 var (
@@ -54,6 +52,8 @@ var (
 	iatGeneration = flag.Bool("iatGeneration", false, "Generate IATs only or run invocations as well")
 	iatFromFile   = flag.Bool("generated", false, "True if iats were already generated")
 	dryRun        = flag.Bool("dryRun", false, "Dry run mode - do not deploy functions or generate invocations")
+	renderOnly    = flag.Bool("renderOnly", false, "Render only mode - write out what would be deployed and exit")
+	renderDir     = flag.String("renderDir", "rendered", "Directory to write the deployment to in the render only mode")
 )
 
 func init() {
@@ -76,13 +76,20 @@ func init() {
 }
 
 func main() {
+	if flag.NArg() > 0 {
+		runSubcommand(flag.Arg(0), flag.Args()[1:])
+		return
+	}
+
 	cfg := config.ReadConfigurationFile(*configPath)
 	if cfg.EnableZipkinTracing {
 		// TODO: how not to exclude Zipkin spans here? - file a feature request
 		log.Warnf("Zipkin tracing has been enabled. This will exclude Istio spans from the Zipkin traces.")
-		shutdown, err := tracer.InitBasicTracer(zipkinAddr, "loader")
+	}
+	if tracing.Enabled(&cfg) {
+		shutdown, err := tracing.Init(&cfg)
 		if err != nil {
-			log.Print(err)
+			log.Fatalf("Failed to set up tracing - %v", err)
 		}
 		defer shutdown()
 	}
@@ -96,6 +103,8 @@ func main() {
 		common.PlatformAWSLambda,
 		common.PlatformDirigent,
 		common.PlatformAzureFunctions,
+		common.PlatformOpenFaaS,
+		common.PlatformFission,
 	}
 	if !slices.Contains(supportedPlatforms, cfg.Platform) {
 		log.Fatal("Unsupported platform!")
@@ -105,6 +114,30 @@ func main() {
 		common.CheckCPULimit(cfg.CPULimit)
 	}
 
+	if cfg.ScaleProfilingStrategy != "" && !slices.Contains(common.ValidScaleProfilingStrategies, cfg.ScaleProfilingStrategy) {
+		log.Fatalf("Unsupported scale profiling strategy '%s'!", cfg.ScaleProfilingStrategy)
+	}
+	if cfg.ScaleProfilingPercentile < 0 || cfg.ScaleProfilingPercentile > 100 {
+		log.Fatalf("Scale profiling percentile %v is not within (0, 100]!", cfg.ScaleProfilingPercentile)
+	}
+
+	if cfg.DeploymentPolicy != "" && !slices.Contains(common.ValidDeploymentPolicies, cfg.DeploymentPolicy) {
+		log.Fatalf("Unsupported deployment failure policy '%s'!", cfg.DeploymentPolicy)
+	}
+
+	if cfg.PreWarmMode != "" && !slices.Contains(common.ValidPreWarmModes, cfg.PreWarmMode) {
+		log.Fatalf("Unsupported pre-warm mode '%s'!", cfg.PreWarmMode)
+	}
+
+	for recordType, format := range cfg.OutputFormats {
+		if !slices.ContainsFunc(metric.RecordSchemas, func(schema metric.RecordSchema) bool { return schema.Name == recordType }) {
+			log.Fatalf("Unsupported record type '%s' in the output formats!", recordType)
+		}
+		if !slices.Contains(common.ValidOutputFormats, format) {
+			log.Fatalf("Unsupported output format '%s' of %s!", format, recordType)
+		}
+	}
+
 	if cfg.TracePath == "RPS" {
 		runRPSMode(&cfg, *iatFromFile, *iatGeneration)
 	} else {
@@ -146,11 +179,15 @@ func parseIATDistribution(cfg *config.LoaderConfiguration) (common.IatDistributi
 func parseYAMLSpecification(cfg *config.LoaderConfiguration) string {
 	switch cfg.YAMLSelector {
 	case "container":
+		if cfg.KnativeAsyncMode {
+			return "workloads/container/trace_func_go_cloudevents.yaml"
+		}
 		return "workloads/container/trace_func_go.yaml"
 	case "firecracker":
 		return "workloads/firecracker/trace_func_go.yaml"
 	default:
-		if cfg.Platform != common.PlatformDirigent && cfg.Platform != common.PlatformAzureFunctions {
+		if cfg.Platform != common.PlatformDirigent && cfg.Platform != common.PlatformAzureFunctions &&
+			cfg.Platform != common.PlatformOpenFaaS && cfg.Platform != common.PlatformFission {
 			log.Fatal("Invalid 'YAMLSelector' parameter.")
 		}
 	}
@@ -179,9 +216,17 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 
 	// Azure trace parsing
 	if !cfg.VSwarm {
-		traceParser = trace.NewAzureParser(cfg.TracePath, durationToParse, yamlPath)
+		azureParser := trace.NewAzureParser(cfg.TracePath, durationToParse, yamlPath)
+		if cfg.ReuseDeployment {
+			azureParser.SeedFunctionNames(cfg.Seed)
+		}
+		traceParser = azureParser
 	} else {
-		traceParser = trace.NewMapperParser(cfg.TracePath, durationToParse)
+		mapperParser := trace.NewMapperParser(cfg.TracePath, durationToParse)
+		if cfg.ReuseDeployment {
+			mapperParser.SeedFunctionNames(cfg.Seed)
+		}
+		traceParser = mapperParser
 	}
 
 	functions = traceParser.Parse()
@@ -218,6 +263,11 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 		return
 	}
 
+	if *renderOnly {
+		experimentDriver.RenderDeployment(*renderDir)
+		return
+	}
+
 	log.Infof("Using %s as a service YAML specification file.\n", yamlPath)
 
 	experimentDriver.GenerateSpecification()
@@ -255,6 +305,200 @@ func runRPSMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATs
 		return
 	}
 
+	if *renderOnly {
+		experimentDriver.RenderDeployment(*renderDir)
+		return
+	}
+
 	experimentDriver.ReadOrWriteFileSpecification(writeIATsToFile, readIATFromFile)
 	experimentDriver.RunExperiment()
 }
+
+func runSubcommand(name string, args []string) {
+	switch name {
+	case "sample":
+		runSampleCommand(args)
+	case "synthesize":
+		runSynthesizeCommand(args)
+	case "describe-trace":
+		runDescribeTraceCommand(args)
+	case "merge-latency":
+		runMergeLatencyCommand(args)
+	default:
+		log.Fatalf("Unknown subcommand '%s'.", name)
+	}
+}
+
+func parseTraceDirectory(path string) []*common.Function {
+	functions := trace.NewAzureParser(path, trace.ReadTraceDuration(path), "").Parse()
+	if _, err := os.Stat(path + "/dirigent.json"); err == nil {
+		trace.NewDirigentMetadataParser(path, functions, "", common.PlatformDirigent).Parse()
+	}
+
+	return functions
+}
+
+func runSampleCommand(args []string) {
+	flags := flag.NewFlagSet("sample", flag.ExitOnError)
+	sourcePath := flags.String("trace", "data/traces/example", "Path to the trace to draw the sample from")
+	originalPath := flags.String("original", "", "Path to the original trace the sample should be representative of (default: -trace)")
+	outputPath := flags.String("output", "data/traces/sample", "Output path for the sampled trace")
+	size := flags.Int("size", 10, "Sample size (#functions)")
+	trials := flags.Int("trials", 16, "Number of sampling trials, out of which the sample closest to the original trace is kept")
+	seed := flags.Int64("seed", 42, "Seed for the sampler (for reproducibility)")
+	_ = flags.Parse(args)
+
+	source := parseTraceDirectory(*sourcePath)
+	original := source
+	if *originalPath != "" && *originalPath != *sourcePath {
+		original = parseTraceDirectory(*originalPath)
+	}
+
+	sample, distance := trace.NewTraceSampler(original, *seed).Sample(source, *size, *trials)
+
+	log.Infof("Sampled %d out of %d functions. Wasserstein distance from the original trace:", len(sample), len(source))
+	log.Infof("\tinvocations per minute: \t%.4f", distance.Invocations)
+	log.Infof("\tresources per minute: \t%.4f", distance.Resources)
+	log.Infof("\taverage runtime: \t\t%.4f", distance.Runtime)
+	log.Infof("\taverage memory: \t\t%.4f", distance.Memory)
+	log.Infof("\taverage: \t\t\t%.4f", distance.Average)
+
+	if err := trace.WriteAzureTrace(*outputPath, sample); err != nil {
+		log.Fatalf("Failed to write the sampled trace: %v", err)
+	}
+}
+
+func runSynthesizeCommand(args []string) {
+	flags := flag.NewFlagSet("synthesize", flag.ExitOnError)
+	specPath := flags.String("spec", "", "Path to a JSON array of synthesis specifications (overrides the per-group flags)")
+	outputPath := flags.String("output", "data/traces/synthetic", "Output path for the synthetic trace")
+	functions := flags.Int("functions", 1, "Number of functions")
+	beginning := flags.Float64("beginning", 1, "Starting RPS of each function")
+	target := flags.Float64("target", 1, "Target RPS of each function")
+	step := flags.Float64("step", 0, "RPS step of the ramp (0 for a constant RPS)")
+	stepDuration := flags.Int("stepDuration", 1, "Duration of each RPS step in minutes")
+	duration := flags.Int("duration", 0, "Minimum trace duration in minutes, padded with zeros")
+	runtime := flags.Int("runtime", 1000, "Execution time of the functions in milliseconds")
+	memory := flags.Int("memory", 128, "Memory footprint of the functions in MB")
+	trigger := flags.String("trigger", common.TriggerHTTP, "Trigger of the functions")
+	dirigentImage := flags.String("dirigentImage", "", "Image to write into dirigent.json (no dirigent.json if empty)")
+	seed := flags.Int64("seed", 42, "Seed for the function hashes (for reproducibility)")
+	_ = flags.Parse(args)
+
+	specifications := []generator.SynthesisSpecification{{
+		Functions:           *functions,
+		Trigger:             *trigger,
+		BeginningRPS:        *beginning,
+		TargetRPS:           *target,
+		StepRPS:             *step,
+		StepDurationMinutes: *stepDuration,
+		DurationMinutes:     *duration,
+		RuntimeMs:           *runtime,
+		MemoryMB:            *memory,
+		DirigentImage:       *dirigentImage,
+	}}
+
+	if *specPath != "" {
+		var err error
+		if specifications, err = generator.ReadSynthesisSpecifications(*specPath); err != nil {
+			log.Fatalf("Failed to read the synthesis specification: %v", err)
+		}
+	}
+
+	if err := trace.WriteAzureTrace(*outputPath, generator.SynthesizeFunctions(specifications, *seed)); err != nil {
+		log.Fatalf("Failed to write the synthetic trace: %v", err)
+	}
+}
+
+func runMergeLatencyCommand(args []string) {
+	flags := flag.NewFlagSet("merge-latency", flag.ExitOnError)
+	outputPath := flags.String("output", "latency_merged.json", "Path to write the merged latency summary to")
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		log.Fatal("No latency summaries to merge.")
+	}
+
+	var merged *metric.LatencySummary
+	for _, path := range flags.Args() {
+		summary, err := metric.ReadLatencySummary(path)
+		if err != nil {
+			log.Fatalf("Failed to read the latency summary: %v", err)
+		}
+
+		if merged == nil {
+			merged = summary
+		} else if err = merged.Merge(summary); err != nil {
+			log.Fatalf("Failed to merge the latency summary %s: %v", path, err)
+		}
+	}
+
+	if err := metric.WriteLatencySummary(*outputPath, merged); err != nil {
+		log.Fatalf("Failed to write the merged latency summary: %v", err)
+	}
+
+	log.Infof("Merged %d latency summaries into %s", flags.NArg(), *outputPath)
+}
+
+func runDescribeTraceCommand(args []string) {
+	flags := flag.NewFlagSet("describe-trace", flag.ExitOnError)
+	loaderConfigPath := flags.String("config", "", "Path to the loader configuration to take the trace, duration and warmup from")
+	tracePath := flags.String("trace", "", "Path to the trace to describe (default: TracePath of -config or data/traces/example)")
+	duration := flags.Int("duration", 0, "Experiment duration in minutes (default: ExperimentDuration of -config or the rest of the trace)")
+	warmup := flags.Int("warmup", -1, "Warmup duration in minutes (default: WarmupDuration of -config or 0)")
+	idleThreshold := flags.Int("idleThreshold", 1, "Idle minutes after which an invocation is expected to cause a cold start")
+	outputPath := flags.String("output", "", "Path to write the JSON report to")
+	printJSON := flags.Bool("json", false, "Print the JSON report instead of the table")
+	_ = flags.Parse(args)
+
+	profiling := trace.ScaleProfilingConfiguration{}
+	if *loaderConfigPath != "" {
+		cfg := config.ReadConfigurationFile(*loaderConfigPath)
+		if cfg.TracePath == "RPS" {
+			log.Fatal("Cannot describe the trace of the RPS mode.")
+		}
+
+		if *tracePath == "" {
+			*tracePath = cfg.TracePath
+		}
+		if *duration == 0 {
+			*duration = cfg.ExperimentDuration
+		}
+		if *warmup < 0 {
+			*warmup = cfg.WarmupDuration
+		}
+		profiling.Strategy = cfg.ScaleProfilingStrategy
+		profiling.Percentile = cfg.ScaleProfilingPercentile
+	}
+	if *tracePath == "" {
+		*tracePath = "data/traces/example"
+	}
+	*warmup = common.MaxOf(*warmup, 0)
+
+	traceDuration := trace.ReadTraceDuration(*tracePath)
+	if *duration <= 0 || *warmup+*duration > traceDuration {
+		*duration = common.MaxOf(traceDuration-*warmup, 0)
+	}
+
+	functions := trace.NewAzureParser(*tracePath, *warmup+*duration, "").Parse()
+	profiling.WarmupDuration = *warmup
+	profiling.ExperimentDuration = *duration
+	report := trace.ComputeTraceReport(functions, profiling, *idleThreshold)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to serialize the trace report: %v", err)
+	}
+
+	if *outputPath != "" {
+		if err = os.WriteFile(*outputPath, data, 0644); err != nil {
+			log.Fatalf("Failed to write the trace report: %v", err)
+		}
+	}
+
+	if *printJSON {
+		fmt.Println(string(data))
+	} else {
+		report.WriteTable(os.Stdout)
+	}
+}
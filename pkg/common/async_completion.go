@@ -0,0 +1,12 @@
+package common
+
+// AsyncCompletion Completion of an asynchronous invocation pushed to the loader, either as the data of a completion
+// CloudEvent or as a plain JSON body. It is correlated with the invocation by the ID the submission returned.
+type AsyncCompletion struct {
+	InvocationID       string `json:"invocationID"`
+	Function           string `json:"function"`
+	Message            string `json:"message"`
+	ReceivedAt         int64  `json:"receivedAt"` // UNIX microseconds at which the function received the invocation
+	DurationInMicroSec uint32 `json:"durationInMicroSec"`
+	MemoryUsageInKb    uint32 `json:"memoryUsageInKb"`
+}
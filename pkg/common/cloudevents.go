@@ -0,0 +1,26 @@
+package common
+
+// CloudEvents exchanged between the loader and the trace function in the Knative asynchronous mode. Events use the
+// binary content mode, i.e., the attributes are sent as ce-* headers and the data as a JSON body.
+const (
+	CloudEventSpecVersion    = "1.0"
+	CloudEventSource         = "vhive-loader"
+	CloudEventInvocationType = "dev.vhive.loader.invocation"
+	CloudEventCompletionType = "dev.vhive.loader.completion"
+
+	CloudEventIDHeader          = "Ce-Id"
+	CloudEventSpecVersionHeader = "Ce-Specversion"
+	CloudEventSourceHeader      = "Ce-Source"
+	CloudEventTypeHeader        = "Ce-Type"
+	// CloudEventFunctionHeader Extension the Knative triggers filter on to deliver the event to the right function
+	CloudEventFunctionHeader = "Ce-Function"
+	// CloudEventReplyToHeader Extension carrying the URL of the sink the function reports the completion to
+	CloudEventReplyToHeader = "Ce-Replyto"
+)
+
+// CloudEventInvocation Data of the invocation event
+type CloudEventInvocation struct {
+	Function          string `json:"function"`
+	RuntimeInMilliSec uint32 `json:"runtimeInMilliSec"`
+	MemoryInMebiBytes uint32 `json:"memoryInMebiBytes"`
+}
@@ -54,6 +54,8 @@ const (
 	Exponential IatDistribution = iota
 	Uniform
 	Equidistant
+	// Periodic spreads all the invocations of a function at a fixed period over the whole trace
+	Periodic
 )
 
 type TraceGranularity int
@@ -113,8 +115,74 @@ const (
 	PlatformOpenWhisk      string = "openwhisk"
 	PlatformAWSLambda      string = "awslambda"
 	PlatformAzureFunctions string = "azurefunctions"
+	PlatformOpenFaaS       string = "openfaas"
+	PlatformFission        string = "fission"
 )
 
+// trigger types as found in the Azure Functions trace
+const (
+	TriggerHTTP          string = "http"
+	TriggerQueue         string = "queue"
+	TriggerEvent         string = "event"
+	TriggerTimer         string = "timer"
+	TriggerStorage       string = "storage"
+	TriggerOrchestration string = "orchestration"
+	TriggerOthers        string = "others"
+)
+
+// AsyncTriggers Triggers whose invocations are not awaited by the caller and are hence invoked asynchronously
+var AsyncTriggers = []string{TriggerQueue, TriggerEvent, TriggerTimer}
+
+// initial scale profiling strategies
+const (
+	// ScaleProfilingFirstMinute Little's law concurrency in the first minute of the trace
+	ScaleProfilingFirstMinute string = "first_minute"
+	// ScaleProfilingWarmupMax Maximum per-minute concurrency over the warmup
+	ScaleProfilingWarmupMax string = "warmup_max"
+	// ScaleProfilingPercentile Percentile of the per-minute concurrency over the warmup and the experiment
+	ScaleProfilingPercentile string = "percentile"
+	// ScaleProfilingSchedule Per-minute concurrency over the warmup, applied as minimum scale by the deployer
+	ScaleProfilingSchedule string = "schedule"
+)
+
+var ValidScaleProfilingStrategies = []string{ScaleProfilingFirstMinute, ScaleProfilingWarmupMax, ScaleProfilingPercentile, ScaleProfilingSchedule}
+
+// policies of handling the functions that failed to deploy
+const (
+	// DeploymentPolicyAbort Cleans up the deployed functions and aborts the experiment
+	DeploymentPolicyAbort string = "abort"
+	// DeploymentPolicyRetry Redeploys the failed functions a number of times, aborting if any of them still fails
+	DeploymentPolicyRetry string = "retry"
+	// DeploymentPolicyDrop Runs the experiment without the failed functions
+	DeploymentPolicyDrop string = "drop"
+)
+
+var ValidDeploymentPolicies = []string{DeploymentPolicyAbort, DeploymentPolicyRetry, DeploymentPolicyDrop}
+
+// modes of driving the functions to their target scale in the pre-warm phase
+const (
+	// PreWarmInvoke Issues synthetic invocations, as many at a time as the target scale of the function
+	PreWarmInvoke string = "invoke"
+	// PreWarmScale Sets the target scale as the minimum scale of the function through the platform
+	PreWarmScale string = "scale"
+)
+
+var ValidPreWarmModes = []string{PreWarmInvoke, PreWarmScale}
+
+// formats of the output files of the records
+const (
+	// OutputFormatCSV Comma-separated values with a header row of the column names
+	OutputFormatCSV string = "csv"
+	// OutputFormatJSONLines One JSON object per line
+	OutputFormatJSONLines string = "jsonl"
+	// OutputFormatParquet Columnar Apache Parquet file, uncompressed and plain encoded
+	OutputFormatParquet string = "parquet"
+	// OutputFormatSQLite Table of an embedded SQLite database
+	OutputFormatSQLite string = "sqlite"
+)
+
+var ValidOutputFormats = []string{OutputFormatCSV, OutputFormatJSONLines, OutputFormatParquet, OutputFormatSQLite}
+
 // dirigent backend
 const (
 	BackendDandelion string = "dandelion"
@@ -0,0 +1,17 @@
+package common
+
+// HTTPInvocation Body of the plain HTTP invocations of the trace function, as issued through the OpenFaaS gateway and
+// the Fission router
+type HTTPInvocation struct {
+	Function          string `json:"function"`
+	RuntimeInMilliSec uint32 `json:"runtimeInMilliSec"`
+	MemoryInMebiBytes uint32 `json:"memoryInMebiBytes"`
+}
+
+// HTTPInvocationReply Body of the response to a plain HTTP invocation
+type HTTPInvocationReply struct {
+	Message            string `json:"message"`
+	Hostname           string `json:"hostname"`
+	DurationInMicroSec uint32 `json:"durationInMicroSec"`
+	MemoryUsageInKb    uint32 `json:"memoryUsageInKb"`
+}
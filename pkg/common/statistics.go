@@ -0,0 +1,80 @@
+package common
+
+import (
+	"math"
+	"sort"
+)
+
+// WassersteinDistance Computes the first Wasserstein distance between two empirical one-dimensional distributions,
+// i.e., the area between their cumulative distribution functions.
+func WassersteinDistance(u []float64, v []float64) float64 {
+	if len(u) == 0 || len(v) == 0 {
+		return 0
+	}
+
+	sortedU := append([]float64{}, u...)
+	sortedV := append([]float64{}, v...)
+	sort.Float64s(sortedU)
+	sort.Float64s(sortedV)
+
+	all := append(append([]float64{}, sortedU...), sortedV...)
+	sort.Float64s(all)
+
+	distance := 0.0
+	indexU, indexV := 0, 0
+	for i := 0; i < len(all)-1; i++ {
+		for indexU < len(sortedU) && sortedU[indexU] <= all[i] {
+			indexU++
+		}
+		for indexV < len(sortedV) && sortedV[indexV] <= all[i] {
+			indexV++
+		}
+
+		cdfU := float64(indexU) / float64(len(sortedU))
+		cdfV := float64(indexV) / float64(len(sortedV))
+
+		distance += math.Abs(cdfU-cdfV) * (all[i+1] - all[i])
+	}
+
+	return distance
+}
+
+// Percentile Returns the p-th percentile (p in [0, 100]) of the data using linear interpolation between the closest
+// ranks. The data is not modified.
+func Percentile(data []float64, p float64) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, data...)
+	sort.Float64s(sorted)
+
+	return PercentileSorted(sorted, p)
+}
+
+// PercentileSorted Same as Percentile, but the data must already be sorted in ascending order
+func PercentileSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+func Mean(data []float64) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, value := range data {
+		sum += value
+	}
+
+	return sum / float64(len(data))
+}
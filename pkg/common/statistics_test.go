@@ -0,0 +1,72 @@
+package common
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWassersteinDistance(t *testing.T) {
+	tests := []struct {
+		testName string
+		u        []float64
+		v        []float64
+		expected float64
+	}{
+		{
+			testName: "identical",
+			u:        []float64{1, 2, 3},
+			v:        []float64{3, 2, 1},
+			expected: 0,
+		},
+		{
+			testName: "shifted",
+			u:        []float64{0, 1, 2},
+			v:        []float64{5, 6, 7},
+			expected: 5,
+		},
+		{
+			// the distributions do not overlap, hence the distance is the difference of the means
+			testName: "different_sizes",
+			u:        []float64{0, 1, 3},
+			v:        []float64{5, 6, 8, 8},
+			expected: 6.75 - 4.0/3,
+		},
+		{
+			testName: "empty",
+			u:        []float64{},
+			v:        []float64{1},
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			got := WassersteinDistance(test.u, test.v)
+			if math.Abs(got-test.expected) > 1e-9 {
+				t.Errorf("Unexpected Wasserstein distance - expected: %f; got: %f", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	data := []float64{5, 1, 4, 2, 3}
+
+	pairs := map[float64]float64{
+		0:   1,
+		25:  2,
+		50:  3,
+		90:  4.6,
+		100: 5,
+	}
+
+	for p, expected := range pairs {
+		if got := Percentile(data, p); math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Unexpected percentile - p: %f; expected: %f; got: %f", p, expected, got)
+		}
+	}
+
+	if data[0] != 5 {
+		t.Error("Percentile should not modify the input data.")
+	}
+}
@@ -99,6 +99,8 @@ type Function struct {
 
 	// From the static trace profiler
 	InitialScale int
+	// ScaleSchedule Minimum scale in each minute of the warmup, if pre-scaling is used
+	ScaleSchedule []int
 	// From the trace
 	InvocationStats  *FunctionInvocationStats
 	RuntimeStats     *FunctionRuntimeStats
@@ -32,6 +32,7 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -142,6 +143,20 @@ func SumNumberOfInvocations(withWarmup bool, totalDuration int, functions []*Fun
 	return result
 }
 
+// GetTrigger Returns the trigger of the function as specified in the trace, or an empty string if unknown
+func GetTrigger(function *Function) string {
+	if function == nil || function.InvocationStats == nil {
+		return ""
+	}
+
+	return strings.ToLower(function.InvocationStats.Trigger)
+}
+
+// IsAsyncTrigger Returns whether the functions with the trigger are invoked asynchronously, regardless of its case
+func IsAsyncTrigger(trigger string) bool {
+	return slices.Contains(AsyncTriggers, strings.ToLower(trigger))
+}
+
 func GetName(function *Function) int {
 	parts := strings.Split(function.Name, "-")
 	if parts[0] == "test" {
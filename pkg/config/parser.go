@@ -64,24 +64,134 @@ type LoaderConfiguration struct {
 	ExperimentDuration int    `json:"ExperimentDuration"`
 	WarmupDuration     int    `json:"WarmupDuration"`
 
+	// output formats keyed by record type, where the record types without a format are written as before, i.e.,
+	// without schema version, to CSV files, except the cluster usage written as JSON lines
+	OutputFormats map[string]string `json:"OutputFormats"`
+
+	ScaleProfilingStrategy   string  `json:"ScaleProfilingStrategy"`
+	ScaleProfilingPercentile float64 `json:"ScaleProfilingPercentile"`
+
+	// pre-warm phase driving the functions to a target scale before the trace starts, skipped if the mode is not set
+	PreWarmMode           string `json:"PreWarmMode"`
+	PreWarmScale          int    `json:"PreWarmScale"`
+	PreWarmTimeoutSeconds int    `json:"PreWarmTimeoutSeconds"`
+
 	IsPartiallyPanic            bool   `json:"IsPartiallyPanic"`
 	EnableZipkinTracing         bool   `json:"EnableZipkinTracing"`
 	EnableMetricsScrapping      bool   `json:"EnableMetricsScrapping"`
 	MetricScrapingPeriodSeconds int    `json:"MetricScrapingPeriodSeconds"`
+	PrometheusAddress           string `json:"PrometheusAddress"`
+	MetricsEndpointAddress      string `json:"MetricsEndpointAddress"`
 	AutoscalingMetric           string `json:"AutoscalingMetric"`
 
+	// export of the spans of the invocations, whose trace context is propagated in the W3C traceparent header
+	TracingExporter      string  `json:"TracingExporter"`
+	TracingEndpoint      string  `json:"TracingEndpoint"`
+	TracingSamplingRatio float64 `json:"TracingSamplingRatio"`
+
 	GRPCConnectionTimeoutSeconds int  `json:"GRPCConnectionTimeoutSeconds"`
 	GRPCFunctionTimeoutSeconds   int  `json:"GRPCFunctionTimeoutSeconds"`
+	GRPCConnectionPooling        bool `json:"GRPCConnectionPooling"`
+	GRPCPoolSize                 int  `json:"GRPCPoolSize"`
+	GRPCPoolIdleTimeoutSeconds   int  `json:"GRPCPoolIdleTimeoutSeconds"`
 	DAGMode                      bool `json:"DAGMode"`
 	EnableDAGDataset             bool `json:"EnableDAGDataset"`
 	Width                        int  `json:"Width"`
 	Depth                        int  `json:"Depth"`
 	VSwarm                       bool `json:"VSwarm"`
 
+	// per-invocation timeouts derived from the requested runtime
+	PerFunctionTimeouts  bool    `json:"PerFunctionTimeouts"`
+	TimeoutRuntimeFactor float64 `json:"TimeoutRuntimeFactor"`
+	TimeoutSlackMs       int     `json:"TimeoutSlackMs"`
+
+	// retry and hedging policies, where the policies of individual functions are keyed by their name or hash
+	RetryPolicy           *RetryPolicy            `json:"RetryPolicy"`
+	FunctionRetryPolicies map[string]*RetryPolicy `json:"FunctionRetryPolicies"`
+
+	// checks of the responses of invocations the platform reported as successful, disabled if not set
+	ResponseValidation *ResponseValidation `json:"ResponseValidation"`
+
+	// transport security of the invocations
+	EnableTLS                 bool   `json:"EnableTLS"`
+	TLSCACertificate          string `json:"TLSCACertificate"`
+	TLSClientCertificate      string `json:"TLSClientCertificate"`
+	TLSClientKey              string `json:"TLSClientKey"`
+	TLSServerName             string `json:"TLSServerName"`
+	TLSInsecureSkipVerify     bool   `json:"TLSInsecureSkipVerify"`
+	BearerToken               string `json:"BearerToken"`
+	BearerTokenFile           string `json:"BearerTokenFile"`
+	BearerTokenRefreshSeconds int    `json:"BearerTokenRefreshSeconds"`
+
+	// invoke functions according to the trigger specified in the trace
+	TriggerSemantics   bool   `json:"TriggerSemantics"`
+	AsyncInvocationURL string `json:"AsyncInvocationURL"`
+
+	// Knative asynchronous invocations through CloudEvents
+	KnativeAsyncMode  bool   `json:"KnativeAsyncMode"`
+	KnativeBrokerName string `json:"KnativeBrokerName"`
+	KnativeBrokerURL  string `json:"KnativeBrokerURL"`
+
+	// deployment of Knative services through the Kubernetes API, where the resources of the experiment are labeled
+	// with its ID, generated if not set
+	ExperimentID               string `json:"ExperimentID"`
+	KubeconfigPath             string `json:"KubeconfigPath"`
+	KnativeReadyTimeoutSeconds int    `json:"KnativeReadyTimeoutSeconds"`
+	// ReuseDeployment Reuses the Knative services of a previous experiment found by name or experiment label instead of
+	// recreating them, patching the differences and resetting their scale, and keeps them once the experiment ends
+	ReuseDeployment bool `json:"ReuseDeployment"`
+
+	// handling of the functions that failed to deploy, aborting the experiment if not set
+	DeploymentPolicy  string `json:"DeploymentPolicy"`
+	DeploymentRetries int    `json:"DeploymentRetries"`
+
+	// receiver of the completions of asynchronous invocations pushed during the run
+	AsyncCallbackAddress          string `json:"AsyncCallbackAddress"`
+	AsyncCallbackGRPCAddress      string `json:"AsyncCallbackGRPCAddress"`
+	AsyncCallbackURL              string `json:"AsyncCallbackURL"`
+	AsyncCompletionTimeoutSeconds int    `json:"AsyncCompletionTimeoutSeconds"`
+
+	// used only if platform is openfaas or fission
+	FunctionImage    string `json:"FunctionImage"`
+	OpenFaaSGateway  string `json:"OpenFaaSGateway"`
+	OpenFaaSUser     string `json:"OpenFaaSUser"`
+	OpenFaaSPassword string `json:"OpenFaaSPassword"`
+	FissionRouter    string `json:"FissionRouter"`
+	FissionNamespace string `json:"FissionNamespace"`
+
 	// used only if platform is dirigent
 	DirigentConfigPath string `json:"DirigentConfigPath"`
 }
 
+// RetryPolicy Policy of retrying failed invocations with exponential backoff, and of hedging slow invocations with a
+// duplicate request
+type RetryPolicy struct {
+	MaxAttempts           int      `json:"MaxAttempts"`
+	RetryableErrorClasses []string `json:"RetryableErrorClasses"`
+
+	InitialBackoffMs  int     `json:"InitialBackoffMs"`
+	MaxBackoffMs      int     `json:"MaxBackoffMs"`
+	BackoffMultiplier float64 `json:"BackoffMultiplier"`
+	BackoffJitter     float64 `json:"BackoffJitter"`
+
+	HedgingPercentile float64 `json:"HedgingPercentile"`
+	HedgingMinSamples int     `json:"HedgingMinSamples"`
+}
+
+// ResponseValidation Checks by which responses served by the wrong function or with an implausible runtime are failed
+// instead of being counted as successful invocations
+type ResponseValidation struct {
+	// FunctionName requires the instance named in the reply to belong to the invoked function
+	FunctionName bool `json:"FunctionName"`
+	// RuntimeTolerance relative deviation of the reported from the requested runtime, disabled if zero
+	RuntimeTolerance   float64 `json:"RuntimeTolerance"`
+	RuntimeToleranceMs int     `json:"RuntimeToleranceMs"`
+	// BodyPatterns regular expressions the body of the response must match
+	BodyPatterns []string `json:"BodyPatterns"`
+	// BodyAssertions names of the assertions registered with the invokers the body of the response must pass
+	BodyAssertions []string `json:"BodyAssertions"`
+}
+
 type WorkflowFunction struct {
 	FunctionName string `json:"FunctionName"`
 	FunctionPath string `json:"FunctionPath"`
@@ -17,9 +17,11 @@ import (
 func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
 	const batchSize = 50
 
+	security := clients.CreateTransportSecurity(d.Configuration.LoaderConfiguration)
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 		Transport: &http.Transport{
+			TLSClientConfig: security.TLSConfig,
 			DialContext: (&net.Dialer{
 				Timeout: 2 * time.Second,
 			}).DialContext,
@@ -53,6 +55,7 @@ func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
 				record := d.AsyncRecords.Dequeue()
 				response, e2e := d.getAsyncResponseData(
 					client,
+					security,
 					d.Configuration.DirigentConfiguration.AsyncResponseURL,
 					record.AsyncResponseID,
 				)
@@ -64,6 +67,7 @@ func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
 					}
 				} else {
 					record.FunctionTimeout = true
+					record.Fail(metric.ErrorClassTimeout, 0, "response not available")
 					record.AsyncResponseID = ""
 					log.Errorf("Failed to fetch response. The function has probably not yet completed.")
 				}
@@ -87,8 +91,11 @@ func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
 	log.Infof("Finished gathering async reponse answers")
 }
 
-func (d *Driver) getAsyncResponseData(client *http.Client, endpoint string, guid string) ([]byte, int) {
-	req, err := http.NewRequest("GET", "http://"+endpoint, bytes.NewReader([]byte(guid)))
+func (d *Driver) getAsyncResponseData(client *http.Client, security *clients.TransportSecurity, endpoint string, guid string) ([]byte, int) {
+	req, err := http.NewRequest("GET", security.Scheme()+"://"+endpoint, bytes.NewReader([]byte(guid)))
+	if err == nil {
+		err = security.AuthorizeRequest(req)
+	}
 	if err != nil {
 		log.Errorf("Failed to retrieve Dirigent response for %s - %v", guid, err)
 		return []byte{}, 0
@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// unclassifiedFailure Category of the failures the invoker did not classify
+const unclassifiedFailure = "unclassified"
+
+// invocationBreakdown counts successful and failed invocations per category (e.g., trigger type)
+type invocationBreakdown struct {
+	mutex   sync.Mutex
+	success map[string]int64
+	failed  map[string]int64
+}
+
+func newInvocationBreakdown() *invocationBreakdown {
+	return &invocationBreakdown{
+		success: make(map[string]int64),
+		failed:  make(map[string]int64),
+	}
+}
+
+func (b *invocationBreakdown) Add(category string, success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if success {
+		b.success[category]++
+	} else {
+		b.failed[category]++
+	}
+}
+
+func (b *invocationBreakdown) Categories() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var categories []string
+	for category := range b.success {
+		categories = append(categories, category)
+	}
+	for category := range b.failed {
+		if _, ok := b.success[category]; !ok {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+
+	return categories
+}
+
+func (b *invocationBreakdown) Get(category string) (int64, int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.success[category], b.failed[category]
+}
+
+// failureCategory Returns the error class of the failed invocation as the category
+func failureCategory(record *metric.ExecutionRecord) string {
+	if record.ErrorClass == "" {
+		return unclassifiedFailure
+	}
+
+	return string(record.ErrorClass)
+}
+
+func (b *invocationBreakdown) Log(title string) {
+	categories := b.Categories()
+	if len(categories) == 0 {
+		return
+	}
+
+	log.Infof("%s:", title)
+	for _, category := range categories {
+		success, failed := b.Get(category)
+		log.Infof("\t%s: \t%d successful, %d failed", category, success, failed)
+	}
+}
+
+// LogFailures Logs only the failed invocations, for breakdowns in which all invocations of a category fail
+func (b *invocationBreakdown) LogFailures(title string) {
+	categories := b.Categories()
+	if len(categories) == 0 {
+		return
+	}
+
+	log.Infof("%s:", title)
+	for _, category := range categories {
+		_, failed := b.Get(category)
+		log.Infof("\t%s: \t%d", category, failed)
+	}
+}
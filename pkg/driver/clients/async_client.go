@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// asyncInvoker submits invocations of asynchronously triggered functions (e.g., queue, event, timer) to an
+// ingestion endpoint, which is responsible for delivering them to the function. Only the submission is awaited.
+type asyncInvoker struct {
+	client   *http.Client
+	security *TransportSecurity
+	url      string
+}
+
+// CreateAsyncInvoker Returns an invoker for asynchronously triggered functions, or nil if all functions should
+// be invoked through the platform invoker.
+func CreateAsyncInvoker(cfg *config.Configuration) Invoker {
+	lcfg := cfg.LoaderConfiguration
+	if !lcfg.TriggerSemantics || lcfg.AsyncInvocationURL == "" {
+		return nil
+	}
+
+	return newAsyncInvoker(lcfg)
+}
+
+func newAsyncInvoker(cfg *config.LoaderConfiguration) *asyncInvoker {
+	security := CreateTransportSecurity(cfg)
+
+	return &asyncInvoker{
+		client:   CreateHTTPClient(cfg, "http1", security.TLSConfig),
+		security: security,
+		url:      cfg.AsyncInvocationURL,
+	}
+}
+
+func (i *asyncInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke async)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s://%s", i.security.Scheme(), i.url), nil)
+	if err == nil {
+		err = i.security.AuthorizeRequest(req)
+	}
+	if err != nil {
+		log.Errorf("Failed to create an async HTTP request - %v\n", err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassRequest, err)
+
+		return false, record
+	}
+
+	req.Header.Set("function", function.Name)
+	req.Header.Set("endpoint", function.Endpoint)
+	req.Header.Set("requested_cpu", strconv.Itoa(runtimeSpec.Runtime))
+	req.Header.Set("requested_memory", strconv.Itoa(runtimeSpec.Memory))
+
+	ctx, span := tracing.StartInvocation(req.Context(), function, runtimeSpec, &record.ExecutionRecordBase)
+	defer tracing.EndInvocation(span, &record.ExecutionRecordBase)
+	req = req.WithContext(ctx)
+	tracing.InjectHTTP(ctx, req)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		log.Errorf("%s - Failed to submit an async invocation - %v\n", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassConnect, err)
+
+		return false, record
+	}
+
+	defer HandleBodyClosing(resp)
+	record.StatusCode = resp.StatusCode
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted) {
+		log.Errorf("Async invocation submission failed - %s - status code: %d", function.Name, resp.StatusCode)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.FunctionTimeout = isTimeout(err)
+		if err != nil {
+			failResponseBody(&record.ExecutionRecordBase, resp.StatusCode, err)
+		} else {
+			failHTTPStatus(&record.ExecutionRecordBase, resp.StatusCode, body)
+		}
+
+		return false, record
+	}
+
+	record.AsyncResponseID = string(body)
+	record.ResponseTime = time.Since(start).Microseconds()
+	record.TimeToSubmitMs = record.ResponseTime
+
+	log.Tracef("(Submitted)\t %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return true, record
+}
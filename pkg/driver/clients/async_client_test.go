@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestAsyncInvoker(t *testing.T) {
+	tests := []struct {
+		testName   string
+		statusCode int
+		success    bool
+	}{
+		{
+			testName:   "accepted",
+			statusCode: http.StatusAccepted,
+			success:    true,
+		},
+		{
+			testName:   "rejected",
+			statusCode: http.StatusServiceUnavailable,
+			success:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("function") != testFunction.Name {
+					t.Errorf("Unexpected function header %s", r.Header.Get("function"))
+				}
+
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte("invocation-id"))
+			}))
+			defer server.Close()
+
+			cfg := createFakeLoaderConfiguration()
+			cfg.TriggerSemantics = true
+			cfg.AsyncInvocationURL = strings.TrimPrefix(server.URL, "http://")
+
+			invoker := CreateAsyncInvoker(&config.Configuration{LoaderConfiguration: cfg})
+			if invoker == nil {
+				t.Fatal("Async invoker should have been created.")
+			}
+
+			success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
+			if success != test.success {
+				t.Errorf("Unexpected invocation outcome - got %v, expected %v", success, test.success)
+			}
+			if success && (record.AsyncResponseID != "invocation-id" || record.TimeToSubmitMs != record.ResponseTime) {
+				t.Error("Invalid async execution record.")
+			}
+		})
+	}
+}
+
+func TestAsyncInvokerDisabled(t *testing.T) {
+	cfg := createFakeLoaderConfiguration()
+	cfg.AsyncInvocationURL = "localhost:8080"
+
+	if CreateAsyncInvoker(&config.Configuration{LoaderConfiguration: cfg}) != nil {
+		t.Error("Async invoker should not be created without trigger semantics.")
+	}
+
+	if !common.IsAsyncTrigger(common.TriggerQueue) || common.IsAsyncTrigger(common.TriggerHTTP) {
+		t.Error("Unexpected trigger classification.")
+	}
+}
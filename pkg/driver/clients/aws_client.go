@@ -37,6 +37,7 @@ func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common
 	responseBody, err := io.ReadAll(res.Body)
 	if err != nil {
 		log.Debugf("Error reading response body:%s", err)
+		failResponseBody(&record.ExecutionRecordBase, res.StatusCode, err)
 		return false, record
 	}
 
@@ -46,6 +47,7 @@ func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common
 	// Unmarshal the response body into the JSON object
 	if err := json.Unmarshal(responseBody, &httpResBody); err != nil {
 		log.Debugf("Error unmarshaling JSON:%s", err)
+		record.Fail(mc.ErrorClassResponse, res.StatusCode, err.Error())
 		return false, record
 	}
 
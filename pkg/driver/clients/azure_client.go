@@ -71,6 +71,7 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failRequest(record, mc.ErrorClassRequest, err)
 
 		return false, record, nil, nil
 	}
@@ -83,16 +84,19 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failRequest(record, mc.ErrorClassConnect, err)
 
 		return false, record, resp, nil
 	}
 	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		log.Errorf("Received non-2xx status code for function %s - error code: %s", function.Name, resp.Status)
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failHTTPStatus(record, resp.StatusCode, nil)
 
 		return false, record, resp, nil
 	}
@@ -103,6 +107,7 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		failResponseBody(record, resp.StatusCode, err)
 
 		return false, record, resp, nil
 	}
@@ -114,6 +119,7 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		record.Fail(mc.ErrorClassResponse, resp.StatusCode, err.Error())
 
 		return false, record, resp, nil
 	}
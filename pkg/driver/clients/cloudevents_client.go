@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// cloudEventsInvoker posts invocations as CloudEvents to a Knative broker, which delivers them to the function through
+// a trigger. The function reports the completion to the loader-hosted completion receiver, hence only the submission is
+// awaited.
+type cloudEventsInvoker struct {
+	client      *http.Client
+	security    *TransportSecurity
+	brokerURL   string
+	callbackURL string
+}
+
+func newCloudEventsInvoker(cfg *config.LoaderConfiguration) *cloudEventsInvoker {
+	security := CreateTransportSecurity(cfg)
+
+	return &cloudEventsInvoker{
+		client:      CreateHTTPClient(cfg, "http1", security.TLSConfig),
+		security:    security,
+		brokerURL:   cfg.KnativeBrokerURL,
+		callbackURL: cfg.AsyncCallbackURL,
+	}
+}
+
+func (i *cloudEventsInvoker) createRequest(function *common.Function, runtimeSpec *common.RuntimeSpecification, eventID string) (*http.Request, error) {
+	data, err := json.Marshal(common.CloudEventInvocation{
+		Function:          function.Name,
+		RuntimeInMilliSec: uint32(runtimeSpec.Runtime),
+		MemoryInMebiBytes: uint32(runtimeSpec.Memory),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s://%s", i.security.Scheme(), i.brokerURL), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(common.CloudEventIDHeader, eventID)
+	req.Header.Set(common.CloudEventSpecVersionHeader, common.CloudEventSpecVersion)
+	req.Header.Set(common.CloudEventSourceHeader, common.CloudEventSource)
+	req.Header.Set(common.CloudEventTypeHeader, common.CloudEventInvocationType)
+	req.Header.Set(common.CloudEventFunctionHeader, function.Name)
+	req.Header.Set(common.CloudEventReplyToHeader, i.callbackURL)
+
+	return req, i.security.AuthorizeRequest(req)
+}
+
+func (i *cloudEventsInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke event)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name // overwritten upon completion
+
+	eventID := uuid.New().String()
+
+	req, err := i.createRequest(function, runtimeSpec, eventID)
+	if err != nil {
+		log.Errorf("Failed to create a CloudEvents request - %v\n", err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassRequest, err)
+
+		return false, record
+	}
+
+	ctx, span := tracing.StartInvocation(req.Context(), function, runtimeSpec, &record.ExecutionRecordBase)
+	defer tracing.EndInvocation(span, &record.ExecutionRecordBase)
+	req = req.WithContext(ctx)
+	tracing.InjectHTTP(ctx, req)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		log.Errorf("%s - Failed to post an invocation event to the broker - %v\n", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassConnect, err)
+
+		return false, record
+	}
+	body, _ := io.ReadAll(resp.Body)
+	HandleBodyClosing(resp)
+	record.StatusCode = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		log.Errorf("Invocation event rejected by the broker - %s - status code: %d", function.Name, resp.StatusCode)
+
+		// a rejected event is told apart by the error class and the status code, as the broker did not time out
+		record.ResponseTime = time.Since(start).Microseconds()
+		failHTTPStatus(&record.ExecutionRecordBase, resp.StatusCode, body)
+
+		return false, record
+	}
+
+	record.AsyncResponseID = eventID
+	record.ResponseTime = time.Since(start).Microseconds()
+	record.TimeToSubmitMs = record.ResponseTime
+
+	log.Tracef("(Submitted)\t %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return true, record
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestCloudEventsInvoker(t *testing.T) {
+	tests := []struct {
+		testName   string
+		statusCode int
+		success    bool
+	}{
+		{
+			testName:   "accepted",
+			statusCode: http.StatusAccepted,
+			success:    true,
+		},
+		{
+			testName:   "rejected",
+			statusCode: http.StatusBadRequest,
+			success:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			var eventID string
+
+			broker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				eventID = r.Header.Get(common.CloudEventIDHeader)
+
+				if r.Header.Get(common.CloudEventTypeHeader) != common.CloudEventInvocationType ||
+					r.Header.Get(common.CloudEventSpecVersionHeader) != common.CloudEventSpecVersion ||
+					r.Header.Get(common.CloudEventFunctionHeader) != testFunction.Name ||
+					r.Header.Get(common.CloudEventReplyToHeader) != "http://sink:8085" {
+
+					t.Errorf("Unexpected CloudEvent attributes %v", r.Header)
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				var invocation common.CloudEventInvocation
+				if err := json.Unmarshal(body, &invocation); err != nil || invocation.RuntimeInMilliSec != uint32(testRuntimeSpecs.Runtime) {
+					t.Errorf("Unexpected CloudEvent data %s", body)
+				}
+
+				w.WriteHeader(test.statusCode)
+			}))
+			defer broker.Close()
+
+			cfg := createFakeLoaderConfiguration()
+			cfg.KnativeAsyncMode = true
+			cfg.KnativeBrokerURL = strings.TrimPrefix(broker.URL, "http://") + "/default/default"
+			cfg.AsyncCallbackURL = "http://sink:8085"
+
+			invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+			success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
+
+			if success != test.success {
+				t.Fatalf("Unexpected invocation outcome - expected: %t; got: %t", test.success, success)
+			}
+			if test.success && (eventID == "" || record.AsyncResponseID != eventID || record.TimeToSubmitMs != record.ResponseTime) {
+				t.Error("The record should be correlated with the invocation event.")
+			}
+			if !test.success && (record.ErrorClass == "" || record.FunctionTimeout) {
+				t.Errorf("Rejected invocation events should be classified by their status code %+v", record.ExecutionRecordBase)
+			}
+		})
+	}
+}
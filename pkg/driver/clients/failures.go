@@ -0,0 +1,85 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpErrorClass Classifies a failed invocation by the HTTP status code of the response
+func httpErrorClass(statusCode int) mc.ErrorClass {
+	switch {
+	case statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable:
+		return mc.ErrorClassOverload
+	case statusCode == http.StatusNotFound:
+		return mc.ErrorClassNotFound
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return mc.ErrorClassUnauthorized
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		return mc.ErrorClassTimeout
+	case statusCode >= 400 && statusCode < 500:
+		return mc.ErrorClassClient
+	default:
+		return mc.ErrorClassServer
+	}
+}
+
+// grpcErrorClass Classifies a failed RPC by its status code
+func grpcErrorClass(code codes.Code) mc.ErrorClass {
+	switch code {
+	case codes.DeadlineExceeded:
+		return mc.ErrorClassTimeout
+	case codes.ResourceExhausted, codes.Unavailable:
+		return mc.ErrorClassOverload
+	case codes.NotFound, codes.Unimplemented:
+		return mc.ErrorClassNotFound
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return mc.ErrorClassUnauthorized
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange, codes.AlreadyExists, codes.Canceled:
+		return mc.ErrorClassClient
+	default:
+		return mc.ErrorClassServer
+	}
+}
+
+// errorMessage Returns the message of the error without the URL of the request, so that the same failure of different
+// functions hashes the same
+func errorMessage(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+// failRequest Records a failure to create or send a request, before any response was received
+func failRequest(record *mc.ExecutionRecordBase, class mc.ErrorClass, err error) {
+	record.Fail(class, 0, errorMessage(err))
+}
+
+// failHTTPStatus Records a response with an unexpected status code
+func failHTTPStatus(record *mc.ExecutionRecordBase, statusCode int, body []byte) {
+	record.Fail(httpErrorClass(statusCode), statusCode, fmt.Sprintf("%d %s", statusCode, body))
+}
+
+// failResponseBody Records a failure to read the body of the response
+func failResponseBody(record *mc.ExecutionRecordBase, statusCode int, err error) {
+	class := mc.ErrorClassResponse
+	if isTimeout(err) {
+		class = mc.ErrorClassTimeout
+	}
+
+	record.Fail(class, statusCode, errorMessage(err))
+}
+
+// failGRPC Records a failed RPC
+func failGRPC(record *mc.ExecutionRecordBase, err error) {
+	s := status.Convert(err)
+	record.Fail(grpcErrorClass(s.Code()), int(s.Code()), s.Message())
+}
@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		testName   string
+		statusCode int
+		grpcCode   codes.Code
+		expected   mc.ErrorClass
+	}{
+		{testName: "overload", statusCode: http.StatusServiceUnavailable, grpcCode: codes.Unavailable, expected: mc.ErrorClassOverload},
+		{testName: "throttled", statusCode: http.StatusTooManyRequests, grpcCode: codes.ResourceExhausted, expected: mc.ErrorClassOverload},
+		{testName: "not_found", statusCode: http.StatusNotFound, grpcCode: codes.Unimplemented, expected: mc.ErrorClassNotFound},
+		{testName: "unauthorized", statusCode: http.StatusForbidden, grpcCode: codes.Unauthenticated, expected: mc.ErrorClassUnauthorized},
+		{testName: "timeout", statusCode: http.StatusGatewayTimeout, grpcCode: codes.DeadlineExceeded, expected: mc.ErrorClassTimeout},
+		{testName: "client", statusCode: http.StatusBadRequest, grpcCode: codes.InvalidArgument, expected: mc.ErrorClassClient},
+		{testName: "server", statusCode: http.StatusInternalServerError, grpcCode: codes.Internal, expected: mc.ErrorClassServer},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			if class := httpErrorClass(test.statusCode); class != test.expected {
+				t.Errorf("Unexpected class of HTTP status %d - expected: %s; got: %s", test.statusCode, test.expected, class)
+			}
+
+			record := &mc.ExecutionRecordBase{}
+			failGRPC(record, status.Error(test.grpcCode, "failed"))
+			if record.ErrorClass != test.expected || record.StatusCode != int(test.grpcCode) || record.ErrorHash == "" {
+				t.Errorf("Unexpected classification of gRPC code %s %+v", test.grpcCode, record)
+			}
+		})
+	}
+}
+
+func TestHTTPInvokerFailureClassification(t *testing.T) {
+	tests := []struct {
+		testName   string
+		platform   string
+		statusCode int
+		expected   mc.ErrorClass
+	}{
+		{testName: "success", platform: common.PlatformDirigent, statusCode: http.StatusOK},
+		{testName: "overload", platform: common.PlatformDirigent, statusCode: http.StatusServiceUnavailable, expected: mc.ErrorClassOverload},
+		{testName: "not_found", platform: common.PlatformDirigent, statusCode: http.StatusNotFound, expected: mc.ErrorClassNotFound},
+		{testName: "gateway_success", platform: common.PlatformOpenFaaS, statusCode: http.StatusOK},
+		{testName: "gateway_throttled", platform: common.PlatformOpenFaaS, statusCode: http.StatusTooManyRequests, expected: mc.ErrorClassOverload},
+		{testName: "gateway_not_found", platform: common.PlatformOpenFaaS, statusCode: http.StatusNotFound, expected: mc.ErrorClassNotFound},
+		{testName: "gateway_timeout", platform: common.PlatformOpenFaaS, statusCode: http.StatusGatewayTimeout, expected: mc.ErrorClassTimeout},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = fmt.Fprintf(w, `{"Function": "instance-1", "hostname": "instance-1"}`)
+			}))
+			defer server.Close()
+
+			cfg := createFakeLoaderConfiguration()
+			cfg.Platform = test.platform
+			cfg.InvokeProtocol = "http1"
+
+			function := testFunction
+			function.Endpoint = strings.TrimPrefix(server.URL, "http://")
+			function.DirigentMetadata = &common.DirigentMetadata{}
+
+			invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg, DirigentConfiguration: &config.DirigentConfig{}}, nil, nil)
+			success, record := invoker.Invoke(&function, &testRuntimeSpecs)
+
+			if success != (test.expected == "") || record.ErrorClass != test.expected || record.StatusCode != test.statusCode {
+				t.Errorf("Unexpected outcome - success: %t; class: '%s'; status code: %d", success, record.ErrorClass, record.StatusCode)
+			}
+			if record.FunctionTimeout {
+				t.Error("Failed responses should not be marked as timed out.")
+			}
+		})
+	}
+}
+
+func TestConnectFailureClassification(t *testing.T) {
+	cfg := createFakeLoaderConfiguration()
+	cfg.Platform = common.PlatformFission
+
+	function := testFunction
+	function.Endpoint = "localhost:1"
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	success, record := invoker.Invoke(&function, &testRuntimeSpecs)
+
+	if success || record.ErrorClass != mc.ErrorClassConnect || !record.ConnectionTimeout || record.ErrorHash == "" {
+		t.Errorf("Unexpected classification of a refused connection %+v", record.ExecutionRecordBase)
+	}
+}
@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// gatewayInvoker invokes functions synchronously through the HTTP gateway of the platform, i.e., the OpenFaaS gateway
+// (<gateway>/function/<name>) or the Fission router (<router>/<name>). The endpoint of each function is set by the
+// deployer, and the trace function serves plain HTTP invocations behind it.
+type gatewayInvoker struct {
+	cfg       *config.LoaderConfiguration
+	client    *http.Client
+	security  *TransportSecurity
+	validator *responseValidator
+	// basic authentication, required by the OpenFaaS gateway when configured
+	user     string
+	password string
+}
+
+func newGatewayInvoker(cfg *config.LoaderConfiguration) *gatewayInvoker {
+	security := CreateTransportSecurity(cfg)
+
+	invoker := &gatewayInvoker{
+		cfg:       cfg,
+		client:    CreateHTTPClient(cfg, "http1", security.TLSConfig),
+		security:  security,
+		validator: newResponseValidator(cfg),
+	}
+	if cfg.Platform == common.PlatformOpenFaaS {
+		invoker.user, invoker.password = cfg.OpenFaaSUser, cfg.OpenFaaSPassword
+	}
+
+	return invoker
+}
+
+func (i *gatewayInvoker) createRequest(function *common.Function, runtimeSpec *common.RuntimeSpecification) (*http.Request, error) {
+	data, err := json.Marshal(common.HTTPInvocation{
+		Function:          function.Name,
+		RuntimeInMilliSec: uint32(runtimeSpec.Runtime),
+		MemoryInMebiBytes: uint32(runtimeSpec.Memory),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s://%s", i.security.Scheme(), function.Endpoint), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if i.user != "" {
+		req.SetBasicAuth(i.user, i.password)
+	}
+
+	return req, i.security.AuthorizeRequest(req)
+}
+
+func (i *gatewayInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name // overwritten by the hostname of the instance
+
+	req, err := i.createRequest(function, runtimeSpec)
+	if err != nil {
+		log.Errorf("Failed to create a HTTP request - %v\n", err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassRequest, err)
+
+		return false, record
+	}
+
+	req, phases, cancel := withInvocationDeadline(req, computeInvocationTimeouts(i.cfg, runtimeSpec))
+	defer cancel()
+
+	ctx, span := tracing.StartInvocation(req.Context(), function, runtimeSpec, &record.ExecutionRecordBase)
+	defer tracing.EndInvocation(span, &record.ExecutionRecordBase)
+	req = req.WithContext(ctx)
+	tracing.InjectHTTP(ctx, req)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		log.Errorf("%s - Failed to send an HTTP request to the gateway - %v\n", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		recordHTTPFailure(record, phases, err)
+
+		return false, record
+	}
+	defer HandleBodyClosing(resp)
+	record.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		// the gateways respond with 502/504 when the function times out, and with 429 when throttling
+		log.Errorf("HTTP request failed - %s - %s - response: %s - status code: %d", function.Name, function.Endpoint, string(body), resp.StatusCode)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.FunctionTimeout = isTimeout(err)
+		record.TimeoutPhase = phases.timeoutPhase(err)
+		if err != nil {
+			failResponseBody(&record.ExecutionRecordBase, resp.StatusCode, err)
+		} else {
+			failHTTPStatus(&record.ExecutionRecordBase, resp.StatusCode, body)
+		}
+
+		return false, record
+	}
+
+	var reply common.HTTPInvocationReply
+	if err = json.Unmarshal(body, &reply); err != nil {
+		log.Warnf("Failed to deserialize the response of %s - %v - %v", function.Name, string(body), err)
+	} else {
+		record.Instance = reply.Hostname
+		record.ActualDuration = reply.DurationInMicroSec
+		record.ActualMemoryUsage = common.Kib2Mib(reply.MemoryUsageInKb)
+	}
+
+	record.ResponseTime = time.Since(start).Microseconds()
+
+	log.Tracef("(Replied)\t %s: %s, %.2f[ms], %d[MiB]", function.Name, reply.Message, float64(reply.DurationInMicroSec)/1e3, common.Kib2Mib(reply.MemoryUsageInKb))
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return i.validator.Check(function, record, body), record
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestGatewayInvoker(t *testing.T) {
+	tests := []struct {
+		testName   string
+		platform   string
+		path       string
+		statusCode int
+		success    bool
+	}{
+		{testName: "openfaas", platform: common.PlatformOpenFaaS, path: "/function/" + testFunction.Name, statusCode: http.StatusOK, success: true},
+		{testName: "fission", platform: common.PlatformFission, path: "/" + testFunction.Name, statusCode: http.StatusOK, success: true},
+		{testName: "gateway_timeout", platform: common.PlatformOpenFaaS, path: "/function/" + testFunction.Name, statusCode: http.StatusGatewayTimeout, success: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				if r.URL.Path != test.path || (test.platform == common.PlatformOpenFaaS) != ok ||
+					(ok && (user != "admin" || password != "secret")) {
+
+					t.Errorf("Unexpected request %s %v", r.URL.Path, r.Header)
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				var invocation common.HTTPInvocation
+				if err := json.Unmarshal(body, &invocation); err != nil || invocation.RuntimeInMilliSec != uint32(testRuntimeSpecs.Runtime) {
+					t.Errorf("Unexpected invocation %s", body)
+				}
+
+				w.WriteHeader(test.statusCode)
+				_ = json.NewEncoder(w).Encode(common.HTTPInvocationReply{
+					Hostname:           "instance-1",
+					DurationInMicroSec: 1500,
+					MemoryUsageInKb:    4096,
+				})
+			}))
+			defer gateway.Close()
+
+			cfg := createFakeLoaderConfiguration()
+			cfg.Platform = test.platform
+			cfg.OpenFaaSUser, cfg.OpenFaaSPassword = "admin", "secret"
+
+			function := testFunction
+			function.Endpoint = strings.TrimPrefix(gateway.URL, "http://") + test.path
+
+			invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+			success, record := invoker.Invoke(&function, &testRuntimeSpecs)
+
+			if success != test.success {
+				t.Fatalf("Unexpected invocation outcome - expected: %t; got: %t", test.success, success)
+			}
+			if test.success && (record.Instance != "instance-1" || record.ActualDuration != 1500 || record.ActualMemoryUsage != 4) {
+				t.Errorf("Unexpected record %+v", record)
+			}
+			if !test.success && (record.ErrorClass != mc.ErrorClassTimeout || record.FunctionTimeout) {
+				t.Errorf("Failed responses should be classified by their status code %+v", record.ExecutionRecordBase)
+			}
+		})
+	}
+}
@@ -34,21 +34,22 @@ import (
 	helloworld "github.com/vhive-serverless/vSwarm/utils/protobuf/helloworld"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"strings"
 	"time"
 
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
+// invoker Issues the RPC of a gRPC function, returning whether it succeeded and the message of the reply
 type invoker interface {
-	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool
+	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) (bool, string)
 }
 
 type ExecutorRPC struct {
 }
 
-func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) (bool, string) {
 	grpcClient := proto.NewExecutorClient(conn)
 
 	response, err := grpcClient.Execute(executionCxt, &proto.FaasRequest{
@@ -62,8 +63,9 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 
 		record.ConnectionTimeout = true // WithBlock deprecated in new gRPC interface
 		record.FunctionTimeout = true
+		failGRPC(&record.ExecutionRecordBase, err)
 
-		return false
+		return false, ""
 	}
 
 	record.Instance = extractInstanceName(response.GetMessage())
@@ -78,13 +80,13 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 	logrus.Tracef("(Replied)\t %s: %s, %.2f[ms], %d[MiB]", function.Name, response.Message,
 		float64(response.DurationInMicroSec)/1e3, common.Kib2Mib(response.MemoryUsageInKb))
 
-	return true
+	return true, response.GetMessage()
 }
 
 type SayHelloRPC struct {
 }
 
-func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) (bool, string) {
 	grpcClient := helloworld.NewGreeterClient(conn)
 	response, err := grpcClient.SayHello(executionCxt, &helloworld.HelloRequest{
 		Name: "Invoke Relay",
@@ -98,25 +100,45 @@ func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 		logrus.Debugf("gRPC timeout exceeded for function %s - %s", function.Name, err)
 		record.ConnectionTimeout = true
 		record.FunctionTimeout = true
+		failGRPC(&record.ExecutionRecordBase, err)
 
-		return false
+		return false, ""
 	}
 	record.ActualDuration = 0
 	record.Instance = extractSwarmFunction(response.GetMessage())
 	record.ActualMemoryUsage = common.Kib2Mib(0) //Memory usage may not be available for all vSwarm benchmarks
 
-	return true
+	return true, response.GetMessage()
 }
 
 type grpcInvoker struct {
-	cfg     *config.LoaderConfiguration
-	invoker invoker
+	cfg       *config.LoaderConfiguration
+	invoker   invoker
+	security  *TransportSecurity
+	validator *responseValidator
+	// nil if a new connection is dialed for each invocation
+	pool *grpcConnectionPool
 }
 
 func newGRPCInvoker(cfg *config.LoaderConfiguration, invoker invoker) *grpcInvoker {
+	var pool *grpcConnectionPool
+	if cfg.GRPCConnectionPooling {
+		pool = newGRPCConnectionPool(cfg.GRPCPoolSize, time.Duration(cfg.GRPCPoolIdleTimeoutSeconds)*time.Second)
+	}
+
 	return &grpcInvoker{
-		cfg:     cfg,
-		invoker: invoker,
+		cfg:       cfg,
+		invoker:   invoker,
+		security:  CreateTransportSecurity(cfg),
+		validator: newResponseValidator(cfg),
+		pool:      pool,
+	}
+}
+
+// Close Closes the pooled connections, if any, once the invocations are done
+func (i *grpcInvoker) Close() {
+	if i.pool != nil {
+		i.pool.Close()
 	}
 }
 
@@ -135,37 +157,81 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
 
-	var dialOptions []grpc.DialOption
-	dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if strings.Contains(i.cfg.Platform, common.PlatformDirigent) {
-		dialOptions = append(dialOptions, grpc.WithAuthority(function.Name)) // Dirigent specific
-	}
-	if i.cfg.EnableZipkinTracing {
-		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
-	}
-
 	grpcStart := time.Now()
 
-	conn, err := grpc.NewClient("passthrough:///" + function.Endpoint, dialOptions...)
+	conn, release, err := i.connect(function, record)
 	if err != nil {
 		logrus.Debugf("Failed to establish a gRPC connection - %v\n", err)
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassConnect, err)
 
 		return false, record
 	}
-	defer gRPCConnectionClose(conn)
+	defer release()
 
-	record.GRPCConnectionEstablishTime = time.Since(grpcStart).Microseconds()
-	executionCxt, cancelExecution := context.WithTimeout(context.Background(), time.Duration(i.cfg.GRPCFunctionTimeoutSeconds)*time.Second)
+	// the total timeout bounds the whole invocation, including establishing the connection
+	timeouts := computeInvocationTimeouts(i.cfg, runtimeSpec)
+	executionCxt, cancelExecution := context.WithDeadline(context.Background(), start.Add(timeouts.Total))
 	defer cancelExecution()
-	success := i.invoker.Invoke(function, runtimeSpec, conn, record, executionCxt)
+
+	if err = waitForConnection(executionCxt, conn, timeouts.Connect); err != nil {
+		logrus.Debugf("Failed to establish a gRPC connection - %v\n", err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		if isTimeout(err) {
+			record.TimeoutPhase = mc.TimeoutPhaseConnect
+		}
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassConnect, err)
+
+		return false, record
+	}
+
+	record.GRPCConnectionEstablishTime = time.Since(grpcStart).Microseconds()
+	executionCxt, span := tracing.StartInvocation(executionCxt, function, runtimeSpec, &record.ExecutionRecordBase)
+	defer tracing.EndInvocation(span, &record.ExecutionRecordBase)
+	success, reply := i.invoker.Invoke(function, runtimeSpec, conn, record, executionCxt)
+	if !success {
+		record.TimeoutPhase = grpcTimeoutPhase(executionCxt.Err())
+	} else {
+		success = i.validator.Check(function, record, []byte(reply))
+	}
 	record.ResponseTime = time.Since(start).Microseconds()
 	logrus.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 	return success, record
 }
 
+// connect Returns a connection to the function, taken from the pool if pooling is enabled, and the function releasing
+// it once the invocation is done
+func (i *grpcInvoker) connect(function *common.Function, record *mc.ExecutionRecord) (*grpc.ClientConn, func(), error) {
+	key := grpcPoolKey{endpoint: function.Endpoint}
+
+	dialOptions := i.security.GRPCDialOptions()
+	if strings.Contains(i.cfg.Platform, common.PlatformDirigent) {
+		dialOptions = append(dialOptions, grpc.WithAuthority(function.Name)) // Dirigent specific
+		key.authority = function.Name
+	}
+	if tracing.Enabled(i.cfg) {
+		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+
+	dial := func() (*grpc.ClientConn, error) {
+		return grpc.NewClient("passthrough:///"+function.Endpoint, dialOptions...)
+	}
+
+	if i.pool == nil {
+		conn, err := dial()
+		return conn, func() { gRPCConnectionClose(conn) }, err
+	}
+
+	conn, reused, err := i.pool.Get(key, dial)
+	record.ConnectionReused = reused
+
+	return conn, func() { i.pool.Release(key, conn) }, err
+}
+
 func extractInstanceName(data string) string {
 	indexOfHyphen := strings.LastIndex(data, common.FunctionNamePrefix)
 	if indexOfHyphen == -1 {
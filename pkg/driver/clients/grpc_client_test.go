@@ -188,3 +188,29 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCClientWithConnectionPooling(t *testing.T) {
+	address, port := "localhost", 18083
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+
+	// make sure that the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	cfg.GRPCConnectionPooling = true
+	cfg.GRPCPoolSize = 2
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	defer invoker.(Closer).Close()
+
+	for i := 0; i < 4; i++ {
+		success, record := invoker.Invoke(&function, &testRuntimeSpecs)
+
+		if !success || record.ConnectionTimeout || record.ConnectionReused != (i >= cfg.GRPCPoolSize) {
+			t.Errorf("Unexpected pooled invocation %d - success: %t, reused: %t", i, success, record.ConnectionReused)
+		}
+	}
+}
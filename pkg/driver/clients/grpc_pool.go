@@ -0,0 +1,224 @@
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// grpcPoolKey Connections are shared only between invocations with the same endpoint and authority, as Dirigent
+// routes requests by the authority header
+type grpcPoolKey struct {
+	endpoint  string
+	authority string
+}
+
+type grpcPoolEntry struct {
+	connections []*grpc.ClientConn
+	next        int
+	lastUsed    time.Time
+}
+
+// grpcConnectionPool Keeps up to size connections per key, which are handed out round-robin and must be released once
+// the invocation is done. Connections in transient failure or shut down are replaced upon retrieval, and keys that
+// have not been used for idleTimeout are evicted. Connections are only closed once no invocation uses them, or when the
+// pool is closed.
+type grpcConnectionPool struct {
+	mutex       sync.Mutex
+	size        int
+	idleTimeout time.Duration
+	entries     map[grpcPoolKey]*grpcPoolEntry
+
+	// users Number of invocations using each connection
+	users map[*grpc.ClientConn]int
+	// retired Connections replaced in the pool, which are closed once released by their last user
+	retired map[*grpc.ClientConn]struct{}
+
+	stopCh chan struct{}
+	// evictionDone is closed once the eviction stopped, or right away if idle keys are not evicted
+	evictionDone chan struct{}
+	closeOnce    sync.Once
+}
+
+func newGRPCConnectionPool(size int, idleTimeout time.Duration) *grpcConnectionPool {
+	pool := &grpcConnectionPool{
+		size:        max(size, 1),
+		idleTimeout: idleTimeout,
+		entries:     make(map[grpcPoolKey]*grpcPoolEntry),
+		users:       make(map[*grpc.ClientConn]int),
+		retired:     make(map[*grpc.ClientConn]struct{}),
+
+		stopCh:       make(chan struct{}),
+		evictionDone: make(chan struct{}),
+	}
+
+	if idleTimeout > 0 {
+		go pool.runEviction()
+	} else {
+		close(pool.evictionDone)
+	}
+
+	return pool
+}
+
+// Close Stops the eviction and closes all the connections of the pool, which must no longer be in use
+func (p *grpcConnectionPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stopCh)
+		<-p.evictionDone
+
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		for key, entry := range p.entries {
+			for _, conn := range entry.connections {
+				gRPCConnectionClose(conn)
+			}
+			delete(p.entries, key)
+		}
+		for conn := range p.retired {
+			gRPCConnectionClose(conn)
+			delete(p.retired, conn)
+		}
+	})
+}
+
+// Get Returns a connection for the key, dialing a new one if the pool for the key is not full or the selected
+// connection is unhealthy. The second return value indicates whether an existing connection was reused. The
+// connection must be released once the invocation is done.
+func (p *grpcConnectionPool) Get(key grpcPoolKey, dial func() (*grpc.ClientConn, error)) (*grpc.ClientConn, bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &grpcPoolEntry{}
+		p.entries[key] = entry
+	}
+	entry.lastUsed = time.Now()
+
+	if len(entry.connections) < p.size {
+		conn, err := dial()
+		if err != nil {
+			return nil, false, err
+		}
+
+		entry.connections = append(entry.connections, conn)
+		p.users[conn]++
+		return conn, false, nil
+	}
+
+	index := entry.next % len(entry.connections)
+	entry.next++
+
+	conn := entry.connections[index]
+	if !isHealthy(conn) {
+		logrus.Debugf("Replacing unhealthy gRPC connection to %s (state: %s)", key.endpoint, conn.GetState())
+		p.retire(conn)
+
+		conn, err := dial()
+		if err != nil {
+			entry.connections = append(entry.connections[:index], entry.connections[index+1:]...)
+			return nil, false, err
+		}
+
+		entry.connections[index] = conn
+		p.users[conn]++
+		return conn, false, nil
+	}
+
+	p.users[conn]++
+	return conn, true, nil
+}
+
+// Release Marks the connection retrieved for the key as no longer used by the invocation, closing it if it was
+// replaced in the meantime and this was its last user
+func (p *grpcConnectionPool) Release(key grpcPoolKey, conn *grpc.ClientConn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if entry, ok := p.entries[key]; ok {
+		entry.lastUsed = time.Now()
+	}
+
+	p.users[conn]--
+	if p.users[conn] > 0 {
+		return
+	}
+	delete(p.users, conn)
+
+	if _, ok := p.retired[conn]; ok {
+		delete(p.retired, conn)
+		gRPCConnectionClose(conn)
+	}
+}
+
+// retire Closes the connection removed from the pool, or defers closing it until released if it is in use
+func (p *grpcConnectionPool) retire(conn *grpc.ClientConn) {
+	if p.users[conn] > 0 {
+		p.retired[conn] = struct{}{}
+		return
+	}
+
+	gRPCConnectionClose(conn)
+}
+
+// inUse Tells whether any of the connections is used by an invocation
+func (p *grpcConnectionPool) inUse(connections []*grpc.ClientConn) bool {
+	for _, conn := range connections {
+		if p.users[conn] > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isHealthy(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
+// evictIdle Closes the connections of all keys unused since the given time and returns the number of evicted keys.
+// Keys with connections still in use, e.g., by invocations running for longer than the idle timeout, are kept.
+func (p *grpcConnectionPool) evictIdle(unusedSince time.Time) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	evicted := 0
+	for key, entry := range p.entries {
+		if entry.lastUsed.After(unusedSince) || p.inUse(entry.connections) {
+			continue
+		}
+
+		for _, conn := range entry.connections {
+			gRPCConnectionClose(conn)
+		}
+
+		delete(p.entries, key)
+		evicted++
+	}
+
+	return evicted
+}
+
+func (p *grpcConnectionPool) runEviction() {
+	defer close(p.evictionDone)
+
+	ticker := time.NewTicker(p.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if evicted := p.evictIdle(time.Now().Add(-p.idleTimeout)); evicted > 0 {
+				logrus.Debugf("Evicted idle gRPC connections to %d endpoint(s).", evicted)
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
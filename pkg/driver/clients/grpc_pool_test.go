@@ -0,0 +1,137 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestGRPCConnectionPool(t *testing.T) {
+	pool := newGRPCConnectionPool(2, 0)
+
+	dialed := 0
+	dial := func() (*grpc.ClientConn, error) {
+		dialed++
+		return grpc.NewClient("passthrough:///localhost:18099", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	key := grpcPoolKey{endpoint: "localhost:18099"}
+	var connections []*grpc.ClientConn
+	for i := 0; i < 4; i++ {
+		conn, reused, err := pool.Get(key, dial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reused != (i >= 2) {
+			t.Errorf("Unexpected reuse of connection %d.", i)
+		}
+
+		connections = append(connections, conn)
+	}
+
+	if dialed != 2 || connections[0] != connections[2] || connections[1] != connections[3] {
+		t.Errorf("Connections should be handed out round-robin - dialed %d times.", dialed)
+	}
+	for _, conn := range connections {
+		pool.Release(key, conn)
+	}
+
+	// a different authority should not share the connections of the endpoint
+	authorityKey := grpcPoolKey{endpoint: key.endpoint, authority: "function"}
+	conn, reused, _ := pool.Get(authorityKey, dial)
+	if reused || dialed != 3 {
+		t.Error("Connections should be pooled per endpoint and authority.")
+	}
+	pool.Release(authorityKey, conn)
+
+	// shut down connections are replaced
+	gRPCConnectionClose(connections[0])
+	conn, reused, _ = pool.Get(key, dial)
+	if reused || conn == connections[0] || dialed != 4 {
+		t.Error("Unhealthy connections should be replaced.")
+	}
+	pool.Release(key, conn)
+
+	if evicted := pool.evictIdle(time.Now().Add(-time.Hour)); evicted != 0 {
+		t.Errorf("No connections should be evicted, yet %d were.", evicted)
+	}
+	if evicted := pool.evictIdle(time.Now()); evicted != 2 || len(pool.entries) != 0 {
+		t.Errorf("All connections should be evicted, yet %d were.", evicted)
+	}
+}
+
+func TestGRPCConnectionPoolInUse(t *testing.T) {
+	pool := newGRPCConnectionPool(1, 0)
+
+	// nothing listens on the endpoint, so the connection ends up in transient failure once it tries to connect
+	dial := func() (*grpc.ClientConn, error) {
+		return grpc.NewClient("passthrough:///localhost:18098", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	key := grpcPoolKey{endpoint: "localhost:18098"}
+	conn, _, err := pool.Get(key, dial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if evicted := pool.evictIdle(time.Now()); evicted != 0 {
+		t.Error("Connections in use should not be evicted.")
+	}
+
+	conn.Connect()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != connectivity.TransientFailure; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatalf("The connection did not fail - state: %s", state)
+		}
+	}
+
+	replacement, _, _ := pool.Get(key, dial)
+	if replacement == conn || conn.GetState() == connectivity.Shutdown {
+		t.Error("Unhealthy connections in use should be replaced without being closed.")
+	}
+
+	pool.Release(key, conn)
+	if conn.GetState() != connectivity.Shutdown {
+		t.Error("Replaced connections should be closed once released by their last user.")
+	}
+
+	pool.Release(key, replacement)
+	if evicted := pool.evictIdle(time.Now()); evicted != 1 || len(pool.users) != 0 || len(pool.retired) != 0 {
+		t.Errorf("Released connections should be evicted - evicted %d.", evicted)
+	}
+}
+
+func TestGRPCConnectionPoolClose(t *testing.T) {
+	pool := newGRPCConnectionPool(1, time.Hour)
+
+	dial := func() (*grpc.ClientConn, error) {
+		return grpc.NewClient("passthrough:///localhost:18097", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	key := grpcPoolKey{endpoint: "localhost:18097"}
+	conn, _, err := pool.Get(key, dial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Release(key, conn)
+
+	pool.Close()
+
+	select {
+	case <-pool.evictionDone:
+	default:
+		t.Error("The eviction should be stopped once the pool is closed.")
+	}
+	if conn.GetState() != connectivity.Shutdown || len(pool.entries) != 0 {
+		t.Errorf("The pooled connections should be closed - state: %s", conn.GetState())
+	}
+
+	// closing the pool again has no effect
+	pool.Close()
+}
@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/tracing"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 	"io"
 	"mime/multipart"
@@ -27,8 +28,10 @@ type FunctionResponse struct {
 
 type httpInvoker struct {
 	client      *http.Client
+	security    *TransportSecurity
 	loaderCfg   *config.LoaderConfiguration
 	dirigentCfg *config.DirigentConfig
+	validator   *responseValidator
 
 	isKnative   bool
 	isDandelion bool
@@ -39,10 +42,14 @@ func newHTTPInvoker(cfg *config.Configuration) *httpInvoker {
 	lcfg := cfg.LoaderConfiguration
 	dcfg := cfg.DirigentConfiguration
 
+	security := CreateTransportSecurity(lcfg)
+
 	return &httpInvoker{
-		client:      CreateHTTPClient(lcfg.GRPCFunctionTimeoutSeconds, lcfg.InvokeProtocol),
+		client:      CreateHTTPClient(lcfg, lcfg.InvokeProtocol, security.TLSConfig),
+		security:    security,
 		loaderCfg:   lcfg,
 		dirigentCfg: dcfg,
+		validator:   newResponseValidator(lcfg),
 
 		isKnative:   strings.Contains(strings.ToLower(lcfg.Platform), common.PlatformKnative),
 		isDandelion: strings.Contains(strings.ToLower(dcfg.Backend), common.BackendDandelion),
@@ -114,7 +121,7 @@ func (i *httpInvoker) functionInvocationRequest(function *common.Function, runti
 		}
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("http://%s", function.Endpoint), requestBody)
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s://%s", i.security.Scheme(), function.Endpoint), requestBody)
 	if err != nil {
 		log.Errorf("Failed to create a HTTP request - %v\n", err)
 		return nil
@@ -149,7 +156,7 @@ func (i *httpInvoker) workflowInvocationRequest(wf *common.Function) *http.Reque
 
 	// create request
 	reqBody := bytes.NewBufferString(wf.WorkflowMetadata.InvocationRequest)
-	req, err := http.NewRequest("POST", fmt.Sprintf("http://%s/workflow", wf.Endpoint), reqBody)
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s://%s/workflow", i.security.Scheme(), wf.Endpoint), reqBody)
 	if err != nil {
 		log.Errorf("Failed to create a HTTP request - %v\n", err)
 		return nil
@@ -185,21 +192,41 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	if req == nil {
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		record.Fail(mc.ErrorClassRequest, 0, "failed to create the request")
 		return false, record
 	}
 
+	if err := i.security.AuthorizeRequest(req); err != nil {
+		log.Errorf("%s - Failed to authorize an HTTP request - %v\n", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassRequest, err)
+
+		return false, record
+	}
+
+	req, phases, cancel := withInvocationDeadline(req, computeInvocationTimeouts(i.loaderCfg, runtimeSpec))
+	defer cancel()
+
+	ctx, span := tracing.StartInvocation(req.Context(), function, runtimeSpec, &record.ExecutionRecordBase)
+	defer tracing.EndInvocation(span, &record.ExecutionRecordBase)
+	req = req.WithContext(ctx)
+	tracing.InjectHTTP(ctx, req)
+
 	// send request
 	resp, err := i.client.Do(req)
 	if err != nil {
 		log.Errorf("%s - Failed to send an HTTP request to the server - %v\n", function.Name, err)
 
 		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+		recordHTTPFailure(record, phases, err)
 
 		return false, record
 	}
 
 	record.GRPCConnectionEstablishTime = time.Since(start).Microseconds()
+	record.StatusCode = resp.StatusCode
 
 	defer HandleBodyClosing(resp)
 	body, err := io.ReadAll(resp.Body)
@@ -214,7 +241,16 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 		}
 
 		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
+		// failed responses are told apart by the error class and the status code, while only deadlines time out
+		record.FunctionTimeout = isTimeout(err)
+		record.TimeoutPhase = phases.timeoutPhase(err)
+		if err != nil {
+			failResponseBody(&record.ExecutionRecordBase, resp.StatusCode, err)
+		} else if resp.StatusCode != http.StatusOK {
+			failHTTPStatus(&record.ExecutionRecordBase, resp.StatusCode, body)
+		} else {
+			record.Fail(mc.ErrorClassResponse, resp.StatusCode, "empty response")
+		}
 
 		return false, record
 	}
@@ -244,7 +280,12 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	log.Tracef("(Replied)\t %s: %s, %.2f[ms], %d[MiB]", function.Name, string(body), float64(0)/1e3, common.Kib2Mib(0))
 	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 
-	return true, record
+	// asynchronous submissions reply with the identifier of the invocation rather than the response
+	if i.dirigentCfg.AsyncMode {
+		return true, record
+	}
+
+	return i.validator.Check(function, record, body), record
 }
 
 func DeserializeDirigentResponse(body []byte, record *mc.ExecutionRecord) error {
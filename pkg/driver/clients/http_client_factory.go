@@ -4,22 +4,25 @@ import (
 	"context"
 	"crypto/tls"
 	"github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"golang.org/x/net/http2"
 	"net"
 	"net/http"
 	"time"
 )
 
-func CreateHTTPClient(timeout int, invokeProtocol string) *http.Client {
+// CreateHTTPClient Creates a client for the given protocol. Requests are sent over TLS if tlsConfig is not nil. The
+// function timeout bounds each request, whereas connections are established within the connect timeout.
+func CreateHTTPClient(cfg *config.LoaderConfiguration, invokeProtocol string, tlsConfig *tls.Config) *http.Client {
 	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
+		Timeout: time.Duration(cfg.GRPCFunctionTimeoutSeconds) * time.Second,
 	}
 
 	switch invokeProtocol {
 	case "http1":
-		client.Transport = getHttp1Transport(timeout)
+		client.Transport = getHttp1Transport(connectTimeout(cfg), tlsConfig)
 	case "http2":
-		client.Transport = getHttp2Transport()
+		client.Transport = getHttp2Transport(connectTimeout(cfg), tlsConfig)
 	case "grpc":
 	default:
 		logrus.Errorf("Invalid invoke protocol in the configuration file.")
@@ -28,10 +31,11 @@ func CreateHTTPClient(timeout int, invokeProtocol string) *http.Client {
 	return client
 }
 
-func getHttp1Transport(timeout int) *http.Transport {
+func getHttp1Transport(connectTimeout time.Duration, tlsConfig *tls.Config) *http.Transport {
 	return &http.Transport{
+		TLSClientConfig: tlsConfig,
 		DialContext: (&net.Dialer{
-			Timeout: time.Duration(timeout) * time.Second,
+			Timeout: connectTimeout,
 		}).DialContext,
 		IdleConnTimeout:     5 * time.Second,
 		MaxIdleConns:        100,
@@ -40,11 +44,22 @@ func getHttp1Transport(timeout int) *http.Transport {
 	}
 }
 
-func getHttp2Transport() *http2.Transport {
+func getHttp2Transport(connectTimeout time.Duration, tlsConfig *tls.Config) *http2.Transport {
+	dialer := &net.Dialer{Timeout: connectTimeout}
+
+	if tlsConfig != nil {
+		return &http2.Transport{
+			TLSClientConfig: tlsConfig,
+			DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return (&tls.Dialer{NetDialer: dialer, Config: cfg}).DialContext(ctx, network, addr)
+			},
+		}
+	}
+
 	return &http2.Transport{
 		AllowHTTP: true,
 		DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
-			return net.Dial(network, addr)
+			return dialer.DialContext(ctx, network, addr)
 		},
 	}
 }
@@ -14,6 +14,12 @@ type Invoker interface {
 	Invoke(*common.Function, *common.RuntimeSpecification) (bool, *metric.ExecutionRecord)
 }
 
+// Closer Implemented by invokers holding resources across invocations, such as pooled connections, which are released
+// once the experiment is done
+type Closer interface {
+	Close()
+}
+
 func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) Invoker {
 	switch strings.ToLower(cfg.LoaderConfiguration.Platform) {
 	case common.PlatformAWSLambda:
@@ -30,7 +36,9 @@ func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, r
 			return newGRPCInvoker(cfg.LoaderConfiguration, ExecutorRPC{})
 		}
 	case common.PlatformKnative:
-		if cfg.LoaderConfiguration.InvokeProtocol == "grpc" {
+		if cfg.LoaderConfiguration.KnativeAsyncMode {
+			return newCloudEventsInvoker(cfg.LoaderConfiguration)
+		} else if cfg.LoaderConfiguration.InvokeProtocol == "grpc" {
 			if !cfg.LoaderConfiguration.VSwarm {
 				return newGRPCInvoker(cfg.LoaderConfiguration, ExecutorRPC{})
 			} else {
@@ -41,6 +49,8 @@ func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, r
 		}
 	case common.PlatformOpenWhisk:
 		return newOpenWhiskInvoker(announceDoneExe, readOpenWhiskMetadata)
+	case common.PlatformOpenFaaS, common.PlatformFission:
+		return newGatewayInvoker(cfg.LoaderConfiguration)
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
@@ -137,6 +137,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failRequest(record, mc.ErrorClassRequest, err)
 
 		return false, record, nil
 	}
@@ -149,16 +150,19 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failRequest(record, mc.ErrorClassConnect, err)
 
 		return false, record, resp
 	}
 	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		log.Debugf("http request for function %s failed - error code: %s", function.Name, resp.Status)
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		failHTTPStatus(record, resp.StatusCode, nil)
 
 		return false, record, resp
 	}
@@ -169,6 +173,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		failResponseBody(record, resp.StatusCode, err)
 
 		return false, record, resp
 	}
@@ -179,6 +184,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		record.Fail(mc.ErrorClassResponse, resp.StatusCode, err.Error())
 
 		return false, record, resp
 	}
@@ -190,6 +196,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		record.Fail(mc.ErrorClassResponse, resp.StatusCode, err.Error())
 
 		return false, record, resp
 	}
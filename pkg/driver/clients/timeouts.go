@@ -0,0 +1,160 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTimeoutRuntimeFactor = 2.0
+	defaultTimeoutSlack         = time.Second
+)
+
+// invocationTimeouts Deadlines of a single invocation
+type invocationTimeouts struct {
+	Connect time.Duration
+	Total   time.Duration
+}
+
+// connectTimeout Returns the timeout for establishing a connection, which falls back to the function timeout
+func connectTimeout(cfg *config.LoaderConfiguration) time.Duration {
+	if cfg.GRPCConnectionTimeoutSeconds > 0 {
+		return time.Duration(cfg.GRPCConnectionTimeoutSeconds) * time.Second
+	}
+
+	return time.Duration(cfg.GRPCFunctionTimeoutSeconds) * time.Second
+}
+
+// computeInvocationTimeouts Returns the deadlines of the invocation. With per-function timeouts, the total deadline is
+// the requested runtime times the factor plus the slack, capped by the global function timeout.
+func computeInvocationTimeouts(cfg *config.LoaderConfiguration, runtimeSpec *common.RuntimeSpecification) invocationTimeouts {
+	global := time.Duration(cfg.GRPCFunctionTimeoutSeconds) * time.Second
+	result := invocationTimeouts{Connect: connectTimeout(cfg), Total: global}
+
+	if !cfg.PerFunctionTimeouts {
+		return result
+	}
+
+	factor := cfg.TimeoutRuntimeFactor
+	if factor <= 0 {
+		factor = defaultTimeoutRuntimeFactor
+	}
+	slack := time.Duration(cfg.TimeoutSlackMs) * time.Millisecond
+	if cfg.TimeoutSlackMs <= 0 {
+		slack = defaultTimeoutSlack
+	}
+
+	result.Total = time.Duration(float64(runtimeSpec.Runtime)*factor*float64(time.Millisecond)) + slack
+	if global > 0 && result.Total > global {
+		result.Total = global
+	}
+
+	return result
+}
+
+// invocationPhases Progress of an HTTP invocation, from which the phase of a timeout is determined
+type invocationPhases struct {
+	connected atomic.Bool
+	firstByte atomic.Bool
+}
+
+// withInvocationDeadline Attaches the total deadline of the invocation and the tracing of its phases to the request
+func withInvocationDeadline(req *http.Request, timeouts invocationTimeouts) (*http.Request, *invocationPhases, context.CancelFunc) {
+	phases := &invocationPhases{}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeouts.Total)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn:              func(httptrace.GotConnInfo) { phases.connected.Store(true) },
+		GotFirstResponseByte: func() { phases.firstByte.Store(true) },
+	})
+
+	return req.WithContext(ctx), phases, cancel
+}
+
+// timeoutPhase Returns the phase in which the request failed, or an empty string if it did not time out
+func (p *invocationPhases) timeoutPhase(err error) string {
+	if !isTimeout(err) {
+		return ""
+	}
+
+	switch {
+	case !p.connected.Load():
+		return mc.TimeoutPhaseConnect
+	case !p.firstByte.Load():
+		return mc.TimeoutPhaseFirstByte
+	default:
+		return mc.TimeoutPhaseTotal
+	}
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// recordHTTPFailure Classifies the failed request in the record
+func recordHTTPFailure(record *mc.ExecutionRecord, phases *invocationPhases, err error) {
+	record.TimeoutPhase = phases.timeoutPhase(err)
+
+	if record.TimeoutPhase == mc.TimeoutPhaseConnect || !phases.connected.Load() {
+		record.ConnectionTimeout = true
+		failRequest(&record.ExecutionRecordBase, mc.ErrorClassConnect, err)
+	} else {
+		record.FunctionTimeout = true
+		if isTimeout(err) {
+			failRequest(&record.ExecutionRecordBase, mc.ErrorClassTimeout, err)
+		} else {
+			// the connection broke before the response arrived
+			failRequest(&record.ExecutionRecordBase, mc.ErrorClassServer, err)
+		}
+	}
+}
+
+// waitForConnection Establishes the connection within the connect timeout, or until the context of the invocation is
+// done if that comes first. A connection that fails is reported immediately, as RPCs would fail fast on it as well.
+func waitForConnection(ctx context.Context, conn *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return status.Errorf(codes.Unavailable, "connection is in state %s", state)
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// grpcTimeoutPhase Returns the phase in which the RPC on an established connection timed out, if it did
+func grpcTimeoutPhase(err error) string {
+	if status.Code(err) == codes.DeadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
+		// unary responses arrive at once
+		return mc.TimeoutPhaseFirstByte
+	}
+
+	return ""
+}
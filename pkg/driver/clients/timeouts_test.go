@@ -0,0 +1,124 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+)
+
+func TestComputeInvocationTimeouts(t *testing.T) {
+	tests := []struct {
+		testName        string
+		perFunction     bool
+		factor          float64
+		slackMs         int
+		runtimeMs       int
+		expectedTotal   time.Duration
+		expectedConnect time.Duration
+	}{
+		{testName: "global", perFunction: false, runtimeMs: 50, expectedTotal: 15 * time.Second, expectedConnect: 5 * time.Second},
+		{testName: "defaults", perFunction: true, runtimeMs: 50, expectedTotal: 1100 * time.Millisecond, expectedConnect: 5 * time.Second},
+		{testName: "factor_and_slack", perFunction: true, factor: 3, slackMs: 200, runtimeMs: 100, expectedTotal: 500 * time.Millisecond, expectedConnect: 5 * time.Second},
+		{testName: "capped_by_global", perFunction: true, factor: 2, slackMs: 1000, runtimeMs: 60000, expectedTotal: 15 * time.Second, expectedConnect: 5 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cfg := createFakeLoaderConfiguration()
+			cfg.PerFunctionTimeouts = test.perFunction
+			cfg.TimeoutRuntimeFactor = test.factor
+			cfg.TimeoutSlackMs = test.slackMs
+
+			timeouts := computeInvocationTimeouts(cfg, &common.RuntimeSpecification{Runtime: test.runtimeMs})
+			if timeouts.Total != test.expectedTotal || timeouts.Connect != test.expectedConnect {
+				t.Errorf("Unexpected timeouts %+v", timeouts)
+			}
+		})
+	}
+}
+
+func TestHTTPTimeoutPhases(t *testing.T) {
+	tests := []struct {
+		testName      string
+		delayHeaders  time.Duration
+		delayBody     time.Duration
+		expectedPhase string
+	}{
+		{testName: "no_timeout", expectedPhase: ""},
+		{testName: "first_byte", delayHeaders: 500 * time.Millisecond, expectedPhase: mc.TimeoutPhaseFirstByte},
+		{testName: "total", delayBody: 500 * time.Millisecond, expectedPhase: mc.TimeoutPhaseTotal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(test.delayHeaders)
+
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+
+				time.Sleep(test.delayBody)
+				_, _ = w.Write([]byte(`{"hostname": "instance-1"}`))
+			}))
+			defer server.Close()
+
+			cfg := createFakeLoaderConfiguration()
+			cfg.Platform = common.PlatformFission
+			cfg.PerFunctionTimeouts = true
+			cfg.TimeoutRuntimeFactor = 1
+			cfg.TimeoutSlackMs = 200
+
+			function := testFunction
+			function.Endpoint = strings.TrimPrefix(server.URL, "http://")
+
+			invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+			success, record := invoker.Invoke(&function, &common.RuntimeSpecification{Runtime: 10, Memory: 128})
+
+			if success != (test.expectedPhase == "") || record.TimeoutPhase != test.expectedPhase {
+				t.Errorf("Unexpected outcome - success: %t; timeout phase: '%s'", success, record.TimeoutPhase)
+			}
+			if test.expectedPhase != "" && (!record.FunctionTimeout || record.ConnectionTimeout) {
+				t.Error("Timeouts after the connection was established should be marked as function timeouts.")
+			}
+		})
+	}
+}
+
+func TestRecordHTTPFailure(t *testing.T) {
+	record := &mc.ExecutionRecord{}
+	recordHTTPFailure(record, &invocationPhases{}, context.DeadlineExceeded)
+
+	if record.TimeoutPhase != mc.TimeoutPhaseConnect || !record.ConnectionTimeout || record.FunctionTimeout {
+		t.Errorf("Unexpected classification of a connect timeout %+v", record.ExecutionRecordBase)
+	}
+}
+
+func TestGRPCTimeoutPhase(t *testing.T) {
+	address, port := "localhost", 18084
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	cfg.PerFunctionTimeouts = true
+	cfg.TimeoutRuntimeFactor = 0.1
+	cfg.TimeoutSlackMs = 1
+
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	success, record := invoker.Invoke(&function, &common.RuntimeSpecification{Runtime: 500, Memory: 128})
+
+	if success || record.TimeoutPhase != mc.TimeoutPhaseFirstByte || record.GRPCConnectionEstablishTime == 0 {
+		t.Errorf("Unexpected outcome - success: %t; timeout phase: '%s'", success, record.TimeoutPhase)
+	}
+}
@@ -0,0 +1,212 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// defaultTokenRefreshPeriod Period of re-reading the bearer token file if not configured
+const defaultTokenRefreshPeriod = 60 * time.Second
+
+// TransportSecurity TLS and bearer-token authentication applied uniformly by the HTTP, gRPC, and async invokers, as
+// well as the async response fetcher. The zero value corresponds to plaintext without authentication.
+type TransportSecurity struct {
+	TLSConfig *tls.Config
+	tokens    *bearerTokenSource
+}
+
+// CreateTransportSecurity Builds the transport security from the loader configuration, terminating the loader if the
+// certificates or the token cannot be loaded.
+func CreateTransportSecurity(cfg *config.LoaderConfiguration) *TransportSecurity {
+	security, err := newTransportSecurity(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure transport security - %v", err)
+	}
+
+	return security
+}
+
+func newTransportSecurity(cfg *config.LoaderConfiguration) (*TransportSecurity, error) {
+	security := &TransportSecurity{}
+
+	if cfg.EnableTLS {
+		tlsConfig, err := createTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		security.TLSConfig = tlsConfig
+	}
+
+	if cfg.BearerToken != "" || cfg.BearerTokenFile != "" {
+		refresh := time.Duration(cfg.BearerTokenRefreshSeconds) * time.Second
+		if refresh <= 0 {
+			refresh = defaultTokenRefreshPeriod
+		}
+
+		security.tokens = &bearerTokenSource{
+			static:  cfg.BearerToken,
+			path:    cfg.BearerTokenFile,
+			refresh: refresh,
+		}
+
+		if _, err := security.tokens.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	return security, nil
+}
+
+func createTLSConfig(cfg *config.LoaderConfiguration) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+
+	if cfg.TLSCACertificate != "" {
+		bundle, err := os.ReadFile(cfg.TLSCACertificate)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.TLSCACertificate)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSClientCertificate != "" || cfg.TLSClientKey != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSClientCertificate, cfg.TLSClientKey)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// Scheme Returns the URL scheme of HTTP requests
+func (s *TransportSecurity) Scheme() string {
+	if s != nil && s.TLSConfig != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// AuthorizeRequest Sets the bearer token of the request, if configured
+func (s *TransportSecurity) AuthorizeRequest(req *http.Request) error {
+	if s == nil || s.tokens == nil {
+		return nil
+	}
+
+	token, err := s.tokens.Token()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// GRPCDialOptions Returns the transport credentials, and the per-RPC bearer token if configured
+func (s *TransportSecurity) GRPCDialOptions() []grpc.DialOption {
+	if s == nil || s.TLSConfig == nil {
+		options := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		if s != nil && s.tokens != nil {
+			options = append(options, grpc.WithPerRPCCredentials(s.tokens))
+		}
+
+		return options
+	}
+
+	options := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(s.TLSConfig.Clone()))}
+	if s.tokens != nil {
+		options = append(options, grpc.WithPerRPCCredentials(&secureBearerTokenSource{s.tokens}))
+	}
+
+	return options
+}
+
+// bearerTokenSource Static bearer token, or a token read from a file that is periodically re-read so that rotated
+// tokens (e.g., projected OIDC service account tokens) are picked up.
+type bearerTokenSource struct {
+	static  string
+	path    string
+	refresh time.Duration
+
+	mutex    sync.Mutex
+	token    string
+	loadedAt time.Time
+}
+
+func (s *bearerTokenSource) Token() (string, error) {
+	if s.path == "" {
+		return s.static, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token != "" && time.Since(s.loadedAt) < s.refresh {
+		return s.token, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if s.token != "" {
+			log.Warnf("Failed to refresh the bearer token from %s, using the previous one - %v", s.path, err)
+			return s.token, nil
+		}
+
+		return "", err
+	}
+
+	s.token = strings.TrimSpace(string(data))
+	s.loadedAt = time.Now()
+
+	return s.token, nil
+}
+
+// GetRequestMetadata Implements credentials.PerRPCCredentials
+func (s *bearerTokenSource) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := s.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity Implements credentials.PerRPCCredentials. Tokens are allowed over plaintext, as some
+// clusters terminate TLS at the ingress.
+func (s *bearerTokenSource) RequireTransportSecurity() bool {
+	return false
+}
+
+type secureBearerTokenSource struct {
+	*bearerTokenSource
+}
+
+func (s *secureBearerTokenSource) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,194 @@
+package clients
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func writePEM(t *testing.T, path string, blockType string, data []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createClientCertificate Writes a self-signed client certificate and its key to the directory
+func createClientCertificate(t *testing.T, directory string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "loader"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificatePath, keyPath := filepath.Join(directory, "client.crt"), filepath.Join(directory, "client.key")
+	writePEM(t, certificatePath, "CERTIFICATE", certificate)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyBytes)
+
+	return certificatePath, keyPath
+}
+
+func TestTransportSecurity(t *testing.T) {
+	directory := t.TempDir()
+	clientCertificate, clientKey := createClientCertificate(t, directory)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caBundle := filepath.Join(directory, "ca.crt")
+	writePEM(t, caBundle, "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		testName       string
+		cfg            config.LoaderConfiguration
+		expectedStatus int
+	}{
+		{
+			testName: "mtls_with_token",
+			cfg: config.LoaderConfiguration{
+				EnableTLS:            true,
+				TLSCACertificate:     caBundle,
+				TLSClientCertificate: clientCertificate,
+				TLSClientKey:         clientKey,
+				BearerToken:          "token",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			testName: "mtls_without_token",
+			cfg: config.LoaderConfiguration{
+				EnableTLS:            true,
+				TLSCACertificate:     caBundle,
+				TLSClientCertificate: clientCertificate,
+				TLSClientKey:         clientKey,
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			security, err := newTransportSecurity(&test.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if security.Scheme() != "https" {
+				t.Errorf("Unexpected scheme %s.", security.Scheme())
+			}
+
+			client := CreateHTTPClient(&config.LoaderConfiguration{GRPCFunctionTimeoutSeconds: 5}, "http1", security.TLSConfig)
+			req, _ := http.NewRequest("GET", security.Scheme()+"://"+server.Listener.Addr().String(), nil)
+			if err = security.AuthorizeRequest(req); err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			HandleBodyClosing(resp)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Errorf("Unexpected status code - expected: %d; got: %d", test.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	// the server certificate is not trusted without the CA bundle
+	security, _ := newTransportSecurity(&config.LoaderConfiguration{EnableTLS: true})
+	if _, err := CreateHTTPClient(&config.LoaderConfiguration{GRPCFunctionTimeoutSeconds: 5}, "http1", security.TLSConfig).Get("https://" + server.Listener.Addr().String()); err == nil {
+		t.Error("Untrusted server certificate should be rejected.")
+	}
+}
+
+func TestTransportSecurityConfigurationErrors(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      config.LoaderConfiguration
+	}{
+		{testName: "missing_ca_bundle", cfg: config.LoaderConfiguration{EnableTLS: true, TLSCACertificate: "/nonexistent/ca.crt"}},
+		{testName: "missing_client_key", cfg: config.LoaderConfiguration{EnableTLS: true, TLSClientCertificate: "/nonexistent/client.crt"}},
+		{testName: "missing_token_file", cfg: config.LoaderConfiguration{BearerTokenFile: "/nonexistent/token"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			if _, err := newTransportSecurity(&test.cfg); err == nil {
+				t.Error("Expected a configuration error.")
+			}
+		})
+	}
+}
+
+func TestBearerTokenRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := &bearerTokenSource{path: path, refresh: time.Hour}
+	if token, _ := tokens.Token(); token != "first" {
+		t.Errorf("Unexpected token %s.", token)
+	}
+
+	if err := os.WriteFile(path, []byte("second"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := tokens.Token(); token != "first" {
+		t.Error("The token should not be re-read before the refresh period.")
+	}
+
+	tokens.refresh = 0
+	metadata, err := tokens.GetRequestMetadata(context.Background())
+	if err != nil || metadata["authorization"] != "Bearer second" {
+		t.Errorf("Unexpected gRPC metadata %v.", metadata)
+	}
+
+	// the previous token is kept if the file cannot be read
+	_ = os.Remove(path)
+	if token, err := tokens.Token(); err != nil || token != "second" {
+		t.Errorf("Unexpected token %s after a failed refresh.", token)
+	}
+}
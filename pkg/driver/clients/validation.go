@@ -0,0 +1,168 @@
+package clients
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// BodyAssertion Checks the body of the response of a successful invocation, returning the reason of the mismatch
+type BodyAssertion func(function *common.Function, body []byte) error
+
+var (
+	bodyAssertions = map[string]BodyAssertion{
+		"json": func(_ *common.Function, body []byte) error {
+			if !json.Valid(body) {
+				return errors.New("not valid JSON")
+			}
+			return nil
+		},
+		"no_failure": func(_ *common.Function, body []byte) error {
+			if strings.Contains(string(body), "FAILURE -") {
+				return errors.New("the function reported a failure")
+			}
+			return nil
+		},
+	}
+	bodyAssertionsMutex sync.RWMutex
+)
+
+// RegisterBodyAssertion Makes the assertion available to the BodyAssertions of the response validation under the name.
+// Must be called before the invoker is created.
+func RegisterBodyAssertion(name string, assertion BodyAssertion) {
+	bodyAssertionsMutex.Lock()
+	defer bodyAssertionsMutex.Unlock()
+
+	bodyAssertions[name] = assertion
+}
+
+// validationError Failed check of a response, where the reason is constant per check so that the same
+// misconfiguration hashes the same for all functions
+type validationError struct {
+	reason string
+	detail string
+}
+
+func (e *validationError) Error() string {
+	return fmt.Sprintf("%s - %s", e.reason, e.detail)
+}
+
+// responseValidator Checks the responses of the invocations the platform reported as successful against the
+// configured response validation
+type responseValidator struct {
+	functionName       bool
+	runtimeTolerance   float64
+	runtimeToleranceMs int
+	patterns           []*regexp.Regexp
+	assertions         []namedBodyAssertion
+}
+
+type namedBodyAssertion struct {
+	name      string
+	assertion BodyAssertion
+}
+
+// newResponseValidator Returns nil if the response validation is not configured
+func newResponseValidator(cfg *config.LoaderConfiguration) *responseValidator {
+	validation := cfg.ResponseValidation
+	if validation == nil {
+		return nil
+	}
+
+	validator := &responseValidator{
+		functionName:       validation.FunctionName,
+		runtimeTolerance:   validation.RuntimeTolerance,
+		runtimeToleranceMs: validation.RuntimeToleranceMs,
+	}
+
+	for _, pattern := range validation.BodyPatterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Fatalf("Invalid body pattern %s of the response validation - %v", pattern, err)
+		}
+		validator.patterns = append(validator.patterns, expression)
+	}
+
+	bodyAssertionsMutex.RLock()
+	defer bodyAssertionsMutex.RUnlock()
+
+	for _, name := range validation.BodyAssertions {
+		assertion, ok := bodyAssertions[name]
+		if !ok {
+			log.Fatalf("Unknown body assertion %s of the response validation", name)
+		}
+		validator.assertions = append(validator.assertions, namedBodyAssertion{name: name, assertion: assertion})
+	}
+
+	return validator
+}
+
+// servedBy Tells whether the instance belongs to the function, where the instances are named after the function,
+// e.g., <function>-00001-deployment-<hash> on Knative
+func servedBy(instance string, function string) bool {
+	if !strings.HasPrefix(instance, function) {
+		return false
+	}
+
+	return len(instance) == len(function) || instance[len(function)] == '-'
+}
+
+func (v *responseValidator) validate(function *common.Function, record *mc.ExecutionRecord, body []byte) *validationError {
+	if v.functionName && !servedBy(record.Instance, function.Name) {
+		return &validationError{reason: "unexpected function", detail: fmt.Sprintf("served by %q", record.Instance)}
+	}
+
+	// not every function reports its runtime, hence only the reported runtimes are checked
+	if v.runtimeTolerance > 0 && record.ActualDuration > 0 {
+		requested, actual := float64(record.RequestedDuration), float64(record.ActualDuration)
+		tolerance := v.runtimeTolerance*requested + float64(v.runtimeToleranceMs)*1e3
+
+		if math.Abs(actual-requested) > tolerance {
+			return &validationError{
+				reason: "runtime out of tolerance",
+				detail: fmt.Sprintf("requested %d[us], reported %d[us]", record.RequestedDuration, record.ActualDuration),
+			}
+		}
+	}
+
+	for _, pattern := range v.patterns {
+		if !pattern.Match(body) {
+			return &validationError{reason: "unexpected body", detail: fmt.Sprintf("does not match %s", pattern)}
+		}
+	}
+
+	for _, assertion := range v.assertions {
+		if err := assertion.assertion(function, body); err != nil {
+			return &validationError{reason: "body assertion " + assertion.name, detail: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// Check Fails the record if the response does not pass the validation, returning whether it passed. A nil validator
+// accepts any response.
+func (v *responseValidator) Check(function *common.Function, record *mc.ExecutionRecord, body []byte) bool {
+	if v == nil {
+		return true
+	}
+
+	err := v.validate(function, record, body)
+	if err == nil {
+		return true
+	}
+
+	log.Warnf("Invalid response of %s - %v", function.Name, err)
+	record.Fail(mc.ErrorClassValidation, record.StatusCode, err.reason)
+
+	return false
+}
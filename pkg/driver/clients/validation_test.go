@@ -0,0 +1,97 @@
+package clients
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestResponseValidator(t *testing.T) {
+	RegisterBodyAssertion("test_hostname", func(function *common.Function, body []byte) error {
+		if !strings.Contains(string(body), function.Name) {
+			return errors.New("no hostname")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		testName   string
+		validation *config.ResponseValidation
+		instance   string
+		actual     uint32
+		body       string
+		valid      bool
+	}{
+		{testName: "disabled", instance: "other-function", body: "", valid: true},
+		{testName: "function_name", validation: &config.ResponseValidation{FunctionName: true}, instance: "test-function-00001-deployment-abc", valid: true},
+		{testName: "exact_function_name", validation: &config.ResponseValidation{FunctionName: true}, instance: "test-function", valid: true},
+		{testName: "other_function", validation: &config.ResponseValidation{FunctionName: true}, instance: "other-function-00001", valid: false},
+		{testName: "function_name_prefix", validation: &config.ResponseValidation{FunctionName: true}, instance: "test-functions-00001", valid: false},
+		{testName: "runtime_within_tolerance", validation: &config.ResponseValidation{RuntimeTolerance: 0.1}, actual: 10_900, valid: true},
+		{testName: "runtime_out_of_tolerance", validation: &config.ResponseValidation{RuntimeTolerance: 0.1}, actual: 11_100, valid: false},
+		{testName: "runtime_slack", validation: &config.ResponseValidation{RuntimeTolerance: 0.1, RuntimeToleranceMs: 1}, actual: 11_100, valid: true},
+		{testName: "runtime_not_reported", validation: &config.ResponseValidation{RuntimeTolerance: 0.1}, actual: 0, valid: true},
+		{testName: "body_pattern", validation: &config.ResponseValidation{BodyPatterns: []string{"^OK - "}}, body: "OK - test-function-00001", valid: true},
+		{testName: "body_pattern_mismatch", validation: &config.ResponseValidation{BodyPatterns: []string{"^OK - "}}, body: "Hello World", valid: false},
+		{testName: "json_assertion", validation: &config.ResponseValidation{BodyAssertions: []string{"json"}}, body: "plain", valid: false},
+		{testName: "failure_assertion", validation: &config.ResponseValidation{BodyAssertions: []string{"no_failure"}}, body: "FAILURE - mem_alloc", valid: false},
+		{testName: "registered_assertion", validation: &config.ResponseValidation{BodyAssertions: []string{"test_hostname"}}, body: "OK - test-function-00001", valid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			validator := newResponseValidator(&config.LoaderConfiguration{ResponseValidation: test.validation})
+
+			record := &mc.ExecutionRecord{
+				ExecutionRecordBase: mc.ExecutionRecordBase{
+					RequestedDuration: 10_000,
+					ActualDuration:    test.actual,
+					Instance:          test.instance,
+					StatusCode:        http.StatusOK,
+				},
+			}
+
+			if valid := validator.Check(&testFunction, record, []byte(test.body)); valid != test.valid {
+				t.Fatalf("Unexpected validation outcome - expected: %t; got: %t", test.valid, valid)
+			}
+			if !test.valid && (record.ErrorClass != mc.ErrorClassValidation || record.StatusCode != http.StatusOK || record.ErrorHash == "") {
+				t.Errorf("Unexpected classification of the invalid response %+v", record.ExecutionRecordBase)
+			}
+			if test.valid && record.ErrorClass != "" {
+				t.Errorf("Valid response classified as %s", record.ErrorClass)
+			}
+		})
+	}
+}
+
+func TestGatewayInvokerMisroutedRequest(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(common.HTTPInvocationReply{
+			Message:            "OK - other-function-7d9f",
+			Hostname:           "other-function-7d9f",
+			DurationInMicroSec: 10_000,
+		})
+	}))
+	defer gateway.Close()
+
+	cfg := createFakeLoaderConfiguration()
+	cfg.Platform = common.PlatformFission
+	cfg.ResponseValidation = &config.ResponseValidation{FunctionName: true}
+
+	function := testFunction
+	function.Endpoint = strings.TrimPrefix(gateway.URL, "http://") + "/" + function.Name
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	success, record := invoker.Invoke(&function, &testRuntimeSpecs)
+
+	if success || record.ErrorClass != mc.ErrorClassValidation || record.FunctionTimeout || record.ConnectionTimeout {
+		t.Errorf("Misrouted request not failed validation %+v", record.ExecutionRecordBase)
+	}
+}
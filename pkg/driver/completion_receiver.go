@@ -0,0 +1,390 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// defaultAsyncCompletionTimeout Time after which an asynchronous invocation without a pushed completion is failed, if
+// not configured. Matches the maximum function runtime.
+const defaultAsyncCompletionTimeout = 15 * time.Minute
+
+// expirationPeriod Period of checking the pending invocations for stragglers
+const expirationPeriod = time.Second
+
+type pushedCompletion struct {
+	common.AsyncCompletion
+	// arrival UNIX microseconds at which the completion reached the receiver
+	arrival int64
+}
+
+type pendingInvocation struct {
+	record   *metric.ExecutionRecord
+	output   chan *metric.ExecutionRecord
+	deadline time.Time
+	// onOutcome is called with whether the invocation completed once its completion arrives or it times out
+	onOutcome func(completed bool)
+}
+
+// completionReceiver Loader-hosted endpoint to which platforms and functions push the completions of asynchronous
+// invocations while the experiment runs. Submitted invocations are tracked until their completion arrives, at which
+// point the record is written out, or until their individual timeout expires, at which point the invocation is failed.
+// Completions are accepted as completion CloudEvents or plain JSON over HTTP, and through the
+// loader.CompletionReceiver/Complete gRPC method taking a google.protobuf.Struct with the same fields.
+type completionReceiver struct {
+	timeout time.Duration
+
+	httpServer   *http.Server
+	httpListener net.Listener
+	grpcAddress  string
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	stopCh       chan struct{}
+
+	mutex   sync.Mutex
+	drained *sync.Cond
+	pending map[string]*pendingInvocation
+	// early completions that arrived before the submission of the invocation returned, which are dropped if no
+	// submission matches them within the timeout
+	early map[string]*pushedCompletion
+
+	completed int
+	timedOut  int
+	unmatched int
+}
+
+func newCompletionReceiver(httpAddress string, grpcAddress string, timeout time.Duration) *completionReceiver {
+	if timeout <= 0 {
+		timeout = defaultAsyncCompletionTimeout
+	}
+
+	receiver := &completionReceiver{
+		timeout: timeout,
+		stopCh:  make(chan struct{}),
+		pending: make(map[string]*pendingInvocation),
+		early:   make(map[string]*pushedCompletion),
+	}
+	receiver.drained = sync.NewCond(&receiver.mutex)
+
+	if httpAddress != "" {
+		receiver.httpServer = &http.Server{Addr: httpAddress, Handler: receiver}
+	}
+	if grpcAddress != "" {
+		receiver.grpcServer = grpc.NewServer()
+		receiver.grpcServer.RegisterService(&completionReceiverServiceDesc, receiver)
+		receiver.grpcAddress = grpcAddress
+	}
+
+	return receiver
+}
+
+func (r *completionReceiver) Start() error {
+	if r.httpServer != nil {
+		listener, err := net.Listen("tcp", r.httpServer.Addr)
+		if err != nil {
+			return err
+		}
+		r.httpListener = listener
+
+		go func() {
+			if err := r.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Completion receiver stopped unexpectedly - %v", err)
+			}
+		}()
+
+		log.Infof("Completion receiver listening for HTTP on %s", listener.Addr())
+	}
+
+	if r.grpcServer != nil {
+		listener, err := net.Listen("tcp", r.grpcAddress)
+		if err != nil {
+			return err
+		}
+		r.grpcListener = listener
+
+		go func() {
+			if err := r.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				log.Errorf("Completion receiver stopped unexpectedly - %v", err)
+			}
+		}()
+
+		log.Infof("Completion receiver listening for gRPC on %s", listener.Addr())
+	}
+
+	go r.runExpiration()
+
+	return nil
+}
+
+func (r *completionReceiver) Stop() {
+	close(r.stopCh)
+
+	if r.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := r.httpServer.Shutdown(ctx); err != nil {
+			log.Warnf("Failed to shut down the completion receiver - %v", err)
+		}
+	}
+	if r.grpcServer != nil {
+		r.grpcServer.Stop()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if unknown := r.unmatched + len(r.early); unknown > 0 {
+		log.Warnf("Received %d completions of unknown invocations.", unknown)
+	}
+}
+
+func (r *completionReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	arrival := time.Now().UnixMicro()
+
+	// plain JSON completions carry no CloudEvent attributes
+	if eventType := req.Header.Get(common.CloudEventTypeHeader); eventType != "" && eventType != common.CloudEventCompletionType {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var completion common.AsyncCompletion
+	if err = json.Unmarshal(body, &completion); err != nil || completion.InvocationID == "" {
+		log.Warnf("Received a malformed completion - %s", body)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.receive(&pushedCompletion{AsyncCompletion: completion, arrival: arrival})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *completionReceiver) completeRPC(in *structpb.Struct) error {
+	arrival := time.Now().UnixMicro()
+
+	data, err := protojson.Marshal(in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var completion common.AsyncCompletion
+	if err = json.Unmarshal(data, &completion); err != nil || completion.InvocationID == "" {
+		log.Warnf("Received a malformed completion - %s", data)
+		return status.Error(codes.InvalidArgument, "malformed completion")
+	}
+
+	r.receive(&pushedCompletion{AsyncCompletion: completion, arrival: arrival})
+
+	return nil
+}
+
+func (r *completionReceiver) receive(completion *pushedCompletion) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invocation, ok := r.pending[completion.InvocationID]
+	if !ok {
+		r.early[completion.InvocationID] = completion
+		return
+	}
+
+	r.finalize(invocation, completion)
+}
+
+// Track Awaits the completion of the submitted invocation, after which the record is written to the output channel.
+// The outcome callback, if any, is called with the mutex held once the invocation completes or times out.
+func (r *completionReceiver) Track(record *metric.ExecutionRecord, output chan *metric.ExecutionRecord, onOutcome func(completed bool)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invocation := &pendingInvocation{
+		record:    record,
+		output:    output,
+		deadline:  time.Now().Add(r.timeout),
+		onOutcome: onOutcome,
+	}
+
+	r.pending[record.AsyncResponseID] = invocation
+
+	if completion, ok := r.early[record.AsyncResponseID]; ok {
+		r.finalize(invocation, completion)
+	}
+}
+
+// finalize Fills in the record from the completion, where the end-to-end latency spans from the submission to the
+// arrival of the completion. Must be called with the mutex held.
+func (r *completionReceiver) finalize(invocation *pendingInvocation, completion *pushedCompletion) {
+	record := invocation.record
+
+	delete(r.pending, record.AsyncResponseID)
+	delete(r.early, record.AsyncResponseID)
+
+	if completion.Function != "" {
+		record.Instance = completion.Function
+	}
+	record.ActualDuration = completion.DurationInMicroSec
+	record.ActualMemoryUsage = common.Kib2Mib(completion.MemoryUsageInKb)
+	record.UserCodeExecutionMs = int64(completion.DurationInMicroSec)
+	if completion.ReceivedAt > 0 {
+		record.QueueingDelay = completion.ReceivedAt - record.StartTime
+	}
+	record.ResponseTime = completion.arrival - record.StartTime
+
+	r.completed++
+	if invocation.onOutcome != nil {
+		invocation.onOutcome(true)
+	}
+	r.emit(invocation)
+}
+
+// emit Writes the record out without blocking the receiver on the metrics collector. Must be called with the mutex
+// held.
+func (r *completionReceiver) emit(invocation *pendingInvocation) {
+	go func() {
+		invocation.output <- invocation.record
+	}()
+
+	if len(r.pending) == 0 {
+		r.drained.Broadcast()
+	}
+}
+
+// expire Fails the pending invocations whose deadline passed, returning their number
+func (r *completionReceiver) expire(now time.Time) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	expired := 0
+	for id, invocation := range r.pending {
+		if now.Before(invocation.deadline) {
+			continue
+		}
+
+		delete(r.pending, id)
+
+		invocation.record.FunctionTimeout = true
+		invocation.record.Fail(metric.ErrorClassTimeout, 0, "no completion received")
+		invocation.record.ResponseTime = now.UnixMicro() - invocation.record.StartTime
+
+		r.timedOut++
+		expired++
+		if invocation.onOutcome != nil {
+			invocation.onOutcome(false)
+		}
+		r.emit(invocation)
+	}
+
+	return expired
+}
+
+// dropUnmatched Drops the early completions no submission matched within the timeout, returning their number
+func (r *completionReceiver) dropUnmatched(now time.Time) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dropped := 0
+	for id, completion := range r.early {
+		if now.Sub(time.UnixMicro(completion.arrival)) < r.timeout {
+			continue
+		}
+
+		delete(r.early, id)
+		dropped++
+	}
+	r.unmatched += dropped
+
+	return dropped
+}
+
+func (r *completionReceiver) runExpiration() {
+	ticker := time.NewTicker(expirationPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if expired := r.expire(now); expired > 0 {
+				log.Warnf("%d asynchronous invocations have not reported completion within %v.", expired, r.timeout)
+			}
+			if dropped := r.dropUnmatched(now); dropped > 0 {
+				log.Warnf("Dropped %d completions of unknown invocations after %v.", dropped, r.timeout)
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// Wait Blocks until all the tracked invocations either completed or timed out
+func (r *completionReceiver) Wait() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.pending) > 0 {
+		log.Infof("Waiting for the completion of %d asynchronous invocations...", len(r.pending))
+	}
+
+	for len(r.pending) > 0 {
+		r.drained.Wait()
+	}
+
+	log.Infof("Received %d completions, %d asynchronous invocations timed out.", r.completed, r.timedOut)
+}
+
+func completeHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &emptypb.Empty{}, srv.(*completionReceiver).completeRPC(req.(*structpb.Struct))
+	}
+	if interceptor == nil {
+		return handler(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: completionReceiverCompleteMethod}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+const completionReceiverCompleteMethod = "/loader.CompletionReceiver/Complete"
+
+// completionReceiverServiceDesc Hand-written descriptor of the loader.CompletionReceiver service, as the messages are
+// well-known protobuf types and no code generation is needed
+var completionReceiverServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loader.CompletionReceiver",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Complete",
+			Handler:    completeHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "completion_receiver",
+}
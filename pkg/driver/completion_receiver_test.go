@@ -0,0 +1,143 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCompletionReceiver(t *testing.T) {
+	receiver := newCompletionReceiver("127.0.0.1:0", "127.0.0.1:0", time.Minute)
+	if err := receiver.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Stop()
+
+	conn, err := grpc.NewClient(receiver.grpcListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	output := make(chan *metric.ExecutionRecord, 10)
+	start := time.Now().UnixMicro()
+
+	tests := []struct {
+		testName    string
+		id          string
+		transport   string
+		eventType   string
+		early       bool
+		statusCode  int
+		shouldTrack bool
+	}{
+		{testName: "cloudevent", id: "event-1", transport: "http", eventType: common.CloudEventCompletionType, statusCode: http.StatusAccepted, shouldTrack: true},
+		{testName: "wrong_event_type", id: "event-2", transport: "http", eventType: common.CloudEventInvocationType, statusCode: http.StatusBadRequest},
+		{testName: "plain_json", id: "dirigent-1", transport: "http", statusCode: http.StatusAccepted, shouldTrack: true},
+		{testName: "grpc", id: "grpc-1", transport: "grpc", shouldTrack: true},
+		{testName: "before_submission_returned", id: "early-1", transport: "http", early: true, statusCode: http.StatusAccepted, shouldTrack: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			completion := common.AsyncCompletion{
+				InvocationID:       test.id,
+				Function:           "test-function",
+				ReceivedAt:         start + 1000,
+				DurationInMicroSec: 5000,
+				MemoryUsageInKb:    2048,
+			}
+
+			record := &metric.ExecutionRecord{ExecutionRecordBase: metric.ExecutionRecordBase{StartTime: start}, AsyncResponseID: test.id}
+			var outcomes []bool
+			onOutcome := func(completed bool) { outcomes = append(outcomes, completed) }
+			if !test.early {
+				receiver.Track(record, output, onOutcome)
+			}
+
+			switch test.transport {
+			case "http":
+				data, _ := json.Marshal(completion)
+				req, _ := http.NewRequest("POST", "http://"+receiver.httpListener.Addr().String(), bytes.NewReader(data))
+				if test.eventType != "" {
+					req.Header.Set(common.CloudEventTypeHeader, test.eventType)
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_ = resp.Body.Close()
+
+				if resp.StatusCode != test.statusCode {
+					t.Fatalf("Unexpected status code - expected: %d; got: %d", test.statusCode, resp.StatusCode)
+				}
+			case "grpc":
+				in, _ := structpb.NewStruct(map[string]interface{}{
+					"invocationID":       completion.InvocationID,
+					"function":           completion.Function,
+					"receivedAt":         completion.ReceivedAt,
+					"durationInMicroSec": completion.DurationInMicroSec,
+					"memoryUsageInKb":    completion.MemoryUsageInKb,
+				})
+				if err := conn.Invoke(context.Background(), completionReceiverCompleteMethod, in, &emptypb.Empty{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.early {
+				receiver.Track(record, output, onOutcome)
+			}
+
+			if !test.shouldTrack {
+				if expired := receiver.expire(time.Now().Add(2 * time.Minute)); expired != 1 || !record.FunctionTimeout {
+					t.Error("Invocations without completion should time out.")
+				}
+				<-output
+
+				if len(outcomes) != 1 || outcomes[0] {
+					t.Errorf("Timed out invocations should be counted as failed - outcomes: %v", outcomes)
+				}
+
+				return
+			}
+
+			written := <-output
+			if len(outcomes) != 1 || !outcomes[0] {
+				t.Errorf("Completed invocations should be counted as successful - outcomes: %v", outcomes)
+			}
+			if written != record || record.QueueingDelay != 1000 || record.ActualDuration != 5000 ||
+				record.ActualMemoryUsage != 2 || record.ResponseTime <= 0 || record.Instance != "test-function" {
+
+				t.Errorf("Unexpected completed record %+v.", record)
+			}
+		})
+	}
+
+	// all the invocations are either completed or timed out, hence waiting must not block
+	receiver.Wait()
+}
+
+func TestCompletionReceiverDropsUnmatched(t *testing.T) {
+	receiver := newCompletionReceiver("", "", time.Minute)
+
+	arrival := time.Now()
+	receiver.receive(&pushedCompletion{AsyncCompletion: common.AsyncCompletion{InvocationID: "unknown"}, arrival: arrival.UnixMicro()})
+
+	if dropped := receiver.dropUnmatched(arrival.Add(30 * time.Second)); dropped != 0 || len(receiver.early) != 1 {
+		t.Error("Completions should be kept for a submission to match them within the timeout.")
+	}
+	if dropped := receiver.dropUnmatched(arrival.Add(2 * time.Minute)); dropped != 1 || len(receiver.early) != 0 || receiver.unmatched != 1 {
+		t.Error("Completions no submission matched within the timeout should be dropped.")
+	}
+}
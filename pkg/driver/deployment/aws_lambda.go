@@ -5,12 +5,18 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// awsAccountIdVariable Serverless Framework variable resolving to the ID of the AWS account the functions are deployed to
+const awsAccountIdVariable = "${aws:accountId}"
+
 type awsLambdaDeployer struct {
 	functions []*common.Function
 }
@@ -19,17 +25,38 @@ func newAWSLambdaDeployer() *awsLambdaDeployer {
 	return &awsLambdaDeployer{}
 }
 
-func (ld *awsLambdaDeployer) Deploy(cfg *config.Configuration) {
+func (ld *awsLambdaDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
 	ld.functions = cfg.Functions
+	start := time.Now()
+
+	return newDeploymentResult(cfg.Functions, start, internalAWSDeployment(cfg.Functions))
+}
+
+// Render Writes the serverless.yml files of the function groups, referring to the AWS account through the variable
+// the Serverless Framework resolves on deployment
+func (ld *awsLambdaDeployer) Render(cfg *config.Configuration, dir string) error {
+	const provider = "aws"
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	for i, functionGroup := range separateFunctions(cfg.Functions) {
+		serverless := newServerlessConfig(i, functionGroup, provider, awsAccountIdVariable)
+		if err := serverless.WriteServerlessConfigFile(filepath.Join(dir, fmt.Sprintf("serverless-%d.yml", i))); err != nil {
+			return err
+		}
+	}
 
-	internalAWSDeployment(cfg.Functions)
+	return nil
 }
 
 func (ld *awsLambdaDeployer) Clean() {
 	CleanAWSLambda(ld.functions)
 }
 
-func internalAWSDeployment(functions []*common.Function) {
+// internalAWSDeployment deploys either all or none of the functions, cleaning up the deployed ones on failure
+func internalAWSDeployment(functions []*common.Function) error {
 	const provider = "aws"
 
 	// Check if all required dependencies are installed, verify that AWS account is clean and ready for deployment
@@ -42,9 +69,10 @@ func internalAWSDeployment(functions []*common.Function) {
 	// Due to CPU and memory constraints, by default, we will deploy 2 serverless.yml files in parallel and wait for them to finish before deploying the next 2
 	var wg sync.WaitGroup
 	var counter uint64 = 0
+	var failed atomic.Int64
 	parallelDeployment := 2
 
-	for i := 0; i < len(functionGroups); {
+	for i := 0; i < len(functionGroups) && failed.Load() == 0; {
 		for parallelIndex := 0; parallelIndex < parallelDeployment; parallelIndex++ {
 			if i < len(functionGroups) {
 				wg.Add(1)
@@ -55,8 +83,7 @@ func internalAWSDeployment(functions []*common.Function) {
 					functionToURLMapping := DeployServerless(index)
 
 					if functionToURLMapping == nil {
-						CleanAWSLambda(functions)                               // Clean up all deployed functions before exiting
-						log.Fatalf("Failed to deploy serverless-%d.yml", index) // Immediately terminate deployment for fast feedback
+						failed.Add(1) // Stop deploying further groups for fast feedback
 					} else {
 						atomic.AddUint64(&counter, 1)
 						for i := 0; i < len(functionGroup); i++ {
@@ -71,7 +98,13 @@ func internalAWSDeployment(functions []*common.Function) {
 		wg.Wait()
 	}
 
+	if failed.Load() > 0 {
+		CleanAWSLambda(functions) // Clean up all deployed functions
+		return fmt.Errorf("failed to deploy %d serverless.yml files", failed.Load())
+	}
+
 	log.Debugf("Deployed all %d serverless.yml files", len(functionGroups))
+	return nil
 }
 
 // CleanAWSLambda cleans up the AWS Lambda deployment environment by deleting all serverless.yml files and the ECR private repository
@@ -267,13 +300,19 @@ func separateFunctions(functions []*common.Function) [][]*common.Function {
 func createSlsConfigFiles(functionGroups [][]*common.Function, provider string, awsAccountId string) {
 	for i := 0; i < len(functionGroups); i++ {
 		log.Debugf("Creating serverless-%d.yml", i)
-		serverless := Serverless{}
-		serverless.CreateHeader(i, provider)
+		serverless := newServerlessConfig(i, functionGroups[i], provider, awsAccountId)
+		serverless.CreateServerlessConfigFile(i)
+	}
+}
 
-		for j := 0; j < len(functionGroups[i]); j++ {
-			serverless.AddFunctionConfig(functionGroups[i][j], provider, awsAccountId)
-		}
+// newServerlessConfig creates the contents of the serverless.yml file of a group of functions
+func newServerlessConfig(index int, functionGroup []*common.Function, provider string, awsAccountId string) *Serverless {
+	serverless := &Serverless{}
+	serverless.CreateHeader(index, provider)
 
-		serverless.CreateServerlessConfigFile(i)
+	for j := 0; j < len(functionGroup); j++ {
+		serverless.AddFunctionConfig(functionGroup[j], provider, awsAccountId)
 	}
+
+	return serverless
 }
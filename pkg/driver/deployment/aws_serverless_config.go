@@ -91,16 +91,19 @@ func (s *Serverless) AddFunctionConfig(function *common.Function, provider strin
 
 // CreateServerlessConfigFile dumps the contents of the Serverless struct into a yml file (serverless-<index>.yml)
 func (s *Serverless) CreateServerlessConfigFile(index int) {
-	data, err := yaml.Marshal(&s)
-	if err != nil {
+	if err := s.WriteServerlessConfigFile(fmt.Sprintf("./serverless-%d.yml", index)); err != nil {
 		log.Fatal(err)
 	}
+}
 
-	err = os.WriteFile(fmt.Sprintf("./serverless-%d.yml", index), data, os.FileMode(0644))
-
+// WriteServerlessConfigFile dumps the contents of the Serverless struct into the yml file at the path
+func (s *Serverless) WriteServerlessConfigFile(path string) error {
+	data, err := yaml.Marshal(&s)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+
+	return os.WriteFile(path, data, os.FileMode(0644))
 }
 
 // DeployServerless deploys the functions defined in the serverless.com file and returns a map from function name to URL
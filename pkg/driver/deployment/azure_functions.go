@@ -33,16 +33,37 @@ func newAzureFunctionsDeployer() *azureFunctionsDeployer {
 	return &azureFunctionsDeployer{}
 }
 
-func (afd *azureFunctionsDeployer) Deploy(cfg *config.Configuration) {
+func (afd *azureFunctionsDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
 	afd.functions = cfg.Functions
-	afd.config = DeployAzureFunctions(afd.functions)
+	start := time.Now()
+
+	config, err := DeployAzureFunctions(afd.functions)
+	if err != nil {
+		// clean up the resources created so far, so that a redeployment does not leave them behind
+		CleanAzureFunctions(config, afd.functions)
+	} else {
+		afd.config = config
+	}
+
+	return newDeploymentResult(afd.functions, start, err)
+}
+
+// Render Writes the contents of the zip file of each function app, without creating any Azure resources
+func (afd *azureFunctionsDeployer) Render(cfg *config.Configuration, dir string) error {
+	return RenderFunctionApps(".", dir, cfg.Functions)
 }
 
 func (afd *azureFunctionsDeployer) Clean() {
+	if afd.config == nil {
+		return
+	}
+
 	CleanAzureFunctions(afd.config, afd.functions)
 }
 
-func DeployAzureFunctions(functions []*common.Function) *Config {
+// DeployAzureFunctions deploys either all or none of the functions, in which case the returned configuration locates
+// the resources to clean up
+func DeployAzureFunctions(functions []*common.Function) (*Config, error) {
 	// 1. Copy exec_func.py to azurefunctions_setup
 	// 2. Initialize resources required for Azure Functions deployment
 	// 3. Create function folders
@@ -76,20 +97,20 @@ func DeployAzureFunctions(functions []*common.Function) *Config {
 
 	// 3. Create function folders
 	if err := CreateFunctionFolders(baseDir, sharedWorkloadDir, functions); err != nil {
-		log.Fatalf("Error setting up function folders required for zipping: %s", err)
+		return config, fmt.Errorf("error setting up function folders required for zipping: %w", err)
 	}
 
 	// 4. Zip function folders
 	if err := ZipFunctionAppFiles(baseDir, functions); err != nil {
-		log.Fatalf("Error zipping function app files for deployment: %s", err)
+		return config, fmt.Errorf("error zipping function app files for deployment: %w", err)
 	}
 
 	// 5. Deploy the function to Azure Functions
 	if err := DeployFunctions(config, zipBaseDir, functions); err != nil {
-		log.Fatalf("Error deploying function: %s", err)
+		return config, fmt.Errorf("error deploying function: %w", err)
 	}
 
-	return config
+	return config, nil
 }
 
 func CleanAzureFunctions(config *Config, functions []*common.Function) {
@@ -331,6 +352,38 @@ func ZipFunctionAppFiles(baseDir string, functions []*common.Function) error {
 	return nil
 }
 
+// RenderFunctionApps writes the files ZipFunctionAppFiles would zip for each function into renderDir/function<i>/,
+// with the same layout as the zip file, reading the workload and settings from the repository at rootDir
+func RenderFunctionApps(rootDir, renderDir string, functions []*common.Function) error {
+	setupDir := filepath.Join(rootDir, "azurefunctions_setup")
+	sharedWorkloadDir := filepath.Join(setupDir, "shared_azure_workload")
+
+	for i := 0; i < len(functions); i++ {
+		folderName := fmt.Sprintf("function%d", i)
+		appDir := filepath.Join(renderDir, folderName)
+
+		if err := os.MkdirAll(filepath.Join(appDir, folderName), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create folder %s: %w", appDir, err)
+		}
+
+		files := [][2]string{
+			{filepath.Join(sharedWorkloadDir, "azurefunctionsworkload.py"), filepath.Join(folderName, "azurefunctionsworkload.py")},
+			{filepath.Join(rootDir, "server", "trace-func-py", "exec_func.py"), filepath.Join(folderName, "exec_func.py")},
+			{filepath.Join(sharedWorkloadDir, "function.json"), filepath.Join(folderName, "function.json")},
+			{filepath.Join(setupDir, "host.json"), "host.json"},
+			{filepath.Join(setupDir, "requirements.txt"), "requirements.txt"},
+		}
+		for _, file := range files {
+			if err := common.CopyFile(file[0], filepath.Join(appDir, file[1])); err != nil {
+				return fmt.Errorf("failed to copy %s to %s: %w", file[0], appDir, err)
+			}
+		}
+	}
+
+	log.Debugf("Rendered %d function apps under %s", len(functions), renderDir)
+	return nil
+}
+
 /* Function for deploying zipped functions */
 
 func DeployFunctions(config *Config, baseDir string, functions []*common.Function) error {
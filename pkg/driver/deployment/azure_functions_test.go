@@ -148,6 +148,41 @@ func TestZipHealth(t *testing.T) {
 }
 
 // Tests loading of config file in /azurefunctions_setup.
+// Tests rendering of the function apps has the layout of the zip files.
+func TestRenderFunctionApps(t *testing.T) {
+
+	// Get current working directory
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	// Construct root directory path
+	root := filepath.Join(cwd, "..", "..", "..")
+	renderDir := t.TempDir()
+
+	functions := []*common.Function{
+		{Name: "function0"},
+		{Name: "function1"},
+	}
+
+	err = deployment.RenderFunctionApps(root, renderDir, functions)
+	require.NoError(t, err)
+
+	for i := range functions {
+		appDir := filepath.Join(renderDir, fmt.Sprintf("function%d", i))
+
+		for _, file := range []string{
+			fmt.Sprintf("function%d/azurefunctionsworkload.py", i),
+			fmt.Sprintf("function%d/exec_func.py", i),
+			fmt.Sprintf("function%d/function.json", i),
+			"host.json",
+			"requirements.txt",
+		} {
+			_, err = os.Stat(filepath.Join(appDir, file))
+			assert.NoError(t, err, "Missing rendered file %s", file)
+		}
+	}
+}
+
 func TestLoadConfig(t *testing.T) {
 
 	// Get current working directory
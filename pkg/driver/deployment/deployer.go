@@ -1,16 +1,94 @@
 package deployment
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
 )
 
+// maxFunctionScale Maximum number of instances of a function on OpenFaaS and Fission, matching the max-scale of the
+// Knative YAML specifications
+const maxFunctionScale = 200
+
 type FunctionDeployer interface {
-	Deploy(cfg *config.Configuration)
+	// Deploy Deploys the functions, setting their endpoints, and returns the outcome of deploying each of them
+	Deploy(cfg *config.Configuration) DeploymentResult
+	// Render Writes out what Deploy would deploy to the directory, without contacting the platform
+	Render(cfg *config.Configuration, dir string) error
 	Clean()
 }
 
+// FunctionDeploymentResult Outcome of deploying a function, which is not invoked unless deployed
+type FunctionDeploymentResult struct {
+	Function string
+	Deployed bool
+	// Reused Whether the function was deployed by reusing its existing deployment
+	Reused   bool
+	Attempts int
+	Duration time.Duration
+	Err      error
+}
+
+// DeploymentResult Outcome of deploying the functions of the experiment
+type DeploymentResult struct {
+	Functions []FunctionDeploymentResult
+}
+
+// newDeploymentResult Returns the same outcome for all the functions, as of the platforms deploying them at once
+func newDeploymentResult(functions []*common.Function, start time.Time, err error) DeploymentResult {
+	result := DeploymentResult{Functions: make([]FunctionDeploymentResult, 0, len(functions))}
+	for _, function := range functions {
+		result.Add(function, start, err)
+	}
+
+	return result
+}
+
+// Add Records the outcome of deploying the function, which is deployed if err is nil
+func (r *DeploymentResult) Add(function *common.Function, start time.Time, err error) {
+	r.Functions = append(r.Functions, FunctionDeploymentResult{
+		Function: function.Name,
+		Deployed: err == nil,
+		Attempts: 1,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+}
+
+// Failed Returns the outcomes of the functions that failed to deploy
+func (r DeploymentResult) Failed() []FunctionDeploymentResult {
+	var failed []FunctionDeploymentResult
+	for _, function := range r.Functions {
+		if !function.Deployed {
+			failed = append(failed, function)
+		}
+	}
+
+	return failed
+}
+
+// ScaleScheduler Implemented by deployers that can update the minimum scale of deployed functions during the
+// experiment, which the driver uses to apply the pre-scaling schedule over the warmup.
+type ScaleScheduler interface {
+	UpdateMinScale(function *common.Function, minScale int) error
+	// RestoreMinScale Restores the minimum scale the function was deployed with
+	RestoreMinScale(function *common.Function) error
+}
+
+// ScaleReporter Implemented by deployers that read the scale of the deployed functions from the native metrics of the
+// platform, instead of the Knative autoscaler metrics in Prometheus.
+type ScaleReporter interface {
+	DeploymentScales() ([]metric.DeploymentScale, error)
+}
+
 func CreateDeployer(cfg *config.Configuration) FunctionDeployer {
 	switch cfg.LoaderConfiguration.Platform {
 	case common.PlatformAWSLambda:
@@ -20,12 +98,108 @@ func CreateDeployer(cfg *config.Configuration) FunctionDeployer {
 	case common.PlatformDirigent:
 		return newDirigentDeployer()
 	case common.PlatformKnative:
-		return newKnativeDeployer()
+		return newKnativeDeployer(cfg.LoaderConfiguration)
 	case common.PlatformOpenWhisk:
 		return newOpenWhiskDeployer()
+	case common.PlatformOpenFaaS:
+		return newOpenFaaSDeployer(cfg.LoaderConfiguration)
+	case common.PlatformFission:
+		return newFissionDeployer(cfg.LoaderConfiguration)
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
 
 	return nil
 }
+
+// traceFunctionEnvironment Environment of the trace function serving plain HTTP invocations, as in the Knative YAML
+// specifications
+func traceFunctionEnvironment(function *common.Function, port int) map[string]string {
+	return map[string]string{
+		"FUNC_PROTOCOL_ENV":       "http",
+		"FUNC_PORT_ENV":           strconv.Itoa(port),
+		"ITERATIONS_MULTIPLIER":   "102",
+		"ENABLE_TRACING":          "false",
+		"COLD_START_BUSY_LOOP_MS": strconv.Itoa(function.ColdStartBusyLoopMs),
+		"IO_PERCENTAGE":           "0",
+	}
+}
+
+// traceFunctionImage Returns the configured image of the trace function, or the one from the Knative YAML
+// specifications
+func traceFunctionImage(cfg *config.LoaderConfiguration) string {
+	if cfg.FunctionImage != "" {
+		return cfg.FunctionImage
+	}
+
+	return "ghcr.io/vhive-serverless/invitro_trace_function:latest"
+}
+
+// waitForReadiness Waits until each function has at least one ready instance, or the timeout expires, and returns
+// the functions that are not ready
+func waitForReadiness(reporter ScaleReporter, functions []*common.Function, timeout time.Duration) map[string]bool {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		ready := make(map[string]bool)
+		if scales, err := reporter.DeploymentScales(); err == nil {
+			for _, scale := range scales {
+				ready[scale.Function] = scale.RunningPods > 0
+			}
+		} else {
+			logrus.Debugf("Failed to read the deployment scales - %v", err)
+		}
+
+		notReady := make(map[string]bool)
+		for _, function := range functions {
+			if !ready[function.Name] {
+				notReady[function.Name] = true
+			}
+		}
+
+		if len(notReady) == 0 {
+			return notReady
+		} else if time.Now().After(deadline) {
+			logrus.Warnf("%d functions are not ready after %v.", len(notReady), timeout)
+			return notReady
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// readinessResult Returns the outcome of deploying the functions, which failed if they could not be deployed or did
+// not become ready in time
+func readinessResult(functions []*common.Function, start time.Time, errs map[string]error, notReady map[string]bool, timeout time.Duration) DeploymentResult {
+	result := DeploymentResult{}
+	for _, function := range functions {
+		err := errs[function.Name]
+		if err == nil && notReady[function.Name] {
+			err = fmt.Errorf("not ready within %v", timeout)
+		}
+		result.Add(function, start, err)
+	}
+
+	return result
+}
+
+// writeRendered Writes the rendered file to the path relative to the render directory
+func writeRendered(dir string, name string, data []byte) error {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	logrus.Debugf("Rendered %s", path)
+	return os.WriteFile(path, data, 0644)
+}
+
+// writeRenderedJSON Writes the object as indented JSON to the path relative to the render directory
+func writeRenderedJSON(dir string, name string, object interface{}) error {
+	data, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeRendered(dir, name, append(data, '\n'))
+}
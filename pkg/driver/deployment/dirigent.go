@@ -36,93 +36,161 @@ func newDirigentDeployerConfiguration(cfg *config.Configuration) dirigentDeploym
 	}
 }
 
-func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
+func (d *dirigentDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
 	dirigentDeployerConfig := newDirigentDeployerConfiguration(cfg)
 
+	if dirigentDeployerConfig.deployWorkflow {
+		return d.deployWorkflow(cfg, dirigentDeployerConfig)
+	}
+
+	results := make([]FunctionDeploymentResult, len(cfg.Functions))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(cfg.Functions))
+
+	for i := 0; i < len(cfg.Functions); i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			start := time.Now()
+			function := cfg.Functions[idx]
+
+			var err error
+			if function.DirigentMetadata == nil {
+				err = fmt.Errorf("no Dirigent metadata for function %s", function.Name)
+			} else {
+				err = deployDirigentFunction(
+					function,
+					function.DirigentMetadata.Image,
+					dirigentDeployerConfig.RegistrationServer,
+					cfg.DirigentConfiguration.BusyLoopOnSandboxStartup,
+					cfg.DirigentConfiguration.PrepullMode,
+					cfg.DirigentConfiguration.RpsRequestedGpu,
+				)
+			}
+
+			result := DeploymentResult{}
+			result.Add(function, start, err)
+			results[idx] = result.Functions[0]
+		}(i)
+	}
+
+	wg.Wait()
+
+	return DeploymentResult{Functions: results}
+}
+
+// deployWorkflow Registers the functions of the workflow and then the workflow, whose compositions replace the
+// functions of the configuration. The workflow is not registered if any of its functions failed to register.
+func (d *dirigentDeployer) deployWorkflow(cfg *config.Configuration, dirigentDeployerConfig dirigentDeploymentConfiguration) DeploymentResult {
+	wfConfigPath := cfg.DirigentConfiguration.WorkflowConfigPath
+	if wfConfigPath == "" {
+		log.Fatalf("Failed to deploy workflow: no workflow config path specified in config file.")
+	}
+	wfConfig := config.ReadWorkflowConfig(wfConfigPath)
+
+	start := time.Now()
 	endpoint := ""
+	result := DeploymentResult{}
+
+	// deploy workflow functions
+	for _, wfFunc := range wfConfig.Functions {
+		functionStart := time.Now()
+		tmpFunction := dirigentWorkflowFunction(cfg.Functions[0], wfFunc)
+		err := deployDirigentFunction(
+			tmpFunction,
+			wfFunc.FunctionPath,
+			dirigentDeployerConfig.RegistrationServer,
+			cfg.DirigentConfiguration.BusyLoopOnSandboxStartup,
+			cfg.DirigentConfiguration.PrepullMode,
+			cfg.DirigentConfiguration.RpsRequestedGpu,
+		)
+		if err != nil {
+			result.Add(tmpFunction, functionStart, err)
+		}
+		endpoint = tmpFunction.Endpoint
+	}
 
-	if dirigentDeployerConfig.deployWorkflow {
-		wfConfigPath := cfg.DirigentConfiguration.WorkflowConfigPath
-		if wfConfigPath == "" {
-			log.Fatalf("Failed to deploy workflow: no workflow config path specified in config file.")
+	if failed := len(result.Functions); failed > 0 {
+		result.Add(cfg.Functions[0], start, fmt.Errorf("%d functions of the workflow failed to register", failed))
+		return result
+	}
+
+	// deploy workflow (stored as configuration functions)
+	compositionNames, err := deployDirigentWorkflow(
+		cfg.Functions[0],
+		dirigentDeployerConfig.RegistrationServer,
+	)
+	if err != nil {
+		return newDeploymentResult(cfg.Functions[:1], start, err)
+	}
+
+	// create a function for each registered composition
+	newFunctions := make([]*common.Function, len(compositionNames))
+	for i, compositionName := range compositionNames {
+		newFunctions[i] = cfg.Functions[0]
+		newFunctions[i].Endpoint = endpoint
+		newFunctions[i].Name = compositionName
+		newFunctions[i].WorkflowMetadata = &common.WorkflowMetadata{
+			InvocationRequest: clients.WorkflowInvocationBody(
+				compositionName,
+				clients.CreateDandelionRequest(compositionName, wfConfig.Compositions[i].InData),
+			),
 		}
-		wfConfig := config.ReadWorkflowConfig(wfConfigPath)
+	}
+	cfg.Functions = newFunctions
 
-		dMetadata := cfg.Functions[0].DirigentMetadata
-		if dMetadata == nil {
-			log.Fatalf("No Dirigent metadata for workflow %s", cfg.Functions[0].Name)
+	return newDeploymentResult(newFunctions, start, nil)
+}
+
+// Render Writes the registration form of each function, and of the workflow in the workflow mode, as JSON
+func (d *dirigentDeployer) Render(cfg *config.Configuration, dir string) error {
+	dirigentConfig := cfg.DirigentConfiguration
+
+	functions := cfg.Functions
+	if dirigentConfig.Workflow {
+		if dirigentConfig.WorkflowConfigPath == "" {
+			return fmt.Errorf("no workflow config path specified in config file")
 		}
-		tmpNumArgs := dMetadata.NumArgs
-		tmpNumRets := dMetadata.NumRets
+		if cfg.Functions[0].DirigentMetadata == nil {
+			return fmt.Errorf("no Dirigent metadata for workflow %s", cfg.Functions[0].Name)
+		}
+		wfConfig := config.ReadWorkflowConfig(dirigentConfig.WorkflowConfigPath)
 
-		// deploy workflow functions
+		functions = nil
 		for _, wfFunc := range wfConfig.Functions {
-			dMetadata.NumArgs = wfFunc.NumArgs
-			dMetadata.NumRets = wfFunc.NumRets
-			tmpFunction := &common.Function{
-				Name:                wfFunc.FunctionName,
-				CPURequestsMilli:    cfg.Functions[0].CPURequestsMilli,  // NOTE: using first function for now as
-				MemoryRequestsMiB:   cfg.Functions[0].MemoryRequestsMiB, // those values are the same for all functions
-				ColdStartBusyLoopMs: cfg.Functions[0].ColdStartBusyLoopMs,
-				DirigentMetadata:    dMetadata,
-			}
-			deployDirigentFunction(
-				tmpFunction,
-				wfFunc.FunctionPath,
-				dirigentDeployerConfig.RegistrationServer,
-				cfg.DirigentConfiguration.BusyLoopOnSandboxStartup,
-				cfg.DirigentConfiguration.PrepullMode,
-				cfg.DirigentConfiguration.RpsRequestedGpu,
-			)
-			endpoint = tmpFunction.Endpoint
+			function := dirigentWorkflowFunction(cfg.Functions[0], wfFunc)
+			function.DirigentMetadata.Image = wfFunc.FunctionPath
+			functions = append(functions, function)
 		}
-		dMetadata.NumArgs = tmpNumArgs
-		dMetadata.NumRets = tmpNumRets
 
-		// deploy workflow (stored as configuration functions)
-		compositionNames := deployDirigentWorkflow(
-			cfg.Functions[0],
-			dirigentDeployerConfig.RegistrationServer,
-		)
-		// create a function for each registered composition
-		newFunctions := make([]*common.Function, len(compositionNames))
-		for i, compositionName := range compositionNames {
-			newFunctions[i] = cfg.Functions[0]
-			newFunctions[i].Endpoint = endpoint
-			newFunctions[i].Name = compositionName
-			newFunctions[i].WorkflowMetadata = &common.WorkflowMetadata{
-				InvocationRequest: clients.WorkflowInvocationBody(
-					compositionName,
-					clients.CreateDandelionRequest(compositionName, wfConfig.Compositions[i].InData),
-				),
-			}
+		payload, err := dirigentWorkflowPayload(cfg.Functions[0])
+		if err != nil {
+			return err
 		}
-		cfg.Functions = newFunctions
-
-	} else {
-		wg := &sync.WaitGroup{}
-		wg.Add(len(cfg.Functions))
-
-		for i := 0; i < len(cfg.Functions); i++ {
-			go func(idx int) {
-				defer wg.Done()
-
-				if cfg.Functions[i].DirigentMetadata == nil {
-					log.Fatalf("No Dirigent metadata for function %s", cfg.Functions[i].Name)
-				}
-				deployDirigentFunction(
-					cfg.Functions[idx],
-					cfg.Functions[idx].DirigentMetadata.Image,
-					dirigentDeployerConfig.RegistrationServer,
-					cfg.DirigentConfiguration.BusyLoopOnSandboxStartup,
-					cfg.DirigentConfiguration.PrepullMode,
-					cfg.DirigentConfiguration.RpsRequestedGpu,
-				)
-			}(i)
+		if err = writeRenderedJSON(dir, "workflow-"+cfg.Functions[0].Name+".json", payload); err != nil {
+			return err
 		}
+	}
 
-		wg.Wait()
+	for _, function := range functions {
+		if function.DirigentMetadata == nil {
+			return fmt.Errorf("no Dirigent metadata for function %s", function.Name)
+		}
+
+		payload := dirigentRegistrationPayload(
+			function,
+			function.DirigentMetadata.Image,
+			dirigentConfig.BusyLoopOnSandboxStartup,
+			dirigentConfig.PrepullMode,
+			dirigentConfig.RpsRequestedGpu,
+		)
+		if err := writeRenderedJSON(dir, function.Name+".json", payload); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 func (*dirigentDeployer) Clean() {}
@@ -151,12 +219,28 @@ var checkClient = &http.Client{
 	},
 }
 
-func deployDirigentFunction(function *common.Function, imagePath string, controlPlaneAddress string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) {
-	metadata := function.DirigentMetadata
+// dirigentWorkflowFunction Returns a function of the workflow, sized as the first function of the trace
+func dirigentWorkflowFunction(wf *common.Function, wfFunc config.WorkflowFunction) *common.Function {
+	if wf.DirigentMetadata == nil {
+		log.Fatalf("No Dirigent metadata for workflow %s", wf.Name)
+	}
 
-	if metadata == nil {
-		log.Fatalf("No Dirigent metadata for function %s", function.Name)
+	metadata := *wf.DirigentMetadata
+	metadata.NumArgs = wfFunc.NumArgs
+	metadata.NumRets = wfFunc.NumRets
+
+	return &common.Function{
+		Name:                wfFunc.FunctionName,
+		CPURequestsMilli:    wf.CPURequestsMilli,  // NOTE: using first function for now as
+		MemoryRequestsMiB:   wf.MemoryRequestsMiB, // those values are the same for all functions
+		ColdStartBusyLoopMs: wf.ColdStartBusyLoopMs,
+		DirigentMetadata:    &metadata,
 	}
+}
+
+// dirigentRegistrationPayload Returns the form by which the function is registered with the control plane
+func dirigentRegistrationPayload(function *common.Function, imagePath string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) url.Values {
+	metadata := function.DirigentMetadata
 
 	payload := url.Values{
 		"name":                {function.Name},
@@ -179,34 +263,41 @@ func deployDirigentFunction(function *common.Function, imagePath string, control
 		payload["cold_start_busy_loop_ms"] = []string{strconv.Itoa(function.ColdStartBusyLoopMs)}
 	}
 
+	return payload
+}
+
+func deployDirigentFunction(function *common.Function, imagePath string, controlPlaneAddress string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) error {
+	if function.DirigentMetadata == nil {
+		return fmt.Errorf("no Dirigent metadata for function %s", function.Name)
+	}
+
+	payload := dirigentRegistrationPayload(function, imagePath, busyLoopOnColdStart, prepullMode, requestedGpu)
 	log.Debug(payload)
 
 	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/", controlPlaneAddress), payload)
 	if err != nil {
-		log.Error("Failed to register a service with the control plane - ", err.Error())
-		return
+		return fmt.Errorf("failed to register a service with the control plane - %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Error("Failed to read response body.")
-		return
+		return fmt.Errorf("failed to read response body - %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Errorf("Got status code %d while registering %s. Body: %s", resp.StatusCode, function.Name, body)
-		return
+		return fmt.Errorf("got status code %d while registering %s. Body: %s", resp.StatusCode, function.Name, body)
 	}
 
 	endpoints := strings.Split(string(body), ";")
-	if len(endpoints) == 0 {
-		log.Error("Function registration returned no data plane(s).")
-		return
+	if len(endpoints) == 0 || endpoints[0] == "" {
+		return fmt.Errorf("function registration returned no data plane(s)")
 	}
 	function.Endpoint = endpoints[rand.Intn(len(endpoints))]
 
 	checkForRegistration(controlPlaneAddress, function.Name, prepullMode)
+
+	return nil
 }
 
 func checkForRegistration(controlPlaneAddress, functionName, prepullMode string) {
@@ -231,36 +322,45 @@ func checkForRegistration(controlPlaneAddress, functionName, prepullMode string)
 	}
 }
 
-func deployDirigentWorkflow(wf *common.Function, controlPlaneAddress string) []string {
+// dirigentWorkflowPayload Returns the form by which the workflow is registered with the control plane
+func dirigentWorkflowPayload(wf *common.Function) (url.Values, error) {
 	metadata := wf.DirigentMetadata
 	if metadata == nil {
-		log.Fatalf("No Dirigent metadata for workflow %s", wf.Name)
+		return nil, fmt.Errorf("no Dirigent metadata for workflow %s", wf.Name)
 	}
 
 	wfDescription, err := os.ReadFile(metadata.Image)
 	if err != nil {
-		log.Fatalf("Failed to read workflow description file '%s' : %v", metadata.Image, err)
+		return nil, fmt.Errorf("failed to read workflow description file '%s' : %v", metadata.Image, err)
 	}
-	payload := url.Values{
+
+	return url.Values{
 		"name":     {wf.Name},
 		"workflow": {string(wfDescription)},
+	}, nil
+}
+
+func deployDirigentWorkflow(wf *common.Function, controlPlaneAddress string) ([]string, error) {
+	payload, err := dirigentWorkflowPayload(wf)
+	if err != nil {
+		return nil, err
 	}
 
 	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/workflow", controlPlaneAddress), payload)
 	if err != nil {
-		log.Fatalf("Failed to register a workflow with the control plane - %v", err)
+		return nil, fmt.Errorf("failed to register a workflow with the control plane - %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalf("Failed to read response body.")
+		return nil, fmt.Errorf("failed to read response body - %w", err)
 	}
 
 	registeredCompositions := strings.Split(string(body), ";")
 	if len(registeredCompositions) == 0 {
-		log.Fatalf("Workflow registration returned zero registered workflows.")
+		return nil, fmt.Errorf("workflow registration returned zero registered workflows")
 	}
 
-	return registeredCompositions
+	return registeredCompositions, nil
 }
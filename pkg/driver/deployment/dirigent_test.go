@@ -0,0 +1,109 @@
+package deployment
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestDirigentDeployerRender(t *testing.T) {
+	function := &common.Function{
+		Name:                "test-function",
+		CPURequestsMilli:    100,
+		MemoryRequestsMiB:   256,
+		ColdStartBusyLoopMs: 50,
+		DirigentMetadata: &common.DirigentMetadata{
+			Image:               "docker.io/test/image:latest",
+			Port:                80,
+			Protocol:            "tcp",
+			ScalingUpperBound:   10,
+			ScalingLowerBound:   1,
+			IterationMultiplier: 102,
+			EnvVars:             []string{"a=1", "b=2"},
+		},
+	}
+
+	tests := []struct {
+		testName string
+		busyLoop bool
+		expected map[string]string
+	}{
+		{testName: "registration", expected: map[string]string{"image": "docker.io/test/image:latest", "requested_cpu": "100", "requested_memory": "256", "scaling_lower_bound": "1", "prepull_mode": "none"}},
+		{testName: "busy_loop_on_startup", busyLoop: true, expected: map[string]string{"cold_start_busy_loop_ms": "50", "iteration_multiplier": "102"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cfg := &config.Configuration{
+				DirigentConfiguration: &config.DirigentConfig{BusyLoopOnSandboxStartup: test.busyLoop, PrepullMode: "none"},
+				Functions:             []*common.Function{function},
+			}
+
+			dir := t.TempDir()
+			if err := newDirigentDeployer().Render(cfg, dir); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "test-function.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload url.Values
+			if err = json.Unmarshal(data, &payload); err != nil {
+				t.Fatal(err)
+			}
+
+			for key, value := range test.expected {
+				if payload.Get(key) != value {
+					t.Errorf("Unexpected %s - expected: %s; got: %s", key, value, payload.Get(key))
+				}
+			}
+			if !reflect.DeepEqual(payload["env_vars"], []string{"a=1", "b=2"}) || !reflect.DeepEqual(payload["port_forwarding"], []string{"80", "tcp"}) {
+				t.Errorf("Unexpected registration payload %v", payload)
+			}
+			if _, ok := payload["cold_start_busy_loop_ms"]; ok != test.busyLoop {
+				t.Errorf("Unexpected busy loop in registration payload %v", payload)
+			}
+		})
+	}
+}
+
+func TestDirigentDeployerRegistrationFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ParseForm() != nil || r.PostForm.Get("name") == "failing-function" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write([]byte("10.0.0.1:8080"))
+	}))
+	defer server.Close()
+
+	metadata := &common.DirigentMetadata{Port: 80, Protocol: "tcp"}
+	cfg := &config.Configuration{
+		DirigentConfiguration: &config.DirigentConfig{DirigentControlPlaneIP: server.Listener.Addr().String()},
+		Functions: []*common.Function{
+			{Name: "test-function", DirigentMetadata: metadata},
+			{Name: "failing-function", DirigentMetadata: metadata},
+			{Name: "no-metadata"},
+		},
+	}
+
+	result := newDirigentDeployer().Deploy(cfg)
+
+	failed := result.Failed()
+	if len(result.Functions) != 3 || len(failed) != 2 || failed[0].Function != "failing-function" || failed[1].Function != "no-metadata" {
+		t.Fatalf("Unexpected deployment result %+v", result)
+	}
+	if cfg.Functions[0].Endpoint != "10.0.0.1:8080" || cfg.Functions[1].Endpoint != "" {
+		t.Errorf("Unexpected endpoints %s and %s", cfg.Functions[0].Endpoint, cfg.Functions[1].Endpoint)
+	}
+}
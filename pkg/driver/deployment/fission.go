@@ -0,0 +1,262 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	fissionFunctionPort = 80
+	fissionReadyTimeout = 5 * time.Minute
+	// fissionLoaderLabel Label of the resources the loader creates, by which they are cleaned up
+	fissionLoaderLabel = "vhive-loader"
+)
+
+type fissionObjectMeta struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type fissionResource struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   fissionObjectMeta `json:"metadata"`
+	Spec       interface{}       `json:"spec"`
+}
+
+type fissionResourceList struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Items      []*fissionResource `json:"items"`
+}
+
+type fissionDeploymentList struct {
+	Items []struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas int `json:"replicas"`
+		} `json:"spec"`
+		Status struct {
+			Replicas            int `json:"replicas"`
+			ReadyReplicas       int `json:"readyReplicas"`
+			UnavailableReplicas int `json:"unavailableReplicas"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// fissionDeployer deploys the trace function as a Fission container function exposed on the router through an HTTP
+// trigger. The Function and HTTPTrigger resources are applied with kubectl, and the scale of the functions is read
+// from the deployments the container executor creates.
+type fissionDeployer struct {
+	router    string
+	namespace string
+
+	// deployed Functions applied by this run, to which the deployment scales are limited
+	deployed map[string]bool
+}
+
+func newFissionDeployer(cfg *config.LoaderConfiguration) *fissionDeployer {
+	namespace := cfg.FissionNamespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return &fissionDeployer{
+		router:    cfg.FissionRouter,
+		namespace: namespace,
+		deployed:  make(map[string]bool),
+	}
+}
+
+func fissionContainerEnvironment(function *common.Function) []map[string]string {
+	environment := traceFunctionEnvironment(function, fissionFunctionPort)
+
+	names := make([]string, 0, len(environment))
+	for name := range environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, map[string]string{"name": name, "value": environment[name]})
+	}
+
+	return result
+}
+
+// functionResources Returns the Function and HTTPTrigger resources of the function
+func (d *fissionDeployer) functionResources(function *common.Function, image string, timeoutSeconds int) *fissionResourceList {
+	metadata := fissionObjectMeta{
+		Name:      function.Name,
+		Namespace: d.namespace,
+		Labels:    map[string]string{fissionLoaderLabel: "true"},
+	}
+
+	return &fissionResourceList{
+		APIVersion: "v1",
+		Kind:       "List",
+		Items: []*fissionResource{
+			{
+				APIVersion: "fission.io/v1",
+				Kind:       "Function",
+				Metadata:   metadata,
+				Spec: map[string]interface{}{
+					"environment": map[string]string{"name": "", "namespace": ""},
+					"InvokeStrategy": map[string]interface{}{
+						"StrategyType": "execution",
+						"ExecutionStrategy": map[string]interface{}{
+							"ExecutorType": "container",
+							"MinScale":     common.MaxOf(function.InitialScale, 1),
+							"MaxScale":     maxFunctionScale,
+						},
+					},
+					"functionTimeout": timeoutSeconds,
+					"podspec": map[string]interface{}{
+						"containers": []map[string]interface{}{
+							{
+								"name":  function.Name,
+								"image": image,
+								"ports": []map[string]interface{}{
+									{"name": "http-env", "containerPort": fissionFunctionPort},
+								},
+								"env": fissionContainerEnvironment(function),
+								"resources": map[string]interface{}{
+									"requests": map[string]string{
+										"cpu":    strconv.Itoa(function.CPURequestsMilli) + "m",
+										"memory": strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+									},
+									"limits": map[string]string{
+										"cpu": strconv.Itoa(function.CPULimitsMilli) + "m",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				APIVersion: "fission.io/v1",
+				Kind:       "HTTPTrigger",
+				Metadata:   metadata,
+				Spec: map[string]interface{}{
+					"functionref": map[string]string{"name": function.Name, "type": "name"},
+					"methods":     []string{"POST"},
+					"relativeurl": "/" + function.Name,
+				},
+			},
+		},
+	}
+}
+
+func (d *fissionDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
+	image := traceFunctionImage(cfg.LoaderConfiguration)
+
+	start := time.Now()
+	errs := make(map[string]error)
+	var deployed []*common.Function
+
+	for _, function := range cfg.Functions {
+		resources, err := json.Marshal(d.functionResources(function, image, cfg.LoaderConfiguration.GRPCFunctionTimeoutSeconds))
+		if err != nil {
+			errs[function.Name] = fmt.Errorf("failed to create the Fission resources - %w", err)
+			continue
+		}
+
+		cmd := exec.Command("kubectl", "apply", "-f", "-")
+		cmd.Stdin = bytes.NewReader(resources)
+
+		stdoutStderr, err := cmd.CombinedOutput()
+		log.Debug("CMD response: ", string(stdoutStderr))
+		if err != nil {
+			errs[function.Name] = fmt.Errorf("failed to deploy on Fission: %w - %s", err, stdoutStderr)
+			continue
+		}
+
+		function.Endpoint = fmt.Sprintf("%s/%s", d.router, function.Name)
+		deployed = append(deployed, function)
+		d.deployed[function.Name] = true
+		log.Debugf("Deployed function on %s\n", function.Endpoint)
+	}
+
+	notReady := waitForReadiness(d, deployed, fissionReadyTimeout)
+
+	return readinessResult(cfg.Functions, start, errs, notReady, fissionReadyTimeout)
+}
+
+// Render Writes the resources of each function as they would be applied with kubectl
+func (d *fissionDeployer) Render(cfg *config.Configuration, dir string) error {
+	image := traceFunctionImage(cfg.LoaderConfiguration)
+
+	for _, function := range cfg.Functions {
+		resources := d.functionResources(function, image, cfg.LoaderConfiguration.GRPCFunctionTimeoutSeconds)
+		if err := writeRenderedJSON(dir, function.Name+".json", resources); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *fissionDeployer) Clean() {
+	cmd := exec.Command("kubectl", "delete", "httptriggers.fission.io,functions.fission.io",
+		"-n", d.namespace, "-l", fissionLoaderLabel+"=true")
+
+	if stdoutStderr, err := cmd.CombinedOutput(); err != nil {
+		log.Errorf("Unable to delete Fission functions - %v - %s", err, stdoutStderr)
+	}
+}
+
+// DeploymentScales Reads the scale of the deployments the container executor created for the functions this run
+// deployed. The deployments are looked up in all namespaces, as the executor may create them outside the namespace of
+// the functions, but are selected by the namespace of the functions they belong to.
+func (d *fissionDeployer) DeploymentScales() ([]metric.DeploymentScale, error) {
+	cmd := exec.Command("kubectl", "get", "deployments", "--all-namespaces",
+		"-l", "executorType=container,functionNamespace="+d.namespace, "-o", "json")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFissionDeploymentScales(out, d.deployed)
+}
+
+// parseFissionDeploymentScales Returns the scale of the deployments of the given functions
+func parseFissionDeploymentScales(data []byte, functions map[string]bool) ([]metric.DeploymentScale, error) {
+	var deployments fissionDeploymentList
+	if err := json.Unmarshal(data, &deployments); err != nil {
+		return nil, err
+	}
+
+	var result []metric.DeploymentScale
+	for _, deployment := range deployments.Items {
+		name, ok := deployment.Metadata.Labels["functionName"]
+		if !ok || !functions[name] {
+			continue
+		}
+
+		result = append(result, metric.DeploymentScale{
+			Function:        name,
+			DesiredPods:     deployment.Spec.Replicas,
+			RunningPods:     deployment.Status.ReadyReplicas,
+			UnreadyPods:     deployment.Status.UnavailableReplicas,
+			TerminatingPods: common.MaxOf(deployment.Status.Replicas-deployment.Spec.Replicas, 0),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package deployment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestFissionFunctionResources(t *testing.T) {
+	deployer := newFissionDeployer(&config.LoaderConfiguration{FissionRouter: "router.fission"})
+	function := &common.Function{Name: "test-function", InitialScale: 0, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128}
+
+	data, err := json.Marshal(deployer.functionResources(function, "trace-function:latest", 60))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resources struct {
+		Items []struct {
+			Kind     string                 `json:"kind"`
+			Metadata fissionObjectMeta      `json:"metadata"`
+			Spec     map[string]interface{} `json:"spec"`
+		} `json:"items"`
+	}
+	if err = json.Unmarshal(data, &resources); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resources.Items) != 2 || resources.Items[0].Kind != "Function" || resources.Items[1].Kind != "HTTPTrigger" {
+		t.Fatalf("Unexpected resources %s", data)
+	}
+
+	for _, resource := range resources.Items {
+		if resource.Metadata.Namespace != "default" || resource.Metadata.Labels[fissionLoaderLabel] != "true" {
+			t.Errorf("Unexpected metadata of %s - %+v", resource.Kind, resource.Metadata)
+		}
+	}
+
+	strategy := resources.Items[0].Spec["InvokeStrategy"].(map[string]interface{})["ExecutionStrategy"].(map[string]interface{})
+	if strategy["ExecutorType"] != "container" || strategy["MinScale"] != 1.0 || strategy["MaxScale"] != float64(maxFunctionScale) {
+		t.Errorf("Unexpected execution strategy %v", strategy)
+	}
+	if resources.Items[1].Spec["relativeurl"] != "/test-function" {
+		t.Errorf("Unexpected HTTP trigger %v", resources.Items[1].Spec)
+	}
+}
+
+func TestParseFissionDeploymentScales(t *testing.T) {
+	data := []byte(`{"items": [
+		{"metadata": {"labels": {"functionName": "f1", "executorType": "container"}},
+		 "spec": {"replicas": 3}, "status": {"replicas": 4, "readyReplicas": 2, "unavailableReplicas": 1}},
+		{"metadata": {"labels": {"executorType": "container"}}, "spec": {"replicas": 1}, "status": {}},
+		{"metadata": {"labels": {"functionName": "other", "executorType": "container"}},
+		 "spec": {"replicas": 1}, "status": {"replicas": 1, "readyReplicas": 1}}
+	]}`)
+
+	// the function other was not deployed by this run
+	scales, err := parseFissionDeploymentScales(data, map[string]bool{"f1": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scales) != 1 || scales[0].Function != "f1" || scales[0].DesiredPods != 3 || scales[0].RunningPods != 2 ||
+		scales[0].UnreadyPods != 1 || scales[0].TerminatingPods != 1 {
+
+		t.Errorf("Unexpected deployment scales %+v", scales)
+	}
+}
@@ -1,40 +1,126 @@
 package deployment
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"os"
 	"os/exec"
-	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/go-cmd/cmd"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	bareMetalLbGateway = "10.200.3.4.sslip.io" // Address of the bare-metal load balancer.
 	namespace          = "default"
+
+	// knativeExperimentLabel Label of the resources the loader creates, holding the ID of the experiment by which
+	// they are cleaned up
+	knativeExperimentLabel = "loader.vhive-serverless.io/experiment"
+	// knativeMinScaleAnnotation Per-revision minimum scale of a Knative service
+	knativeMinScaleAnnotation = "autoscaling.knative.dev/min-scale"
+	// knativeAutoscalingAnnotationPrefix Domain of the annotations configuring the autoscaling of a revision
+	knativeAutoscalingAnnotationPrefix = "autoscaling.knative.dev/"
+	// knativeResetAnnotation Time at which a reused service was last reset, changed to roll out a new revision that
+	// starts at the initial scale
+	knativeResetAnnotation = "loader.vhive-serverless.io/reset"
+
+	defaultKnativeReadyTimeout = 5 * time.Minute
 )
 
 var (
-	urlRegex = regexp.MustCompile("at URL:\nhttp://([^\n]+)")
+	knativeServiceResource = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
+	knativeTriggerResource = schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1", Resource: "triggers"}
+	// knativePodAutoscalerResource Autoscaler of a revision, reporting its actual scale
+	knativePodAutoscalerResource = schema.GroupVersionResource{Group: "autoscaling.internal.knative.dev", Version: "v1alpha1", Resource: "podautoscalers"}
+
+	// knativeBackoff Backoff of retrying the requests to the API server
+	knativeBackoff = wait.Backoff{Steps: 5, Duration: time.Second, Factor: 2, Jitter: 0.1}
 )
 
-type knativeDeployer struct{}
+// knativeDeployer deploys the functions as Knative services created through the Kubernetes API from the YAML template
+// of the function. All the resources are labeled with the ID of the experiment, so that only they are cleaned up.
+// In the reuse mode, the services of a previous experiment are patched and reset instead, and kept once it ends.
+type knativeDeployer struct {
+	client         dynamic.Interface
+	kubeconfigPath string
+	experimentID   string
+	reuse          bool
+	readyTimeout   time.Duration
+	pollInterval   time.Duration
+	backoff        wait.Backoff
+
+	// manifests applied with kubectl before deploying the functions, e.g., the databases of vSwarm functions
+	predeployments []string
+
+	// services rendered for the deployed functions by name, holding the minimum scale of their template
+	services      map[string]*unstructured.Unstructured
+	servicesMutex sync.Mutex
+}
 
 type knativeDeploymentConfiguration struct {
 	IsPartiallyPanic  bool
 	EndpointPort      int
 	AutoscalingMetric string
+
+	AsyncMode  bool
+	BrokerName string
+}
+
+func newKnativeDeployer(cfg *config.LoaderConfiguration) *knativeDeployer {
+	experimentID := cfg.ExperimentID
+	if experimentID == "" {
+		experimentID = time.Now().UTC().Format("20060102-150405")
+	}
+
+	readyTimeout := time.Duration(cfg.KnativeReadyTimeoutSeconds) * time.Second
+	if readyTimeout <= 0 {
+		readyTimeout = defaultKnativeReadyTimeout
+	}
+
+	return &knativeDeployer{
+		kubeconfigPath: cfg.KubeconfigPath,
+		experimentID:   experimentID,
+		reuse:          cfg.ReuseDeployment,
+		readyTimeout:   readyTimeout,
+		pollInterval:   time.Second,
+		backoff:        knativeBackoff,
+		services:       make(map[string]*unstructured.Unstructured),
+	}
 }
 
-func newKnativeDeployer() *knativeDeployer {
-	return &knativeDeployer{}
+// newKnativeClient Creates a client from the kubeconfig at the path, the KUBECONFIG environment variable or
+// ~/.kube/config, in that order, falling back to the in-cluster configuration
+func newKnativeClient(kubeconfigPath string) (dynamic.Interface, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfigPath
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(restConfig)
 }
 
 func newKnativeDeployerConfiguration(cfg *config.Configuration) knativeDeploymentConfiguration {
@@ -42,11 +128,32 @@ func newKnativeDeployerConfiguration(cfg *config.Configuration) knativeDeploymen
 		IsPartiallyPanic:  cfg.LoaderConfiguration.IsPartiallyPanic,
 		EndpointPort:      cfg.LoaderConfiguration.EndpointPort,
 		AutoscalingMetric: cfg.LoaderConfiguration.AutoscalingMetric,
+
+		AsyncMode:  cfg.LoaderConfiguration.KnativeAsyncMode,
+		BrokerName: cfg.LoaderConfiguration.KnativeBrokerName,
 	}
 }
 
-func (*knativeDeployer) Deploy(cfg *config.Configuration) {
+func (d *knativeDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
+	if d.client == nil {
+		client, err := newKnativeClient(d.kubeconfigPath)
+		if err != nil {
+			return newDeploymentResult(cfg.Functions, time.Now(), fmt.Errorf("failed to create a Kubernetes client - %w", err))
+		}
+		d.client = client
+	}
+
+	log.Infof("Deploying %d functions as experiment %s", len(cfg.Functions), d.experimentID)
+
 	knativeConfig := newKnativeDeployerConfiguration(cfg)
+	d.applyPredeployments(cfg.Functions)
+
+	var existing map[string]*unstructured.Unstructured
+	if d.reuse {
+		existing = d.discoverServices()
+	}
+
+	results := make([]FunctionDeploymentResult, len(cfg.Functions))
 
 	queue := make(chan struct{}, runtime.NumCPU()) // message queue as a sync method
 	deployed := sync.WaitGroup{}
@@ -59,99 +166,638 @@ func (*knativeDeployer) Deploy(cfg *config.Configuration) {
 			defer deployed.Done()
 			defer func() { <-queue }()
 
-			knativeDeploySingleFunction(
-				cfg.Functions[i],
-				cfg.Functions[i].YAMLPath,
-				knativeConfig.IsPartiallyPanic,
-				knativeConfig.EndpointPort,
-				knativeConfig.AutoscalingMetric,
-			)
+			results[i] = d.deployFunction(cfg.Functions[i], knativeConfig, existing)
 		}()
 	}
 
 	deployed.Wait()
+
+	return DeploymentResult{Functions: results}
 }
 
-func (*knativeDeployer) Clean() {
-	cmd := exec.Command("kn", "service", "delete", "--all")
+// Render Writes the Knative service of each function as it would be applied, followed by its trigger in the
+// asynchronous mode
+func (d *knativeDeployer) Render(cfg *config.Configuration, dir string) error {
+	knativeConfig := newKnativeDeployerConfiguration(cfg)
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	for _, function := range cfg.Functions {
+		service, err := d.renderService(function, knativeConfig)
+		if err != nil {
+			return fmt.Errorf("failed to render function %s - %w", function.Name, err)
+		}
+
+		objects := []*unstructured.Unstructured{service}
+		if knativeConfig.AsyncMode {
+			objects = append(objects, d.trigger(function, service.GetNamespace(), knativeConfig.BrokerName))
+		}
 
-	if err := cmd.Run(); err != nil {
-		log.Errorf("Unable to delete Knative services - %s", err)
+		var documents []string
+		for _, object := range objects {
+			data, err := yaml.Marshal(object.Object)
+			if err != nil {
+				return err
+			}
+			documents = append(documents, string(data))
+		}
+
+		if err = writeRendered(dir, function.Name+".yaml", []byte(strings.Join(documents, "---\n"))); err != nil {
+			return err
+		}
 	}
-	preDepCmd := exec.Command("kubectl", "delete", "pods", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+
+	return nil
+}
+
+// deployFunction Deploys the function, or reuses its existing service in the reuse mode
+func (d *knativeDeployer) deployFunction(function *common.Function, knativeConfig knativeDeploymentConfiguration, existing map[string]*unstructured.Unstructured) FunctionDeploymentResult {
+	start := time.Now()
+	result := FunctionDeploymentResult{Function: function.Name}
+
+	service, err := d.renderService(function, knativeConfig)
+	if err != nil {
+		result.Err = err
+		return result
 	}
-	preDepCmd = exec.Command("kubectl", "delete", "services", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+	d.servicesMutex.Lock()
+	d.services[function.Name] = service
+	d.servicesMutex.Unlock()
+
+	var current *unstructured.Unstructured
+	if d.reuse {
+		current, err = d.findService(service, existing)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to look up the existing service - %w", err)
+			return result
+		}
 	}
+
+	result.Err = retry.OnError(d.backoff, isRetriable, func() error {
+		result.Attempts++
+		if current != nil {
+			return d.reuseService(function, service, current)
+		}
+		return d.apply(knativeServiceResource, service)
+	})
+	result.Reused = current != nil
+	if result.Err != nil {
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// the endpoint is set even if the service does not become ready in time, as it may still do so during the warmup
+	// if the failed functions are redeployed
+	url, err := d.waitForReady(service.GetNamespace(), function.Name)
+	function.Endpoint = knativeEndpoint(url, function.Name, service.GetNamespace(), knativeConfig.EndpointPort)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	log.Debugf("Deployed function on %s\n", function.Endpoint)
+
+	if knativeConfig.AsyncMode {
+		trigger := d.trigger(function, service.GetNamespace(), knativeConfig.BrokerName)
+		if result.Err = retry.OnError(d.backoff, isRetriable, func() error {
+			return d.apply(knativeTriggerResource, trigger)
+		}); result.Err != nil {
+			result.Err = fmt.Errorf("failed to create the trigger - %w", result.Err)
+			return result
+		}
+	}
+
+	result.Deployed = true
+
+	return result
 }
 
-func knativeDeploySingleFunction(function *common.Function, yamlPath string, isPartiallyPanic bool, endpointPort int, autoscalingMetric string) bool {
+// discoverServices Returns the services labeled with the ID of the experiment, by namespace and name
+func (d *knativeDeployer) discoverServices() map[string]*unstructured.Unstructured {
+	existing := make(map[string]*unstructured.Unstructured)
+
+	selector := metav1.ListOptions{LabelSelector: knativeExperimentLabel + "=" + d.experimentID}
+	list, err := d.client.Resource(knativeServiceResource).List(context.Background(), selector)
+	if err != nil {
+		log.Warnf("Unable to list the services of experiment %s, looking them up by name instead - %v", d.experimentID, err)
+		return existing
+	}
+
+	for i := range list.Items {
+		existing[list.Items[i].GetNamespace()+"/"+list.Items[i].GetName()] = &list.Items[i]
+	}
+	log.Infof("Found %d services of experiment %s to reuse", len(existing), d.experimentID)
+
+	return existing
+}
+
+// findService Returns the existing service of the function, discovered by the label of the experiment or otherwise
+// looked up by name, or nil if the function is not deployed
+func (d *knativeDeployer) findService(service *unstructured.Unstructured, existing map[string]*unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if current, ok := existing[service.GetNamespace()+"/"+service.GetName()]; ok {
+		return current, nil
+	}
+
+	current, err := d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace()).Get(context.Background(), service.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return current, err
+}
+
+// reuseService Updates the existing service of the function if it differs from the requested one, which rolls out a
+// new revision, and otherwise rolls one out only if the scale of the service is not the initial scale of the function
+func (d *knativeDeployer) reuseService(function *common.Function, service *unstructured.Unstructured, current *unstructured.Unstructured) error {
+	if differences := knativeServiceDifferences(service, current); len(differences) > 0 {
+		log.Debugf("Updating the service of function %s, which differs in %s", function.Name, strings.Join(differences, ", "))
+
+		updated := service.DeepCopy()
+		updated.SetResourceVersion(current.GetResourceVersion())
+		_, err := d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace()).Update(context.Background(), updated, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			// modified since it was discovered, so retried on the latest version
+			if latest, getErr := d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace()).Get(context.Background(), service.GetName(), metav1.GetOptions{}); getErr == nil {
+				*current = *latest
+			}
+		}
+
+		return err
+	}
+
+	scale, err := d.actualScale(current)
+	if err == nil && scale == int64(function.InitialScale) {
+		log.Debugf("Reusing the service of function %s as is", function.Name)
+		return nil
+	} else if err != nil {
+		log.Debugf("Unable to determine the scale of function %s, resetting it - %v", function.Name, err)
+	} else {
+		log.Debugf("Resetting the scale of function %s from %d to %d", function.Name, scale, function.InitialScale)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{knativeResetAnnotation: time.Now().UTC().Format(time.RFC3339Nano)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace()).Patch(context.Background(), service.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+// actualScale Returns the number of instances of the latest ready revision of the service, as reported by its
+// autoscaler
+func (d *knativeDeployer) actualScale(service *unstructured.Unstructured) (int64, error) {
+	revision, _, _ := unstructured.NestedString(service.Object, "status", "latestReadyRevisionName")
+	if revision == "" {
+		return 0, errors.New("no ready revision")
+	}
+
+	autoscaler, err := d.client.Resource(knativePodAutoscalerResource).Namespace(service.GetNamespace()).Get(context.Background(), revision, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	scale, found, err := unstructured.NestedInt64(autoscaler.Object, "status", "actualScale")
+	if err != nil {
+		return 0, err
+	} else if !found {
+		return 0, errors.New("actual scale not reported")
+	}
+
+	return scale, nil
+}
+
+// knativeServiceDifferences Returns the fields in which the existing service differs from the requested one. The
+// fields the API server defaults or adds are ignored, except for the autoscaling annotations of the revision, such as
+// the minimum scale set during the warmup of a previous experiment.
+func knativeServiceDifferences(service *unstructured.Unstructured, current *unstructured.Unstructured) []string {
+	var differences []string
+
+	for key, value := range service.GetLabels() {
+		if current.GetLabels()[key] != value {
+			differences = append(differences, "label "+key)
+		}
+	}
+
+	requested, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	existing, _, _ := unstructured.NestedStringMap(current.Object, "spec", "template", "metadata", "annotations")
+	for key := range existing {
+		if _, ok := requested[key]; !ok && strings.HasPrefix(key, knativeAutoscalingAnnotationPrefix) {
+			differences = append(differences, "annotation "+key)
+		}
+	}
+	for key, value := range requested {
+		if existing[key] != value {
+			differences = append(differences, "annotation "+key)
+		}
+	}
+
+	requestedSpec, _, _ := unstructured.NestedMap(service.Object, "spec", "template", "spec")
+	existingSpec, _, _ := unstructured.NestedMap(current.Object, "spec", "template", "spec")
+	if !knativeFieldsMatch(requestedSpec, existingSpec) {
+		differences = append(differences, "the revision spec")
+	}
+
+	return differences
+}
+
+// knativeFieldsMatch Tells whether all the requested fields have the same values in the existing object, comparing
+// numbers regardless of their type and resource quantities regardless of their notation
+func knativeFieldsMatch(requested interface{}, existing interface{}) bool {
+	switch value := requested.(type) {
+	case map[string]interface{}:
+		other, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, field := range value {
+			if !knativeFieldsMatch(field, other[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		other, ok := existing.([]interface{})
+		if !ok || len(value) != len(other) {
+			return false
+		}
+		for i := range value {
+			if !knativeFieldsMatch(value[i], other[i]) {
+				return false
+			}
+		}
+		return true
+	case string:
+		other, ok := existing.(string)
+		if !ok {
+			return false
+		}
+		if value == other {
+			return true
+		}
+		requestedQuantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return false
+		}
+		existingQuantity, err := resource.ParseQuantity(other)
+		return err == nil && requestedQuantity.Cmp(existingQuantity) == 0
+	case int64, float64:
+		requestedNumber, ok := knativeNumber(value)
+		existingNumber, otherOk := knativeNumber(existing)
+		return ok && otherOk && requestedNumber == existingNumber
+	default:
+		return requested == existing
+	}
+}
+
+func knativeNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
+
+// isRetriable Tells whether the request to the API server may succeed if retried
+func isRetriable(err error) bool {
+	return !apierrors.IsInvalid(err) && !apierrors.IsBadRequest(err) && !apierrors.IsForbidden(err) &&
+		!apierrors.IsUnauthorized(err) && !apierrors.IsNotFound(err)
+}
+
+// knativeTemplateVariables Values substituted into the YAML template of the function, as by envsubst
+func knativeTemplateVariables(function *common.Function, knativeConfig knativeDeploymentConfiguration) map[string]string {
 	panicWindow := "\"10.0\""
 	panicThreshold := "\"200.0\""
-	if isPartiallyPanic {
+	if knativeConfig.IsPartiallyPanic {
 		panicWindow = "\"100.0\""
 		panicThreshold = "\"1000.0\""
 	}
 	autoscalingTarget := 100 // default for concurrency
-	if autoscalingMetric == "rps" {
+	if knativeConfig.AutoscalingMetric == "rps" {
 		autoscalingTarget = int(math.Round(1000.0 / function.RuntimeStats.Average))
 		// for rps mode use the average runtime in milliseconds to determine how many requests a pod can process per
 		// second, then round to an integer as that is what the knative config expects
 	}
-	for _, path := range function.PredeploymentPath {
-		envCmd := cmd.NewCmd("kubectl", "apply", "-f", path)
-		status := <-envCmd.Start()
 
-		for _, line := range status.Stdout {
-			fmt.Println("Predeployment command response is" + line)
+	return map[string]string{
+		"FUNC_NAME":               function.Name,
+		"CPU_REQUEST":             strconv.Itoa(function.CPURequestsMilli) + "m",
+		"CPU_LIMITS":              strconv.Itoa(function.CPULimitsMilli) + "m",
+		"MEMORY_REQUESTS":         strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		"PANIC_WINDOW":            panicWindow,
+		"PANIC_THRESHOLD":         panicThreshold,
+		"AUTOSCALING_METRIC":      wrapString(knativeConfig.AutoscalingMetric),
+		"AUTOSCALING_TARGET":      wrapString(strconv.Itoa(autoscalingTarget)),
+		"COLD_START_BUSY_LOOP_MS": wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
+	}
+}
+
+// renderService Renders the Knative service of the function from its YAML template
+func (d *knativeDeployer) renderService(function *common.Function, knativeConfig knativeDeploymentConfiguration) (*unstructured.Unstructured, error) {
+	template, err := os.ReadFile(function.YAMLPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the YAML template - %w", err)
+	}
+
+	variables := knativeTemplateVariables(function, knativeConfig)
+	rendered := os.Expand(string(template), func(name string) string {
+		if value, ok := variables[name]; ok {
+			return value
 		}
+		return os.Getenv(name)
+	})
+
+	service := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal([]byte(rendered), &service.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse the rendered YAML template - %w", err)
+	}
+	if service.GetKind() != "Service" {
+		return nil, fmt.Errorf("the YAML template defines a %s instead of a Knative service", service.GetKind())
+	}
+
+	service.SetName(function.Name)
+	if service.GetNamespace() == "" {
+		service.SetNamespace(namespace)
+	}
+
+	labels := service.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[knativeExperimentLabel] = d.experimentID
+	service.SetLabels(labels)
+
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["autoscaling.knative.dev/initial-scale"] = strconv.Itoa(function.InitialScale)
+	if err = unstructured.SetNestedStringMap(service.Object, annotations, "spec", "template", "metadata", "annotations"); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// trigger Subscribes the function to the invocation events addressed to it
+func (d *knativeDeployer) trigger(function *common.Function, namespace string, brokerName string) *unstructured.Unstructured {
+	if brokerName == "" {
+		brokerName = "default"
 	}
-	cmd := exec.Command(
-		"bash",
-		"./pkg/driver/deployment/knative.sh",
-		yamlPath,
-		function.Name,
 
-		strconv.Itoa(function.CPURequestsMilli)+"m",
-		strconv.Itoa(function.CPULimitsMilli)+"m",
-		strconv.Itoa(function.MemoryRequestsMiB)+"Mi",
-		strconv.Itoa(function.InitialScale),
-		panicWindow,
-		panicThreshold,
+	trigger := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "eventing.knative.dev/v1",
+		"kind":       "Trigger",
+		"spec": map[string]interface{}{
+			"broker": brokerName,
+			"filter": map[string]interface{}{
+				"attributes": map[string]interface{}{
+					"type":     common.CloudEventInvocationType,
+					"function": function.Name,
+				},
+			},
+			"subscriber": map[string]interface{}{
+				"ref": map[string]interface{}{
+					"apiVersion": "serving.knative.dev/v1",
+					"kind":       "Service",
+					"name":       function.Name,
+				},
+			},
+		},
+	}}
+	trigger.SetName(function.Name)
+	trigger.SetNamespace(namespace)
+	trigger.SetLabels(map[string]string{knativeExperimentLabel: d.experimentID})
 
-		wrapString(autoscalingMetric),
-		wrapString(strconv.Itoa(autoscalingTarget)),
+	return trigger
+}
 
-		wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
-	)
+// apply Creates the object, or updates it if it already exists
+func (d *knativeDeployer) apply(resource schema.GroupVersionResource, object *unstructured.Unstructured) error {
+	client := d.client.Resource(resource).Namespace(object.GetNamespace())
 
-	stdoutStderr, err := cmd.CombinedOutput()
-	log.Debug("CMD response: ", string(stdoutStderr))
+	_, err := client.Create(context.Background(), object, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, err := client.Get(context.Background(), object.GetName(), metav1.GetOptions{})
 	if err != nil {
-		// TODO: there should be a toggle to turn off deployment because if this is fatal then we cannot test the thing locally
-		log.Warnf("Failed to deploy function %s: %v\n%s\n", function.Name, err, stdoutStderr)
-		return false
-	}
-	if endpoint := urlRegex.FindStringSubmatch(string(stdoutStderr))[1]; endpoint != function.Endpoint {
-		// TODO: check when this situation happens
-		log.Debugf("Update function endpoint to %s\n", endpoint)
-		function.Endpoint = endpoint
-	} else {
-		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, namespace, bareMetalLbGateway)
+		return err
+	}
+
+	updated := object.DeepCopy()
+	updated.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(context.Background(), updated, metav1.UpdateOptions{})
+
+	return err
+}
+
+// waitForReady Waits until the Ready condition of the latest generation of the service is true, returning its URL
+func (d *knativeDeployer) waitForReady(namespace string, name string) (string, error) {
+	client := d.client.Resource(knativeServiceResource).Namespace(namespace)
+
+	var url, reason string
+	err := wait.PollUntilContextTimeout(context.Background(), d.pollInterval, d.readyTimeout, true, func(ctx context.Context) (bool, error) {
+		service, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			reason = err.Error()
+			return false, nil
+		}
+
+		var ready bool
+		ready, reason = knativeReadyCondition(service)
+		url, _, _ = unstructured.NestedString(service.Object, "status", "url")
+
+		return ready, nil
+	})
+	if err != nil {
+		if reason != "" {
+			return "", fmt.Errorf("not ready within %v - %s", d.readyTimeout, reason)
+		}
+		return "", fmt.Errorf("not ready within %v", d.readyTimeout)
+	}
+
+	return url, nil
+}
+
+// knativeReadyCondition Returns whether the service is ready, and the message of its Ready condition otherwise
+func knativeReadyCondition(service *unstructured.Unstructured) (bool, string) {
+	generation := service.GetGeneration()
+	observed, _, _ := unstructured.NestedInt64(service.Object, "status", "observedGeneration")
+	if generation > 0 && observed < generation {
+		return false, "latest generation not yet observed"
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(service.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+
+		if condition["status"] == "True" {
+			return true, ""
+		}
+
+		message, _ := condition["message"].(string)
+		return false, message
+	}
+
+	return false, "no Ready condition"
+}
+
+// knativeEndpoint Returns the address of the function, being the host of the URL of the service if reported, with the
+// port appended
+func knativeEndpoint(url string, name string, namespace string, port int) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://")
+	if host == "" {
+		host = fmt.Sprintf("%s.%s.%s", name, namespace, bareMetalLbGateway)
+	}
+
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// applyPredeployments Applies the manifests the functions depend on, each once
+func (d *knativeDeployer) applyPredeployments(functions []*common.Function) {
+	applied := make(map[string]bool)
+
+	for _, function := range functions {
+		for _, path := range function.PredeploymentPath {
+			if applied[path] {
+				continue
+			}
+			applied[path] = true
+
+			stdoutStderr, err := exec.Command("kubectl", "apply", "-f", path).CombinedOutput()
+			log.Debug("Predeployment command response: ", string(stdoutStderr))
+			if err != nil {
+				log.Warnf("Failed to apply the predeployment manifest %s - %v", path, err)
+				continue
+			}
+
+			d.predeployments = append(d.predeployments, path)
+		}
+	}
+}
+
+func (d *knativeDeployer) Clean() {
+	if d.client == nil {
+		return
+	}
+	if d.reuse {
+		log.Infof("Keeping the services of experiment %s to reuse them", d.experimentID)
+		return
+	}
+
+	selector := metav1.ListOptions{LabelSelector: knativeExperimentLabel + "=" + d.experimentID}
+	for _, resource := range []schema.GroupVersionResource{knativeTriggerResource, knativeServiceResource} {
+		if err := d.deleteLabeled(resource, selector); err != nil {
+			log.Errorf("Unable to delete the %s of experiment %s - %v", resource.Resource, d.experimentID, err)
+		}
+	}
+
+	for _, path := range d.predeployments {
+		if stdoutStderr, err := exec.Command("kubectl", "delete", "--ignore-not-found", "-f", path).CombinedOutput(); err != nil {
+			log.Errorf("Unable to delete the predeployment manifest %s - %v - %s", path, err, stdoutStderr)
+		}
+	}
+}
+
+// deleteLabeled Deletes the resources with the label of the experiment in all namespaces
+func (d *knativeDeployer) deleteLabeled(resource schema.GroupVersionResource, selector metav1.ListOptions) error {
+	list, err := d.client.Resource(resource).List(context.Background(), selector)
+	if apierrors.IsNotFound(err) {
+		// e.g., Knative Eventing is not installed
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, item := range list.Items {
+		err = d.client.Resource(resource).Namespace(item.GetNamespace()).Delete(context.Background(), item.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	log.Debugf("Deleted %d %s", len(list.Items)-len(errs), resource.Resource)
+
+	return errors.Join(errs...)
+}
+
+// UpdateMinScale Sets the minimum scale of the service of the function. As the minimum scale is an annotation of the
+// revision template, each update rolls out a new revision, which starts at the initial scale of the function and takes
+// over the traffic once ready, while the instances of the previous revision are scaled down.
+func (d *knativeDeployer) UpdateMinScale(function *common.Function, minScale int) error {
+	service, err := d.deployedService(function)
+	if err != nil {
+		return err
+	}
+
+	return d.patchMinScale(service, strconv.Itoa(minScale))
+}
+
+// RestoreMinScale Restores the minimum scale from the template of the function, removing it if the template does not
+// set it, which rolls out a new revision as UpdateMinScale does
+func (d *knativeDeployer) RestoreMinScale(function *common.Function) error {
+	service, err := d.deployedService(function)
+	if err != nil {
+		return err
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	if minScale, ok := annotations[knativeMinScaleAnnotation]; ok {
+		return d.patchMinScale(service, minScale)
+	}
+
+	return d.patchMinScale(service, nil)
+}
+
+// deployedService Returns the service deployed for the function, in the namespace of its template
+func (d *knativeDeployer) deployedService(function *common.Function) (*unstructured.Unstructured, error) {
+	d.servicesMutex.Lock()
+	defer d.servicesMutex.Unlock()
+
+	service, ok := d.services[function.Name]
+	if !ok {
+		return nil, fmt.Errorf("function %s was not deployed", function.Name)
+	}
+
+	return service, nil
+}
+
+// patchMinScale Patches the minimum scale annotation of the revision template, removing it if the value is nil
+func (d *knativeDeployer) patchMinScale(service *unstructured.Unstructured, minScale interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{knativeMinScaleAnnotation: minScale},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace()).Patch(context.Background(), service.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update the minimum scale of %s: %v", service.GetName(), err)
 	}
-	// adding port to the endpoint
-	function.Endpoint = fmt.Sprintf("%s:%d", function.Endpoint, endpointPort)
-	log.Debugf("Deployed function on %s\n", function.Endpoint)
 
-	return true
+	return nil
 }
 
 func wrapString(value string) string {
@@ -0,0 +1,469 @@
+package deployment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+const knativeTestTemplate = "../../../workloads/container/trace_func_go.yaml"
+
+func newFakeKnativeClient(objects ...k8sruntime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(), map[schema.GroupVersionResource]string{
+		knativeServiceResource:       "ServiceList",
+		knativeTriggerResource:       "TriggerList",
+		knativePodAutoscalerResource: "PodAutoscalerList",
+	}, objects...)
+}
+
+func newTestKnativeService(name string, labels map[string]string) *unstructured.Unstructured {
+	service := &unstructured.Unstructured{}
+	service.SetAPIVersion("serving.knative.dev/v1")
+	service.SetKind("Service")
+	service.SetNamespace(namespace)
+	service.SetName(name)
+	service.SetLabels(labels)
+
+	return service
+}
+
+// readyOnCreate Marks the created services as ready, except for the named ones
+func readyOnCreate(client *fake.FakeDynamicClient, neverReady string) {
+	client.PrependReactor("create", "services", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		service := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if service.GetName() == neverReady {
+			return false, nil, nil
+		}
+
+		_ = unstructured.SetNestedField(service.Object, "http://"+service.GetName()+".default.example.com", "status", "url")
+		_ = unstructured.SetNestedSlice(service.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}, "status", "conditions")
+
+		return false, nil, nil
+	})
+}
+
+func newTestKnativeDeployer(client *fake.FakeDynamicClient) *knativeDeployer {
+	deployer := newKnativeDeployer(&config.LoaderConfiguration{ExperimentID: "test-experiment"})
+	deployer.client = client
+	deployer.readyTimeout = 200 * time.Millisecond
+	deployer.pollInterval = 10 * time.Millisecond
+	deployer.backoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+
+	return deployer
+}
+
+func TestKnativeDeployer(t *testing.T) {
+	unrelated := newTestKnativeService("unrelated", nil)
+	previous := newTestKnativeService("previous", map[string]string{knativeExperimentLabel: "previous-experiment"})
+
+	client := newFakeKnativeClient(unrelated, previous)
+	readyOnCreate(client, "never-ready")
+
+	// the API server is unavailable at the first attempt of creating a service
+	var failures atomic.Int32
+	client.PrependReactor("create", "services", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		if failures.Add(1) == 1 {
+			return true, nil, apierrors.NewServiceUnavailable("starting")
+		}
+		return false, nil, nil
+	})
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:          common.PlatformKnative,
+			EndpointPort:      80,
+			AutoscalingMetric: "concurrency",
+			KnativeAsyncMode:  true,
+		},
+		Functions: []*common.Function{
+			{Name: "scaled-function", YAMLPath: knativeTestTemplate, InitialScale: 2, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128},
+			{Name: "never-ready", YAMLPath: knativeTestTemplate, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128},
+			{Name: "no-template", YAMLPath: "missing.yaml"},
+		},
+	}
+
+	deployer := newTestKnativeDeployer(client)
+	knativeConfig := newKnativeDeployerConfiguration(cfg)
+
+	tests := []struct {
+		testName string
+		function *common.Function
+		ready    bool
+		endpoint string
+		attempts int
+	}{
+		{testName: "ready", function: cfg.Functions[0], ready: true, endpoint: "scaled-function.default.example.com:80", attempts: 2},
+		{testName: "never_ready", function: cfg.Functions[1], ready: false, endpoint: "never-ready.default." + bareMetalLbGateway + ":80", attempts: 1},
+		{testName: "no_template", function: cfg.Functions[2], ready: false, endpoint: "", attempts: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			result := deployer.deployFunction(test.function, knativeConfig, nil)
+
+			if result.Deployed != test.ready || (result.Err == nil) != test.ready || result.Attempts != test.attempts {
+				t.Errorf("Unexpected deployment result %+v", result)
+			}
+			if test.function.Endpoint != test.endpoint {
+				t.Errorf("Unexpected endpoint - expected: %s; got: %s", test.endpoint, test.function.Endpoint)
+			}
+		})
+	}
+
+	service, err := client.Resource(knativeServiceResource).Namespace(namespace).Get(context.Background(), "scaled-function", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	containers, _, _ := unstructured.NestedSlice(service.Object, "spec", "template", "spec", "containers")
+	if service.GetLabels()[knativeExperimentLabel] != "test-experiment" ||
+		annotations["autoscaling.knative.dev/initial-scale"] != "2" ||
+		annotations["autoscaling.knative.dev/metric"] != "concurrency" ||
+		annotations["autoscaling.knative.dev/panic-window-percentage"] != "10.0" ||
+		len(containers) != 1 || !strings.Contains(containers[0].(map[string]interface{})["image"].(string), "trace_function") {
+
+		t.Errorf("Unexpected rendered service %v", service.Object)
+	}
+
+	if _, err = client.Resource(knativeTriggerResource).Namespace(namespace).Get(context.Background(), "scaled-function", metav1.GetOptions{}); err != nil {
+		t.Errorf("Trigger of the function was not created - %v", err)
+	}
+
+	if err = deployer.UpdateMinScale(cfg.Functions[0], 3); err != nil {
+		t.Fatal(err)
+	}
+	service, _ = client.Resource(knativeServiceResource).Namespace(namespace).Get(context.Background(), "scaled-function", metav1.GetOptions{})
+	annotations, _, _ = unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	if annotations[knativeMinScaleAnnotation] != "3" || annotations["autoscaling.knative.dev/initial-scale"] != "2" {
+		t.Errorf("Unexpected annotations after updating the minimum scale %v", annotations)
+	}
+
+	if err = deployer.RestoreMinScale(cfg.Functions[0]); err != nil {
+		t.Fatal(err)
+	}
+	service, _ = client.Resource(knativeServiceResource).Namespace(namespace).Get(context.Background(), "scaled-function", metav1.GetOptions{})
+	annotations, _, _ = unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	// as set by the template
+	if annotations[knativeMinScaleAnnotation] != "0" || annotations["autoscaling.knative.dev/initial-scale"] != "2" {
+		t.Errorf("Unexpected annotations after restoring the minimum scale %v", annotations)
+	}
+
+	deployer.Clean()
+
+	services, err := client.Resource(knativeServiceResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, item := range services.Items {
+		remaining = append(remaining, item.GetName())
+	}
+	if len(remaining) != 2 || !strings.Contains(strings.Join(remaining, ","), "unrelated") || !strings.Contains(strings.Join(remaining, ","), "previous") {
+		t.Errorf("Clean deleted services of other experiments - remaining: %v", remaining)
+	}
+
+	triggers, _ := client.Resource(knativeTriggerResource).List(context.Background(), metav1.ListOptions{})
+	if len(triggers.Items) != 0 {
+		t.Errorf("Clean left %d triggers", len(triggers.Items))
+	}
+}
+
+func TestKnativeDeployerUpdatesExistingService(t *testing.T) {
+	existing := newTestKnativeService("test-function", map[string]string{knativeExperimentLabel: "test-experiment"})
+	existing.SetResourceVersion("7")
+
+	client := newFakeKnativeClient(existing)
+	client.PrependReactor("update", "services", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		service := action.(k8stesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+		_ = unstructured.SetNestedSlice(service.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}, "status", "conditions")
+
+		return false, nil, nil
+	})
+
+	deployer := newTestKnativeDeployer(client)
+	function := &common.Function{Name: "test-function", YAMLPath: knativeTestTemplate}
+
+	result := deployer.deployFunction(function, knativeDeploymentConfiguration{EndpointPort: 80}, nil)
+	if !result.Deployed || function.Endpoint != "test-function.default."+bareMetalLbGateway+":80" {
+		t.Errorf("Failed to update the existing service %+v - endpoint %s", result, function.Endpoint)
+	}
+}
+
+func TestKnativeDeployerReuse(t *testing.T) {
+	newFunction := func() *common.Function {
+		return &common.Function{
+			Name:              "test-function",
+			YAMLPath:          knativeTestTemplate,
+			InitialScale:      1,
+			CPURequestsMilli:  1000,
+			CPULimitsMilli:    1000,
+			MemoryRequestsMiB: 1024,
+		}
+	}
+
+	tests := []struct {
+		testName string
+		// existing Modifies the requested service into the existing one, which does not exist if nil
+		existing    func(service *unstructured.Unstructured)
+		actualScale int64
+		reused      bool
+		action      string
+	}{
+		{testName: "as_is", existing: func(*unstructured.Unstructured) {}, actualScale: 1, reused: true},
+		{testName: "defaulted_by_the_api_server", existing: func(service *unstructured.Unstructured) {
+			containers, _, _ := unstructured.NestedSlice(service.Object, "spec", "template", "spec", "containers")
+			container := containers[0].(map[string]interface{})
+			container["name"] = "user-container"
+			container["resources"] = map[string]interface{}{
+				"limits":   map[string]interface{}{"cpu": "1"},
+				"requests": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+			}
+			_ = unstructured.SetNestedSlice(service.Object, containers, "spec", "template", "spec", "containers")
+			_ = unstructured.SetNestedField(service.Object, int64(1), "spec", "template", "spec", "containerConcurrency")
+		}, actualScale: 1, reused: true},
+		{testName: "scaled", existing: func(*unstructured.Unstructured) {}, actualScale: 4, reused: true, action: "patch"},
+		{testName: "different_resources", existing: func(service *unstructured.Unstructured) {
+			containers, _, _ := unstructured.NestedSlice(service.Object, "spec", "template", "spec", "containers")
+			_ = unstructured.SetNestedField(containers[0].(map[string]interface{}), "512Mi", "resources", "requests", "memory")
+			_ = unstructured.SetNestedSlice(service.Object, containers, "spec", "template", "spec", "containers")
+		}, actualScale: 1, reused: true, action: "update"},
+		{testName: "minimum_scale_left_by_the_warmup", existing: func(service *unstructured.Unstructured) {
+			_ = unstructured.SetNestedField(service.Object, "3", "spec", "template", "metadata", "annotations", knativeMinScaleAnnotation)
+		}, actualScale: 3, reused: true, action: "update"},
+		{testName: "of_another_experiment", existing: func(service *unstructured.Unstructured) {
+			service.SetLabels(map[string]string{knativeExperimentLabel: "previous-experiment"})
+		}, actualScale: 1, reused: true, action: "update"},
+		{testName: "not_deployed", reused: false, action: "create"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			deployer := newTestKnativeDeployer(nil)
+			deployer.reuse = true
+			knativeConfig := knativeDeploymentConfiguration{EndpointPort: 80, AutoscalingMetric: "concurrency"}
+
+			var objects []k8sruntime.Object
+			if test.existing != nil {
+				existing, err := deployer.renderService(newFunction(), knativeConfig)
+				if err != nil {
+					t.Fatal(err)
+				}
+				test.existing(existing)
+				existing.SetResourceVersion("3")
+				_ = unstructured.SetNestedField(existing.Object, "test-function-00001", "status", "latestReadyRevisionName")
+				_ = unstructured.SetNestedSlice(existing.Object, []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				}, "status", "conditions")
+
+				autoscaler := &unstructured.Unstructured{}
+				autoscaler.SetAPIVersion("autoscaling.internal.knative.dev/v1alpha1")
+				autoscaler.SetKind("PodAutoscaler")
+				autoscaler.SetNamespace(namespace)
+				autoscaler.SetName("test-function-00001")
+				_ = unstructured.SetNestedField(autoscaler.Object, test.actualScale, "status", "actualScale")
+
+				objects = append(objects, existing, autoscaler)
+			}
+
+			client := newFakeKnativeClient(objects...)
+			readyOnCreate(client, "")
+			// the status is kept by the API server, unlike by the fake client
+			client.PrependReactor("update", "services", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+				service := action.(k8stesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+				_ = unstructured.SetNestedSlice(service.Object, []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				}, "status", "conditions")
+
+				return false, nil, nil
+			})
+			deployer.client = client
+
+			function := newFunction()
+			result := deployer.deployFunction(function, knativeConfig, deployer.discoverServices())
+			if !result.Deployed || result.Reused != test.reused || function.Endpoint == "" {
+				t.Fatalf("Unexpected deployment result %+v", result)
+			}
+
+			var actions []string
+			for _, action := range client.Actions() {
+				if action.GetResource() == knativeServiceResource && action.GetVerb() != "get" && action.GetVerb() != "list" {
+					actions = append(actions, action.GetVerb())
+				}
+			}
+			if strings.Join(actions, ",") != test.action {
+				t.Errorf("Unexpected modifications of the service - expected: %s; got: %v", test.action, actions)
+			}
+
+			service, err := client.Resource(knativeServiceResource).Namespace(namespace).Get(context.Background(), "test-function", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+			if (annotations[knativeResetAnnotation] != "") != (test.action == "patch") ||
+				annotations[knativeMinScaleAnnotation] != "0" || service.GetLabels()[knativeExperimentLabel] != "test-experiment" {
+
+				t.Errorf("Unexpected service after reusing it %v", service.Object)
+			}
+
+			deployer.Clean()
+			if _, err = client.Resource(knativeServiceResource).Namespace(namespace).Get(context.Background(), "test-function", metav1.GetOptions{}); err != nil {
+				t.Errorf("Clean deleted the reused service - %v", err)
+			}
+		})
+	}
+}
+
+func TestKnativeReadyCondition(t *testing.T) {
+	tests := []struct {
+		testName   string
+		generation int64
+		observed   int64
+		conditions []interface{}
+		ready      bool
+	}{
+		{testName: "ready", conditions: []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}, ready: true},
+		{testName: "not_ready", conditions: []interface{}{map[string]interface{}{"type": "Ready", "status": "False", "message": "RevisionFailed"}}, ready: false},
+		{testName: "no_conditions", ready: false},
+		{testName: "stale_generation", generation: 2, observed: 1, conditions: []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}, ready: false},
+		{testName: "observed_generation", generation: 2, observed: 2, conditions: []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}, ready: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			service := newTestKnativeService("test-function", nil)
+			service.SetGeneration(test.generation)
+			_ = unstructured.SetNestedField(service.Object, test.observed, "status", "observedGeneration")
+			if test.conditions != nil {
+				_ = unstructured.SetNestedSlice(service.Object, test.conditions, "status", "conditions")
+			}
+
+			if ready, reason := knativeReadyCondition(service); ready != test.ready || (!ready && reason == "") {
+				t.Errorf("Unexpected readiness - expected: %t; got: %t (%s)", test.ready, ready, reason)
+			}
+		})
+	}
+}
+
+func TestKnativeDeployerRender(t *testing.T) {
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:          common.PlatformKnative,
+			AutoscalingMetric: "concurrency",
+			IsPartiallyPanic:  true,
+			KnativeAsyncMode:  true,
+		},
+		Functions: []*common.Function{
+			{Name: "test-function", YAMLPath: knativeTestTemplate, InitialScale: 3, CPURequestsMilli: 250, CPULimitsMilli: 1000, MemoryRequestsMiB: 512, ColdStartBusyLoopMs: 150},
+		},
+	}
+
+	// rendering must not contact the API server
+	deployer := newKnativeDeployer(&config.LoaderConfiguration{ExperimentID: "test-experiment"})
+	dir := t.TempDir()
+	if err := deployer.Render(cfg, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "test-function.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	documents := strings.Split(string(data), "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("Expected the service and the trigger, got %d documents", len(documents))
+	}
+
+	service := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal([]byte(documents[0]), &service.Object); err != nil {
+		t.Fatal(err)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	containers, _, _ := unstructured.NestedSlice(service.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	requests, _, _ := unstructured.NestedStringMap(container, "resources", "requests")
+	env, _, _ := unstructured.NestedSlice(container, "env")
+
+	if service.GetName() != "test-function" || service.GetLabels()[knativeExperimentLabel] != "test-experiment" ||
+		annotations["autoscaling.knative.dev/initial-scale"] != "3" ||
+		annotations["autoscaling.knative.dev/panic-window-percentage"] != "100.0" ||
+		annotations["autoscaling.knative.dev/target"] != "100" ||
+		requests["cpu"] != "250m" || requests["memory"] != "512Mi" ||
+		!strings.Contains(documents[0], "value: \"150\"") || len(env) != 4 {
+
+		t.Errorf("Unexpected rendered service\n%s", documents[0])
+	}
+
+	if !strings.Contains(documents[1], "kind: Trigger") || !strings.Contains(documents[1], "function: test-function") {
+		t.Errorf("Unexpected rendered trigger\n%s", documents[1])
+	}
+
+	cfg.Functions[0].YAMLPath = "missing.yaml"
+	if err = deployer.Render(cfg, dir); err == nil {
+		t.Error("Rendering a function without a template should fail.")
+	}
+}
+
+func TestKnativeUpdateMinScaleInTemplateNamespace(t *testing.T) {
+	data, err := os.ReadFile(knativeTestTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal(data, &template.Object); err != nil {
+		t.Fatal(err)
+	}
+	template.SetNamespace("experiments")
+	if data, err = yaml.Marshal(template.Object); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "template.yaml")
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newFakeKnativeClient()
+	readyOnCreate(client, "")
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformKnative, EndpointPort: 80},
+		Functions:           []*common.Function{{Name: "test-function", YAMLPath: path, CPULimitsMilli: 1000}},
+	}
+
+	deployer := newTestKnativeDeployer(client)
+	if result := deployer.deployFunction(cfg.Functions[0], newKnativeDeployerConfiguration(cfg), nil); !result.Deployed {
+		t.Fatalf("Unexpected deployment result %+v", result)
+	}
+
+	if err = deployer.UpdateMinScale(cfg.Functions[0], 3); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := client.Resource(knativeServiceResource).Namespace("experiments").Get(context.Background(), "test-function", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	if annotations[knativeMinScaleAnnotation] != "3" {
+		t.Errorf("Unexpected annotations after updating the minimum scale %v", annotations)
+	}
+}
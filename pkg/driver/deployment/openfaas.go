@@ -0,0 +1,217 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	// openFaaSFunctionPort Port the of-watchdog expects the function to listen on
+	openFaaSFunctionPort = 8080
+	openFaaSReadyTimeout = 5 * time.Minute
+)
+
+// openFaaSFunctionDeployment Deployment request of the OpenFaaS gateway (/system/functions)
+type openFaaSFunctionDeployment struct {
+	Service  string             `json:"service"`
+	Image    string             `json:"image"`
+	EnvVars  map[string]string  `json:"envVars"`
+	Labels   map[string]string  `json:"labels"`
+	Requests *openFaaSResources `json:"requests,omitempty"`
+	Limits   *openFaaSResources `json:"limits,omitempty"`
+}
+
+type openFaaSResources struct {
+	Memory string `json:"memory,omitempty"`
+	CPU    string `json:"cpu,omitempty"`
+}
+
+// openFaaSFunctionStatus Function status as listed by the OpenFaaS gateway
+type openFaaSFunctionStatus struct {
+	Name              string `json:"name"`
+	Replicas          int    `json:"replicas"`
+	AvailableReplicas int    `json:"availableReplicas"`
+}
+
+// openFaaSStatusError Response of the OpenFaaS gateway with a non-2xx status code
+type openFaaSStatusError struct {
+	statusCode int
+	body       []byte
+}
+
+func (e *openFaaSStatusError) Error() string {
+	return fmt.Sprintf("got status code %d - %s", e.statusCode, e.body)
+}
+
+// openFaaSDeployer deploys the trace function through the REST API of the OpenFaaS gateway, and reads the scale of
+// the functions from the replica counts the gateway reports.
+type openFaaSDeployer struct {
+	gateway  string
+	user     string
+	password string
+	client   *http.Client
+
+	functions []*common.Function
+}
+
+func newOpenFaaSDeployer(cfg *config.LoaderConfiguration) *openFaaSDeployer {
+	return &openFaaSDeployer{
+		gateway:  cfg.OpenFaaSGateway,
+		user:     cfg.OpenFaaSUser,
+		password: cfg.OpenFaaSPassword,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func newOpenFaaSFunctionDeployment(function *common.Function, image string) *openFaaSFunctionDeployment {
+	labels := map[string]string{
+		// OpenFaaS does not allow a minimum scale of zero, but scales idle functions to zero if labeled so
+		"com.openfaas.scale.min": strconv.Itoa(common.MaxOf(function.InitialScale, 1)),
+		"com.openfaas.scale.max": strconv.Itoa(maxFunctionScale),
+	}
+	if function.InitialScale == 0 {
+		labels["com.openfaas.scale.zero"] = "true"
+	}
+
+	return &openFaaSFunctionDeployment{
+		Service: function.Name,
+		Image:   image,
+		EnvVars: traceFunctionEnvironment(function, openFaaSFunctionPort),
+		Labels:  labels,
+		Requests: &openFaaSResources{
+			Memory: strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+			CPU:    strconv.Itoa(function.CPURequestsMilli) + "m",
+		},
+		Limits: &openFaaSResources{
+			CPU: strconv.Itoa(function.CPULimitsMilli) + "m",
+		},
+	}
+}
+
+func (d *openFaaSDeployer) request(method string, path string, body interface{}) ([]byte, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("http://%s%s", d.gateway, path), payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.user != "" {
+		req.SetBasicAuth(d.user, d.password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return data, &openFaaSStatusError{statusCode: resp.StatusCode, body: data}
+	}
+
+	return data, nil
+}
+
+func (d *openFaaSDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
+	d.functions = cfg.Functions
+	image := traceFunctionImage(cfg.LoaderConfiguration)
+
+	start := time.Now()
+	errs := make(map[string]error)
+	var deployed []*common.Function
+
+	for _, function := range d.functions {
+		deployment := newOpenFaaSFunctionDeployment(function, image)
+
+		_, err := d.request(http.MethodPost, "/system/functions", deployment)
+
+		var statusErr *openFaaSStatusError
+		if errors.As(err, &statusErr) && statusErr.statusCode == http.StatusConflict {
+			// the function already exists, hence it is updated instead
+			_, err = d.request(http.MethodPut, "/system/functions", deployment)
+		}
+		if err != nil {
+			errs[function.Name] = fmt.Errorf("failed to deploy on OpenFaaS - %w", err)
+			continue
+		}
+
+		function.Endpoint = fmt.Sprintf("%s/function/%s", d.gateway, function.Name)
+		deployed = append(deployed, function)
+		log.Debugf("Deployed function on %s\n", function.Endpoint)
+	}
+
+	notReady := waitForReadiness(d, deployed, openFaaSReadyTimeout)
+
+	return readinessResult(d.functions, start, errs, notReady, openFaaSReadyTimeout)
+}
+
+// Render Writes the deployment request of each function to the gateway
+func (d *openFaaSDeployer) Render(cfg *config.Configuration, dir string) error {
+	image := traceFunctionImage(cfg.LoaderConfiguration)
+
+	for _, function := range cfg.Functions {
+		if err := writeRenderedJSON(dir, function.Name+".json", newOpenFaaSFunctionDeployment(function, image)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *openFaaSDeployer) Clean() {
+	for _, function := range d.functions {
+		if _, err := d.request(http.MethodDelete, "/system/functions", map[string]string{"functionName": function.Name}); err != nil {
+			log.Debugf("Unable to delete OpenFaaS function %s - %v", function.Name, err)
+		}
+	}
+}
+
+// DeploymentScales Maps the replicas of the functions to the desired pods, and the available replicas to the running
+// pods
+func (d *openFaaSDeployer) DeploymentScales() ([]metric.DeploymentScale, error) {
+	data, err := d.request(http.MethodGet, "/system/functions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []openFaaSFunctionStatus
+	if err = json.Unmarshal(data, &statuses); err != nil {
+		return nil, err
+	}
+
+	result := make([]metric.DeploymentScale, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, metric.DeploymentScale{
+			Function:    status.Name,
+			DesiredPods: status.Replicas,
+			RunningPods: status.AvailableReplicas,
+			UnreadyPods: common.MaxOf(status.Replicas-status.AvailableReplicas, 0),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package deployment
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestOpenFaaSDeployer(t *testing.T) {
+	deployed := make(map[string]*openFaaSFunctionDeployment)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" || r.URL.Path != "/system/functions" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			var deployment openFaaSFunctionDeployment
+			if err := json.NewDecoder(r.Body).Decode(&deployment); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			deployed[deployment.Service] = &deployment
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodGet:
+			var statuses []openFaaSFunctionStatus
+			for name := range deployed {
+				statuses = append(statuses, openFaaSFunctionStatus{Name: name, Replicas: 3, AvailableReplicas: 2})
+			}
+			_ = json.NewEncoder(w).Encode(statuses)
+		case http.MethodDelete:
+			var request map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			delete(deployed, request["functionName"])
+		}
+	}))
+	defer gateway.Close()
+
+	address := strings.TrimPrefix(gateway.URL, "http://")
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:         common.PlatformOpenFaaS,
+			OpenFaaSGateway:  address,
+			OpenFaaSUser:     "admin",
+			OpenFaaSPassword: "secret",
+		},
+		Functions: []*common.Function{
+			{Name: "scaled-function", InitialScale: 2, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128},
+			{Name: "idle-function", InitialScale: 0, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128},
+		},
+	}
+
+	deployer := CreateDeployer(cfg)
+	deployer.Deploy(cfg)
+
+	tests := []struct {
+		testName string
+		function *common.Function
+		minScale string
+		toZero   string
+	}{
+		{testName: "scaled", function: cfg.Functions[0], minScale: "2", toZero: ""},
+		{testName: "scale_to_zero", function: cfg.Functions[1], minScale: "1", toZero: "true"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			deployment, ok := deployed[test.function.Name]
+			if !ok {
+				t.Fatalf("Function %s was not deployed.", test.function.Name)
+			}
+
+			if deployment.Labels["com.openfaas.scale.min"] != test.minScale || deployment.Labels["com.openfaas.scale.zero"] != test.toZero ||
+				deployment.Requests.CPU != "100m" || deployment.Requests.Memory != "128Mi" || deployment.Limits.CPU != "1000m" ||
+				deployment.EnvVars["FUNC_PROTOCOL_ENV"] != "http" || deployment.EnvVars["FUNC_PORT_ENV"] != "8080" {
+
+				t.Errorf("Unexpected deployment %+v", deployment)
+			}
+
+			if test.function.Endpoint != address+"/function/"+test.function.Name {
+				t.Errorf("Unexpected endpoint %s", test.function.Endpoint)
+			}
+		})
+	}
+
+	scales, err := deployer.(ScaleReporter).DeploymentScales()
+	if err != nil || len(scales) != 2 || scales[0].DesiredPods != 3 || scales[0].RunningPods != 2 || scales[0].UnreadyPods != 1 {
+		t.Errorf("Unexpected deployment scales %+v - %v", scales, err)
+	}
+
+	deployer.Clean()
+	if len(deployed) != 0 {
+		t.Errorf("Functions %v were not deleted.", deployed)
+	}
+}
+
+func TestOpenFaaSDeployExisting(t *testing.T) {
+	tests := []struct {
+		testName   string
+		postStatus int
+		updated    bool
+		failed     bool
+	}{
+		{testName: "created", postStatus: http.StatusAccepted, updated: false, failed: false},
+		{testName: "conflict_updates", postStatus: http.StatusConflict, updated: true, failed: false},
+		{testName: "other_error_fails", postStatus: http.StatusBadRequest, updated: false, failed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			updated := false
+
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodPost:
+					w.WriteHeader(test.postStatus)
+				case http.MethodPut:
+					updated = true
+					w.WriteHeader(http.StatusAccepted)
+				case http.MethodGet:
+					_ = json.NewEncoder(w).Encode([]openFaaSFunctionStatus{{Name: "function", Replicas: 1, AvailableReplicas: 1}})
+				}
+			}))
+			defer gateway.Close()
+
+			cfg := &config.Configuration{
+				LoaderConfiguration: &config.LoaderConfiguration{
+					Platform:        common.PlatformOpenFaaS,
+					OpenFaaSGateway: strings.TrimPrefix(gateway.URL, "http://"),
+				},
+				Functions: []*common.Function{{Name: "function"}},
+			}
+
+			result := CreateDeployer(cfg).Deploy(cfg)
+
+			if updated != test.updated {
+				t.Errorf("Unexpected update of the existing function - %v", updated)
+			}
+			failed := result.Failed()
+			if (len(failed) > 0) != test.failed {
+				t.Fatalf("Unexpected deployment outcome %+v", result.Functions)
+			}
+			if test.failed && !strings.Contains(failed[0].Err.Error(), "status code 400") {
+				t.Errorf("The deployment error does not carry the original status code - %v", failed[0].Err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/vhive-serverless/loader/pkg/config"
 	"os/exec"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -19,8 +20,9 @@ func newOpenWhiskDeployer() *openWhiskDeployer {
 	return &openWhiskDeployer{}
 }
 
-func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
+func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) DeploymentResult {
 	owd.functions = cfg.Functions
+	start := time.Now()
 
 	cmd := exec.Command("wsk", "-i", "property", "get", "--apihost")
 
@@ -29,23 +31,42 @@ func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
 
 	err := cmd.Run()
 	if err != nil {
-		log.Fatalf("Unable to read OpenWhisk API host data - %s", err)
+		return newDeploymentResult(owd.functions, start, fmt.Errorf("unable to read OpenWhisk API host data - %w", err))
 	}
 	result := strings.Split(out.String(), "\t")
 	endpoint := strings.TrimSpace(result[len(result)-1])
 
-	const actionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
-
+	deployment := DeploymentResult{}
 	for i := 0; i < len(owd.functions); i++ {
-		cmd = exec.Command("wsk", "-i", "action", "create", owd.functions[i].Name, actionLocation, "--kind", "go:1.17", "--web", "true")
+		functionStart := time.Now()
+		cmd = exec.Command("wsk", openWhiskActionArgs(owd.functions[i])...)
 
-		err = cmd.Run()
-		if err != nil {
-			log.Fatalf("Unable to create OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
+		if err = cmd.Run(); err != nil {
+			deployment.Add(owd.functions[i], functionStart, fmt.Errorf("unable to create OpenWhisk action - %w", err))
+			continue
 		}
 
 		owd.functions[i].Endpoint = fmt.Sprintf("https://%s/api/v1/web/guest/default/%s", endpoint, owd.functions[i].Name)
+		deployment.Add(owd.functions[i], functionStart, nil)
+	}
+
+	return deployment
+}
+
+// Render Writes the commands creating the actions of the functions as a shell script
+func (owd *openWhiskDeployer) Render(cfg *config.Configuration, dir string) error {
+	script := "#!/bin/sh\n"
+	for _, function := range cfg.Functions {
+		script += "wsk " + strings.Join(openWhiskActionArgs(function), " ") + "\n"
 	}
+
+	return writeRendered(dir, "actions.sh", []byte(script))
+}
+
+func openWhiskActionArgs(function *common.Function) []string {
+	const actionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
+
+	return []string{"-i", "action", "create", function.Name, actionLocation, "--kind", "go:1.17", "--web", "true"}
 }
 
 func (owd *openWhiskDeployer) Clean() {
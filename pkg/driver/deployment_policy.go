@@ -0,0 +1,189 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+// decisions of the deployment failure policy
+const (
+	deploymentDeployed = "deployed"
+	deploymentRetried  = "retried"
+	deploymentDropped  = "dropped"
+	deploymentAborted  = "aborted"
+)
+
+// deploymentMetadata Outcome of deploying the functions and the decision of the deployment failure policy, written
+// next to the output of the experiment
+type deploymentMetadata struct {
+	Policy    string                     `json:"policy"`
+	Retries   int                        `json:"retries"`
+	Rounds    int                        `json:"rounds"`
+	Decision  string                     `json:"decision"`
+	Dropped   []string                   `json:"dropped,omitempty"`
+	Functions []functionDeploymentRecord `json:"functions"`
+}
+
+type functionDeploymentRecord struct {
+	Function   string `json:"function"`
+	Deployed   bool   `json:"deployed"`
+	Reused     bool   `json:"reused,omitempty"`
+	Attempts   int    `json:"attempts"`
+	DurationMs int64  `json:"durationMs"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// deploy Deploys the functions and applies the deployment failure policy to the ones that failed, returning an
+// error if the experiment is to be aborted
+func (d *Driver) deploy(deployer deployment.FunctionDeployer) error {
+	cfg := d.Configuration.LoaderConfiguration
+
+	policy := cfg.DeploymentPolicy
+	if policy == "" {
+		policy = common.DeploymentPolicyAbort
+	}
+	metadata := &deploymentMetadata{Policy: policy, Retries: cfg.DeploymentRetries, Decision: deploymentDeployed}
+
+	var order []string
+	outcomes := make(map[string]deployment.FunctionDeploymentResult)
+	record := func(result deployment.DeploymentResult) []deployment.FunctionDeploymentResult {
+		metadata.Rounds++
+		for _, outcome := range result.Functions {
+			if previous, ok := outcomes[outcome.Function]; ok {
+				outcome.Attempts += previous.Attempts
+				outcome.Duration += previous.Duration
+			} else {
+				order = append(order, outcome.Function)
+			}
+			outcomes[outcome.Function] = outcome
+		}
+
+		return result.Failed()
+	}
+
+	failed := record(deployer.Deploy(d.Configuration))
+
+	if policy == common.DeploymentPolicyRetry {
+		for round := 0; round < cfg.DeploymentRetries && len(failed) > 0; round++ {
+			log.Infof("Redeploying %d functions that failed to deploy (retry %d of %d).", len(failed), round+1, cfg.DeploymentRetries)
+
+			retryConfiguration := *d.Configuration
+			retryConfiguration.Functions = functionsNamed(d.Configuration.Functions, failed)
+			retried := retryConfiguration.Functions
+			if len(retried) == 0 {
+				break
+			}
+
+			failed = record(deployer.Deploy(&retryConfiguration))
+			// the deployer may replace the functions, as Dirigent does with the compositions of a workflow
+			d.Configuration.Functions = replaceFunctions(d.Configuration.Functions, retried, retryConfiguration.Functions)
+			metadata.Decision = deploymentRetried
+		}
+	}
+
+	var err error
+	if len(failed) > 0 {
+		for _, outcome := range failed {
+			log.Warnf("Failed to deploy function %s after %v (%d attempts) - %v", outcome.Function, outcome.Duration, outcome.Attempts, outcome.Err)
+		}
+
+		dropped := functionsNamed(d.Configuration.Functions, failed)
+		if policy == common.DeploymentPolicyDrop && len(dropped) < len(d.Configuration.Functions) {
+			metadata.Decision = deploymentDropped
+			for _, function := range dropped {
+				metadata.Dropped = append(metadata.Dropped, function.Name)
+			}
+			d.Configuration.Functions = replaceFunctions(d.Configuration.Functions, dropped, nil)
+
+			log.Warnf("Dropped %d functions that failed to deploy from the experiment.", len(metadata.Dropped))
+		} else {
+			metadata.Decision = deploymentAborted
+			err = fmt.Errorf("%d functions failed to deploy under the %s policy", len(failed), policy)
+		}
+	}
+
+	endpoints := make(map[string]string)
+	for _, function := range d.Configuration.Functions {
+		endpoints[function.Name] = function.Endpoint
+	}
+	deployed := 0
+	for _, name := range order {
+		outcome := outcomes[name]
+		if outcome.Deployed {
+			deployed++
+		}
+
+		functionRecord := functionDeploymentRecord{
+			Function:   outcome.Function,
+			Deployed:   outcome.Deployed,
+			Reused:     outcome.Reused,
+			Attempts:   outcome.Attempts,
+			DurationMs: outcome.Duration.Milliseconds(),
+			Endpoint:   endpoints[outcome.Function],
+		}
+		if outcome.Err != nil {
+			functionRecord.Error = outcome.Err.Error()
+		}
+		metadata.Functions = append(metadata.Functions, functionRecord)
+	}
+	log.Infof("%d of %d functions deployed (%s).", deployed, len(order), metadata.Decision)
+
+	if writeErr := writeDeploymentMetadata(d.outputMetadataFilename("deployment"), metadata); writeErr != nil {
+		log.Errorf("Failed to write the deployment metadata - %v", writeErr)
+	}
+
+	return err
+}
+
+func writeDeploymentMetadata(path string, metadata *deploymentMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// functionsNamed Returns the functions of which the deployment outcomes are given
+func functionsNamed(functions []*common.Function, outcomes []deployment.FunctionDeploymentResult) []*common.Function {
+	names := make(map[string]bool)
+	for _, outcome := range outcomes {
+		names[outcome.Function] = true
+	}
+
+	var result []*common.Function
+	for _, function := range functions {
+		if names[function.Name] {
+			result = append(result, function)
+		}
+	}
+
+	return result
+}
+
+// replaceFunctions Replaces the old functions with the new ones, placed where the first of the old ones was
+func replaceFunctions(functions []*common.Function, old []*common.Function, replacements []*common.Function) []*common.Function {
+	replaced := make(map[*common.Function]bool)
+	for _, function := range old {
+		replaced[function] = true
+	}
+
+	result := make([]*common.Function, 0, len(functions)-len(old)+len(replacements))
+	inserted := false
+	for _, function := range functions {
+		if !replaced[function] {
+			result = append(result, function)
+		} else if !inserted {
+			result = append(result, replacements...)
+			inserted = true
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+// scriptedDeployer Fails to deploy the functions the given number of times before deploying them
+type scriptedDeployer struct {
+	failures map[string]int
+	deployed [][]string
+}
+
+func (s *scriptedDeployer) Deploy(cfg *config.Configuration) deployment.DeploymentResult {
+	var names []string
+	result := deployment.DeploymentResult{}
+
+	for _, function := range cfg.Functions {
+		names = append(names, function.Name)
+
+		var err error
+		if s.failures[function.Name] != 0 {
+			s.failures[function.Name]--
+			err = errors.New("not ready")
+		} else {
+			function.Endpoint = function.Name + ".example.com"
+		}
+		result.Add(function, time.Now(), err)
+	}
+	s.deployed = append(s.deployed, names)
+
+	return result
+}
+
+func (s *scriptedDeployer) Render(*config.Configuration, string) error { return nil }
+
+func (s *scriptedDeployer) Clean() {}
+
+func TestDeploymentPolicy(t *testing.T) {
+	tests := []struct {
+		testName  string
+		policy    string
+		retries   int
+		failures  map[string]int
+		aborted   bool
+		functions []string
+		rounds    [][]string
+		decision  string
+		attempts  int
+	}{
+		{testName: "deployed", policy: "", functions: []string{"f0", "f1", "f2"}, rounds: [][]string{{"f0", "f1", "f2"}}, decision: deploymentDeployed, attempts: 1},
+		{testName: "abort_by_default", policy: "", failures: map[string]int{"f1": 1}, aborted: true, rounds: [][]string{{"f0", "f1", "f2"}}, decision: deploymentAborted, attempts: 1},
+		{testName: "retry_succeeds", policy: common.DeploymentPolicyRetry, retries: 2, failures: map[string]int{"f1": 2}, functions: []string{"f0", "f1", "f2"}, rounds: [][]string{{"f0", "f1", "f2"}, {"f1"}, {"f1"}}, decision: deploymentRetried, attempts: 3},
+		{testName: "retries_exhausted", policy: common.DeploymentPolicyRetry, retries: 1, failures: map[string]int{"f1": 2}, aborted: true, rounds: [][]string{{"f0", "f1", "f2"}, {"f1"}}, decision: deploymentAborted, attempts: 2},
+		{testName: "drop", policy: common.DeploymentPolicyDrop, failures: map[string]int{"f0": 1, "f2": 1}, functions: []string{"f1"}, rounds: [][]string{{"f0", "f1", "f2"}}, decision: deploymentDropped, attempts: 1},
+		{testName: "drop_all", policy: common.DeploymentPolicyDrop, failures: map[string]int{"f0": 1, "f1": 1, "f2": 1}, aborted: true, rounds: [][]string{{"f0", "f1", "f2"}}, decision: deploymentAborted, attempts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			prefix := filepath.Join(t.TempDir(), "test")
+			driver := &Driver{Configuration: &config.Configuration{
+				LoaderConfiguration: &config.LoaderConfiguration{
+					OutputPathPrefix:  prefix,
+					DeploymentPolicy:  test.policy,
+					DeploymentRetries: test.retries,
+				},
+				TraceDuration: 1,
+				Functions:     []*common.Function{{Name: "f0"}, {Name: "f1"}, {Name: "f2"}},
+			}}
+			deployer := &scriptedDeployer{failures: test.failures}
+
+			err := driver.deploy(deployer)
+			if (err != nil) != test.aborted {
+				t.Fatalf("Unexpected outcome of the deployment - %v", err)
+			}
+			if !reflect.DeepEqual(deployer.deployed, test.rounds) {
+				t.Errorf("Unexpected deployment rounds %v", deployer.deployed)
+			}
+
+			if !test.aborted {
+				var functions []string
+				for _, function := range driver.Configuration.Functions {
+					functions = append(functions, function.Name)
+					if function.Endpoint == "" {
+						t.Errorf("Function %s kept without an endpoint", function.Name)
+					}
+				}
+				if !reflect.DeepEqual(functions, test.functions) {
+					t.Errorf("Unexpected functions of the experiment %v", functions)
+				}
+			}
+
+			data, err := os.ReadFile(prefix + "_deployment_1.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var metadata deploymentMetadata
+			if err = json.Unmarshal(data, &metadata); err != nil {
+				t.Fatal(err)
+			}
+			if metadata.Decision != test.decision || metadata.Rounds != len(test.rounds) || len(metadata.Functions) != 3 {
+				t.Errorf("Unexpected deployment metadata %+v", metadata)
+			}
+			for _, function := range metadata.Functions {
+				if function.Function == "f1" && function.Attempts != test.attempts {
+					t.Errorf("Unexpected attempts of function %+v", function)
+				}
+				if function.Deployed == (function.Error != "") {
+					t.Errorf("Unexpected record of function %+v", function)
+				}
+			}
+		})
+	}
+}
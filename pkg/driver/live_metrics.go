@@ -0,0 +1,165 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const liveMetricsPath = "/metrics"
+
+// liveMetrics Loader-side metrics of the running experiment, exposed on the /metrics endpoint in the Prometheus text
+// format, so that the loader can be monitored while the experiment runs rather than only from its output files
+type liveMetrics struct {
+	registry *mc.Registry
+
+	issued        *mc.CounterVec
+	succeeded     *mc.CounterVec
+	failed        *mc.CounterVec
+	inFlight      *mc.GaugeVec
+	schedulingLag *mc.HistogramVec
+	responseTime  *mc.HistogramVec
+	minute        *mc.GaugeVec
+	targetRps     *mc.GaugeVec
+	actualRps     *mc.GaugeVec
+
+	// invocations issued per function since the start of the current minute of the experiment
+	issuedLock     sync.Mutex
+	issuedInMinute map[string]int
+
+	server   *http.Server
+	listener net.Listener
+}
+
+func newLiveMetrics() *liveMetrics {
+	registry := mc.NewRegistry()
+
+	return &liveMetrics{
+		registry: registry,
+
+		issued:    registry.NewCounter("loader_invocations_issued_total", "Invocations issued by the loader.", "function"),
+		succeeded: registry.NewCounter("loader_invocations_succeeded_total", "Invocations that succeeded.", "function"),
+		failed:    registry.NewCounter("loader_invocations_failed_total", "Invocations that failed, by error class.", "function", "error_class"),
+		inFlight:  registry.NewGauge("loader_invocations_in_flight", "Invocations issued and not yet returned.", "function"),
+		schedulingLag: registry.NewHistogram("loader_scheduling_lag_seconds", "Delay of issuing invocations after their scheduled time.",
+			mc.ExponentialBuckets(0.0001, 4, 10), "function"),
+		responseTime: registry.NewHistogram("loader_response_time_seconds", "Response time of synchronous invocations.",
+			mc.ExponentialBuckets(0.001, 2, 17), "function"),
+		minute:    registry.NewGauge("loader_experiment_minute", "Current minute of the experiment, including the warmup."),
+		targetRps: registry.NewGauge("loader_target_rps", "Invocations per second the trace requests in the current minute.", "function"),
+		actualRps: registry.NewGauge("loader_actual_rps", "Invocations per second issued in the previous minute.", "function"),
+
+		issuedInMinute: make(map[string]int),
+	}
+}
+
+// Start Serves the metrics on the address until stopped
+func (m *liveMetrics) Start(address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(liveMetricsPath, m.registry)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	m.listener = listener
+	m.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := m.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Metrics endpoint stopped unexpectedly - %v", err)
+		}
+	}()
+
+	log.Infof("Exposing the loader metrics on http://%s%s", listener.Addr(), liveMetricsPath)
+
+	return nil
+}
+
+func (m *liveMetrics) Stop() {
+	if m.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.server.Shutdown(ctx); err != nil {
+		log.Warnf("Failed to shut down the metrics endpoint - %v", err)
+	}
+}
+
+func (m *liveMetrics) invocationIssued(function string) {
+	m.issued.Inc(function)
+	m.inFlight.Add(1, function)
+
+	m.issuedLock.Lock()
+	m.issuedInMinute[function]++
+	m.issuedLock.Unlock()
+}
+
+func (m *liveMetrics) invocationReturned(function string, record *mc.ExecutionRecord, success bool) {
+	m.inFlight.Add(-1, function)
+
+	if success {
+		m.succeeded.Inc(function)
+	} else {
+		m.failed.Inc(function, failureCategory(record))
+	}
+}
+
+func (m *liveMetrics) observeResponseTime(function string, record *mc.ExecutionRecord) {
+	m.responseTime.Observe(float64(record.ResponseTime)/1e6, function)
+}
+
+func (m *liveMetrics) observeSchedulingLag(function string, lag time.Duration) {
+	m.schedulingLag.Observe(max(lag, 0).Seconds(), function)
+}
+
+// startMinute Sets the actual rate of the minute that ended from the invocations issued in it, and the target rate
+// of the minute that starts from the specification of the functions
+func (m *liveMetrics) startMinute(minute int, functions []*common.Function, granularity common.TraceGranularity) {
+	m.issuedLock.Lock()
+	issued := m.issuedInMinute
+	m.issuedInMinute = make(map[string]int)
+	m.issuedLock.Unlock()
+
+	if minute > 0 {
+		for _, function := range functions {
+			m.actualRps.Set(float64(issued[function.Name])/60, function.Name)
+		}
+	}
+
+	m.minute.Set(float64(minute))
+	for _, function := range functions {
+		m.targetRps.Set(float64(targetInvocations(function, minute, granularity))/60, function.Name)
+	}
+}
+
+// targetInvocations Returns the number of invocations of the function the trace requests in the minute
+func targetInvocations(function *common.Function, minute int, granularity common.TraceGranularity) int {
+	if function.Specification == nil {
+		return 0
+	}
+
+	counts := function.Specification.PerMinuteCount
+	from, to := minute, minute+1
+	if granularity == common.SecondGranularity {
+		// the counts are per second
+		from, to = minute*60, (minute+1)*60
+	}
+
+	var sum int
+	for i := from; i < to && i < len(counts); i++ {
+		sum += counts[i]
+	}
+
+	return sum
+}
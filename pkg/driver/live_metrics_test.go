@@ -0,0 +1,119 @@
+package driver
+
+import (
+	"container/list"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestLiveMetrics(t *testing.T) {
+	function := &common.Function{
+		Name: "f0",
+		Specification: &common.FunctionSpecification{
+			PerMinuteCount:       []int{120, 60},
+			RuntimeSpecification: []common.RuntimeSpecification{{}, {}, {}},
+		},
+	}
+	driver := NewDriver(&config.Configuration{
+		LoaderConfiguration: createFakeLoaderConfiguration(false),
+		TraceGranularity:    common.MinuteGranularity,
+		Functions:           []*common.Function{function},
+	})
+	driver.Invoker = &scriptedInvoker{failures: 1, class: metric.ErrorClassTimeout, delays: map[int]time.Duration{1: 20 * time.Millisecond}}
+
+	if err := driver.liveMetrics.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	defer driver.liveMetrics.Stop()
+
+	driver.liveMetrics.startMinute(0, driver.Configuration.Functions, driver.Configuration.TraceGranularity)
+
+	var functionsInvoked, successCount, failedCount int64
+	output := make(chan *metric.ExecutionRecord, 10)
+	for i := 0; i < 3; i++ {
+		rootFunction := list.New()
+		rootFunction.PushBack(&common.Node{Function: function})
+		announceDone := &sync.WaitGroup{}
+		announceDone.Add(1)
+		driver.invokeFunction(&InvocationMetadata{
+			RootFunction:        rootFunction,
+			Phase:               common.ExecutionPhase,
+			IatIndex:            i,
+			SuccessCount:        &successCount,
+			FailedCount:         &failedCount,
+			FunctionsInvoked:    &functionsInvoked,
+			RecordOutputChannel: output,
+			AnnounceDoneWG:      announceDone,
+		})
+		announceDone.Wait()
+	}
+	driver.liveMetrics.observeSchedulingLag(function.Name, -time.Millisecond)
+	driver.liveMetrics.observeSchedulingLag(function.Name, 3*time.Millisecond)
+
+	driver.liveMetrics.startMinute(1, driver.Configuration.Functions, driver.Configuration.TraceGranularity)
+
+	resp, err := http.Get("http://" + driver.liveMetrics.listener.Addr().String() + liveMetricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`loader_invocations_issued_total{function="f0"} 3`,
+		`loader_invocations_succeeded_total{function="f0"} 2`,
+		`loader_invocations_failed_total{function="f0",error_class="timeout"} 1`,
+		`loader_invocations_in_flight{function="f0"} 0`,
+		`loader_response_time_seconds_bucket{function="f0",le="0.016"} 2`,
+		`loader_response_time_seconds_count{function="f0"} 3`,
+		`loader_scheduling_lag_seconds_bucket{function="f0",le="0.0001"} 1`,
+		`loader_scheduling_lag_seconds_count{function="f0"} 2`,
+		`loader_experiment_minute 1`,
+		`loader_target_rps{function="f0"} 1`,
+		`loader_actual_rps{function="f0"} 0.05`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Missing %s in the exposed metrics\n%s", line, body)
+		}
+	}
+}
+
+func TestTargetInvocations(t *testing.T) {
+	perSecond := make([]int, 90)
+	for i := range perSecond {
+		perSecond[i] = 1
+	}
+
+	tests := []struct {
+		testName    string
+		granularity common.TraceGranularity
+		counts      []int
+		minute      int
+		expected    int
+	}{
+		{testName: "minute_granularity", granularity: common.MinuteGranularity, counts: []int{5, 7}, minute: 1, expected: 7},
+		{testName: "second_granularity", granularity: common.SecondGranularity, counts: perSecond, minute: 0, expected: 60},
+		{testName: "partial_minute", granularity: common.SecondGranularity, counts: perSecond, minute: 1, expected: 30},
+		{testName: "after_trace", granularity: common.MinuteGranularity, counts: []int{5, 7}, minute: 2, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			function := &common.Function{Specification: &common.FunctionSpecification{PerMinuteCount: test.counts}}
+			if target := targetInvocations(function, test.minute, test.granularity); target != test.expected {
+				t.Errorf("Unexpected target of %d invocations", target)
+			}
+		})
+	}
+}
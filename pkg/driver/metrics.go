@@ -1,10 +1,9 @@
 package driver
 
 import (
-	"encoding/json"
+	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
-	"os"
 	"sync"
 	"time"
 )
@@ -15,46 +14,35 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 
 	return func() {
 		signalReady.Done()
+		clusterUsageRecords := make(chan interface{}, 100)
 		knStatRecords := make(chan interface{}, 100)
 		scaleRecords := make(chan interface{}, 100)
 		writerDone := sync.WaitGroup{}
 
-		clusterUsageFile, err := os.Create(d.outputFilename("cluster_usage"))
-		common.Check(err)
-		defer clusterUsageFile.Close()
-
-		writerDone.Add(1)
-		go mc.RunCSVWriter(knStatRecords, d.outputFilename("kn_stats"), &writerDone)
-
-		writerDone.Add(1)
-		go mc.RunCSVWriter(scaleRecords, d.outputFilename("deployment_scale"), &writerDone)
+		writerDone.Add(3)
+		go mc.RunRecordWriter(clusterUsageRecords, d.recordSink(mc.ClusterUsageSchema, "cluster_usage", common.OutputFormatJSONLines), &writerDone)
+		go mc.RunRecordWriter(knStatRecords, d.recordSink(mc.KnStatsSchema, "kn_stats", common.OutputFormatCSV), &writerDone)
+		go mc.RunRecordWriter(scaleRecords, d.recordSink(mc.DeploymentScaleSchema, "deployment_scale", common.OutputFormatCSV), &writerDone)
 
 		for {
 			select {
 			case <-timer.C:
-				recCluster := mc.ScrapeClusterUsage()
+				recCluster := d.scrapeClusterUsage()
 				recCluster.Timestamp = time.Now().UnixMicro()
+				clusterUsageRecords <- recCluster
 
-				byteArr, err := json.Marshal(recCluster)
-				common.Check(err)
-
-				_, err = clusterUsageFile.Write(byteArr)
-				common.Check(err)
-
-				_, err = clusterUsageFile.WriteString("\n")
-				common.Check(err)
-
-				recScale := mc.ScrapeDeploymentScales()
+				recScale := d.scrapeDeploymentScales()
 				timestamp := time.Now().UnixMicro()
 				for _, rec := range recScale {
 					rec.Timestamp = timestamp
 					scaleRecords <- rec
 				}
 
-				recKnative := mc.ScrapeKnStats()
+				recKnative := d.scrapeKnStats()
 				recKnative.Timestamp = time.Now().UnixMicro()
 				knStatRecords <- recKnative
 			case <-finishCh:
+				close(clusterUsageRecords)
 				close(knStatRecords)
 				close(scaleRecords)
 
@@ -66,3 +54,64 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 		}
 	}
 }
+
+// metricsScraper returns the scraper of the cluster metrics, created at the first scrape, or nil if it cannot be
+func (d *Driver) metricsScraper() *mc.Scraper {
+	d.scraperOnce.Do(func() {
+		cfg := d.Configuration.LoaderConfiguration
+
+		scraper, err := mc.NewScraper(cfg.PrometheusAddress, cfg.KubeconfigPath)
+		if err != nil {
+			log.Warn("Fail to create the metrics scraper: ", err)
+			return
+		}
+		d.scraper = scraper
+	})
+
+	return d.scraper
+}
+
+// scrapeDeploymentScales reads the scales from the native metrics of the platform if the deployer reports them, and
+// from the Knative autoscaler metrics otherwise
+func (d *Driver) scrapeDeploymentScales() []mc.DeploymentScale {
+	var scales []mc.DeploymentScale
+	var err error
+	if d.scaleReporter != nil {
+		scales, err = d.scaleReporter.DeploymentScales()
+	} else if scraper := d.metricsScraper(); scraper != nil {
+		scales, err = scraper.DeploymentScales()
+	}
+	if err != nil {
+		log.Warn("Fail to scrape deployment scales: ", err)
+	}
+
+	return scales
+}
+
+func (d *Driver) scrapeKnStats() mc.KnStats {
+	scraper := d.metricsScraper()
+	if scraper == nil {
+		return mc.KnStats{}
+	}
+
+	stats, err := scraper.KnStats()
+	if err != nil {
+		log.Warn("Fail to scrape Knative: ", err)
+	}
+
+	return stats
+}
+
+func (d *Driver) scrapeClusterUsage() mc.ClusterUsage {
+	scraper := d.metricsScraper()
+	if scraper == nil {
+		return mc.ClusterUsage{}
+	}
+
+	usage, err := scraper.ClusterUsage()
+	if err != nil {
+		log.Warn("Fail to scrape cluster usage: ", err)
+	}
+
+	return usage
+}
@@ -0,0 +1,72 @@
+package driver
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+// preScalingUpdates Returns the minimum scale each function should be updated to at the beginning of the given
+// minute, and the functions whose schedule is over and whose minimum scale should hence be restored. Functions are
+// updated only when their scheduled scale changes.
+func preScalingUpdates(functions []*common.Function, minute int) (map[*common.Function]int, []*common.Function) {
+	updates := make(map[*common.Function]int)
+	var restores []*common.Function
+
+	for _, function := range functions {
+		schedule := function.ScaleSchedule
+
+		switch {
+		case len(schedule) == 0 || minute > len(schedule):
+			continue
+		case minute == len(schedule):
+			restores = append(restores, function)
+		case minute == 0 || schedule[minute] != schedule[minute-1]:
+			updates[function] = schedule[minute]
+		}
+	}
+
+	return updates, restores
+}
+
+// runPreScalingSchedule Applies the per-minute minimum scale from the static trace profiler over the warmup. It is
+// started together with the load generation, so the minutes of the schedule align with the minutes of the trace.
+func (d *Driver) runPreScalingSchedule(scheduler deployment.ScaleScheduler) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	// the schedules end in the minute after the last one, in which the minimum scale is restored
+	end := 0
+	for _, function := range d.Configuration.Functions {
+		end = common.MaxOf(end, len(function.ScaleSchedule))
+	}
+
+	for minute := 0; minute <= end; minute++ {
+		updates, restores := preScalingUpdates(d.Configuration.Functions, minute)
+
+		for function, minScale := range updates {
+			go func(function *common.Function, minScale int) {
+				if err := scheduler.UpdateMinScale(function, minScale); err != nil {
+					log.Warnf("Failed to apply the pre-scaling schedule in minute %d: %v", minute, err)
+				} else {
+					log.Debugf("Minimum scale of function %s set to %d in minute %d.", function.Name, minScale, minute)
+				}
+			}(function, minScale)
+		}
+		for _, function := range restores {
+			go func(function *common.Function) {
+				if err := scheduler.RestoreMinScale(function); err != nil {
+					log.Warnf("Failed to restore the minimum scale of function %s in minute %d: %v", function.Name, minute, err)
+				} else {
+					log.Debugf("Minimum scale of function %s restored in minute %d.", function.Name, minute)
+				}
+			}(function)
+		}
+
+		if minute < end {
+			<-ticker.C
+		}
+	}
+}
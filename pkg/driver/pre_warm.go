@@ -0,0 +1,244 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+const (
+	defaultPreWarmTimeout = 10 * time.Minute
+	preWarmPollInterval   = 5 * time.Second
+
+	// preWarmRuntimeMs Runtime of the synthetic invocations, long enough for each of them to hold an instance
+	preWarmRuntimeMs = 1000
+)
+
+// preWarmMetadata Outcome of the pre-warm phase, written next to the output of the experiment
+type preWarmMetadata struct {
+	Mode       string                 `json:"mode"`
+	Converged  bool                   `json:"converged"`
+	DurationMs int64                  `json:"durationMs"`
+	Functions  []functionPreWarmState `json:"functions"`
+}
+
+type functionPreWarmState struct {
+	Function string `json:"function"`
+	Target   int    `json:"target"`
+	Observed int    `json:"observed"`
+	// DurationMs Time until the function first reached its target, if it did
+	DurationMs int64 `json:"durationMs,omitempty"`
+	Reached    bool  `json:"reached"`
+}
+
+// preWarmTargets Returns the target scale of each function, being the configured scale if set and the initial scale
+// from the static trace profiler otherwise. Functions with no target are left out.
+func preWarmTargets(functions []*common.Function, scale int) map[*common.Function]int {
+	targets := make(map[*common.Function]int)
+
+	for _, function := range functions {
+		target := scale
+		if target <= 0 {
+			target = function.InitialScale
+		}
+		if target > 0 {
+			targets[function] = target
+		}
+	}
+
+	return targets
+}
+
+// observesScale Tells whether the scale of the functions is observable, either from the Knative autoscaler metrics or
+// from the native metrics of the platform
+func (d *Driver) observesScale() bool {
+	return d.scaleReporter != nil || d.Configuration.LoaderConfiguration.Platform == common.PlatformKnative
+}
+
+// preWarm Drives the functions to their target number of ready instances before the trace starts, and waits until the
+// observed scale reaches the targets or the timeout expires. The experiment is run either way, while the time it took
+// is written to the pre-warm metadata. Returns the functions whose minimum scale was raised in the scale mode, which
+// is to be restored only once the trace runs, as restoring it rolls out a new revision on Knative.
+func (d *Driver) preWarm(deployer deployment.FunctionDeployer, pollInterval time.Duration) []*common.Function {
+	cfg := d.Configuration.LoaderConfiguration
+	if cfg.PreWarmMode == "" {
+		return nil
+	}
+	if !d.observesScale() {
+		log.Warnf("The scale of the functions on platform %s is not observable. Skipping the pre-warm phase.", cfg.Platform)
+		return nil
+	}
+
+	targets := preWarmTargets(d.Configuration.Functions, cfg.PreWarmScale)
+	if len(targets) == 0 {
+		log.Warn("No function has a target scale to pre-warm to. Skipping the pre-warm phase.")
+		return nil
+	}
+
+	timeout := time.Duration(cfg.PreWarmTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultPreWarmTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mode := cfg.PreWarmMode
+	scheduler, ok := deployer.(deployment.ScaleScheduler)
+	if mode == common.PreWarmScale && !ok {
+		log.Warnf("Platform %s does not support setting the minimum scale. Pre-warming by invoking the functions instead.", cfg.Platform)
+		mode = common.PreWarmInvoke
+	}
+
+	log.Infof("Pre-warming %d functions in the %s mode.", len(targets), mode)
+	start := time.Now()
+
+	var raised []*common.Function
+	invoking := &sync.WaitGroup{}
+	invocationCtx, stopInvoking := context.WithCancel(ctx)
+	if mode == common.PreWarmScale {
+		for function, target := range targets {
+			if err := scheduler.UpdateMinScale(function, target); err != nil {
+				log.Warnf("Failed to set the minimum scale of function %s to %d - %v", function.Name, target, err)
+			} else {
+				raised = append(raised, function)
+			}
+		}
+	} else {
+		for function, target := range targets {
+			for i := 0; i < target; i++ {
+				invoking.Add(1)
+				go d.invokeUntilDone(invocationCtx, function, pollInterval, invoking)
+			}
+		}
+	}
+
+	metadata := d.waitForScale(ctx, targets, pollInterval)
+	metadata.Mode = mode
+	metadata.DurationMs = time.Since(start).Milliseconds()
+
+	stopInvoking()
+	invoking.Wait()
+
+	if metadata.Converged {
+		log.Infof("Pre-warmed all functions to their target scale in %v.", time.Since(start))
+	} else {
+		log.Warnf("Not all functions reached their target scale within %v. Running the experiment nonetheless.", timeout)
+	}
+
+	if err := writePreWarmMetadata(d.outputMetadataFilename("prewarm"), metadata); err != nil {
+		log.Errorf("Failed to write the pre-warm metadata - %v", err)
+	}
+
+	return raised
+}
+
+// preWarmRestoreDelay Returns how long the minimum scale raised by the pre-warm phase is kept once the trace starts,
+// being the warmup of the trace, or its first minute if there is no warmup
+func (d *Driver) preWarmRestoreDelay() time.Duration {
+	return time.Duration(common.MaxOf(d.Configuration.LoaderConfiguration.WarmupDuration, 1)) * time.Minute
+}
+
+// restorePreWarmScale Restores the minimum scale of the pre-warmed functions after the delay. It is started together
+// with the load generation, so the warm instances serve the beginning of the trace, while the revisions rolled out by
+// restoring the minimum scale start from the load of the trace. Functions with a pre-scaling schedule are left to the
+// schedule, which sets their minimum scale from the first minute on and restores it at its end.
+func (d *Driver) restorePreWarmScale(scheduler deployment.ScaleScheduler, functions []*common.Function, delay time.Duration) {
+	scheduled := d.Configuration.LoaderConfiguration.ScaleProfilingStrategy == common.ScaleProfilingSchedule
+
+	time.Sleep(delay)
+
+	for _, function := range functions {
+		if scheduled && len(function.ScaleSchedule) > 0 {
+			continue
+		}
+
+		if err := scheduler.RestoreMinScale(function); err != nil {
+			log.Warnf("Failed to restore the minimum scale of function %s - %v", function.Name, err)
+		} else {
+			log.Debugf("Minimum scale of function %s restored after the pre-warm phase.", function.Name)
+		}
+	}
+}
+
+// invokeUntilDone Invokes the function back to back until the context is done, backing off after failed invocations
+func (d *Driver) invokeUntilDone(ctx context.Context, function *common.Function, backoff time.Duration, done *sync.WaitGroup) {
+	defer done.Done()
+
+	runtimeSpecification := &common.RuntimeSpecification{Runtime: preWarmRuntimeMs, Memory: 1}
+	for ctx.Err() == nil {
+		if success, _ := d.Invoker.Invoke(function, runtimeSpecification); success {
+			continue
+		}
+
+		log.Debugf("Pre-warm invocation of function %s failed.", function.Name)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// waitForScale Polls the scale of the functions until all of them have at least their target number of running
+// instances at the same time, or the context is done
+func (d *Driver) waitForScale(ctx context.Context, targets map[*common.Function]int, pollInterval time.Duration) *preWarmMetadata {
+	start := time.Now()
+
+	states := make(map[string]*functionPreWarmState)
+	metadata := &preWarmMetadata{}
+	for _, function := range d.Configuration.Functions {
+		if target, ok := targets[function]; ok {
+			state := &functionPreWarmState{Function: function.Name, Target: target}
+			states[function.Name] = state
+			metadata.Functions = append(metadata.Functions, *state)
+		}
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, scale := range d.scrapeDeploymentScales() {
+			state, ok := states[scale.Function]
+			if !ok {
+				continue
+			}
+
+			state.Observed = scale.RunningPods
+			if !state.Reached && state.Observed >= state.Target {
+				state.Reached = true
+				state.DurationMs = time.Since(start).Milliseconds()
+				log.Debugf("Function %s reached its target scale of %d.", state.Function, state.Target)
+			}
+		}
+
+		metadata.Converged = true
+		for i := range metadata.Functions {
+			metadata.Functions[i] = *states[metadata.Functions[i].Function]
+			metadata.Converged = metadata.Converged && metadata.Functions[i].Observed >= metadata.Functions[i].Target
+		}
+		if metadata.Converged {
+			return metadata
+		}
+
+		select {
+		case <-ctx.Done():
+			return metadata
+		case <-ticker.C:
+		}
+	}
+}
+
+func writePreWarmMetadata(path string, metadata *preWarmMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,245 @@
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// warmingPlatform Reports as running the most instances held at a time by the invocations of a function since the
+// last scrape, or its minimum scale, up to the capacity of the platform. Records the minimum scales set and restored.
+// Like on Knative, changing the minimum scale rolls out a new revision, which starts without the instances of the
+// previous one.
+type warmingPlatform struct {
+	scriptedDeployer
+
+	mutex       sync.Mutex
+	capacity    int
+	invocations int
+	inFlight    map[string]int
+	peak        map[string]int
+	minScale    map[string]int
+	updated     map[string]int
+	restored    map[string]bool
+	revisions   map[string]int
+}
+
+func newWarmingPlatform(capacity int) *warmingPlatform {
+	return &warmingPlatform{
+		capacity:  capacity,
+		inFlight:  map[string]int{},
+		peak:      map[string]int{},
+		minScale:  map[string]int{},
+		updated:   map[string]int{},
+		restored:  map[string]bool{},
+		revisions: map[string]int{},
+	}
+}
+
+func (p *warmingPlatform) Invoke(function *common.Function, _ *common.RuntimeSpecification) (bool, *metric.ExecutionRecord) {
+	p.mutex.Lock()
+	p.invocations++
+	p.inFlight[function.Name]++
+	p.peak[function.Name] = common.MaxOf(p.peak[function.Name], p.inFlight[function.Name])
+	p.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	p.mutex.Lock()
+	p.inFlight[function.Name]--
+	p.mutex.Unlock()
+
+	return true, &metric.ExecutionRecord{}
+}
+
+func (p *warmingPlatform) UpdateMinScale(function *common.Function, minScale int) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.minScale[function.Name] = minScale
+	p.updated[function.Name] = minScale
+	p.revisions[function.Name]++
+	p.peak[function.Name] = 0
+	return nil
+}
+
+func (p *warmingPlatform) RestoreMinScale(function *common.Function) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.minScale, function.Name)
+	p.restored[function.Name] = true
+	p.revisions[function.Name]++
+	p.peak[function.Name] = 0
+	return nil
+}
+
+func (p *warmingPlatform) DeploymentScales() ([]metric.DeploymentScale, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var scales []metric.DeploymentScale
+	for _, name := range []string{"f0", "f1", "f2"} {
+		running := common.MinOf(common.MaxOf(p.peak[name], p.minScale[name]), p.capacity)
+		scales = append(scales, metric.DeploymentScale{Function: name, RunningPods: running})
+		p.peak[name] = p.inFlight[name]
+	}
+
+	return scales, nil
+}
+
+func TestPreWarm(t *testing.T) {
+	tests := []struct {
+		testName   string
+		platform   string
+		mode       string
+		scale      int
+		capacity   int
+		skipped    bool
+		converged  bool
+		targets    map[string]int
+		invoked    bool
+		minScale   map[string]int
+		observable bool
+	}{
+		{testName: "invoke_to_initial_scale", platform: common.PlatformOpenFaaS, mode: common.PreWarmInvoke, capacity: 10, observable: true, converged: true, targets: map[string]int{"f0": 2, "f2": 1}, invoked: true, minScale: map[string]int{}},
+		{testName: "scale_to_configured_scale", platform: common.PlatformOpenFaaS, mode: common.PreWarmScale, scale: 3, capacity: 10, observable: true, converged: true, targets: map[string]int{"f0": 3, "f1": 3, "f2": 3}, minScale: map[string]int{"f0": 3, "f1": 3, "f2": 3}},
+		{testName: "not_converged", platform: common.PlatformOpenFaaS, mode: common.PreWarmScale, scale: 3, capacity: 2, observable: true, converged: false, targets: map[string]int{"f0": 3, "f1": 3, "f2": 3}, minScale: map[string]int{"f0": 3, "f1": 3, "f2": 3}},
+		{testName: "disabled", platform: common.PlatformOpenFaaS, capacity: 10, observable: true, skipped: true, minScale: map[string]int{}},
+		{testName: "not_observable", platform: common.PlatformOpenWhisk, mode: common.PreWarmInvoke, capacity: 10, skipped: true, minScale: map[string]int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			prefix := filepath.Join(t.TempDir(), "test")
+			platform := newWarmingPlatform(test.capacity)
+
+			driver := &Driver{
+				Configuration: &config.Configuration{
+					LoaderConfiguration: &config.LoaderConfiguration{
+						Platform:              test.platform,
+						OutputPathPrefix:      prefix,
+						PreWarmMode:           test.mode,
+						PreWarmScale:          test.scale,
+						PreWarmTimeoutSeconds: 1,
+					},
+					TraceDuration: 1,
+					Functions:     []*common.Function{{Name: "f0", InitialScale: 2}, {Name: "f1"}, {Name: "f2", InitialScale: 1}},
+				},
+				Invoker: platform,
+			}
+			if test.observable {
+				driver.scaleReporter = platform
+			}
+
+			raised := driver.preWarm(platform, 5*time.Millisecond)
+
+			data, err := os.ReadFile(prefix + "_prewarm_1.json")
+			if test.skipped {
+				if err == nil || platform.invocations > 0 {
+					t.Errorf("Pre-warm phase not skipped")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			var metadata preWarmMetadata
+			if err = json.Unmarshal(data, &metadata); err != nil {
+				t.Fatal(err)
+			}
+			if metadata.Converged != test.converged || metadata.Mode != test.mode || len(metadata.Functions) != len(test.targets) {
+				t.Errorf("Unexpected pre-warm metadata %+v", metadata)
+			}
+			for _, function := range metadata.Functions {
+				if function.Target != test.targets[function.Function] || function.Reached != (function.Observed >= function.Target) {
+					t.Errorf("Unexpected pre-warm state %+v", function)
+				}
+			}
+
+			if (platform.invocations > 0) != test.invoked {
+				t.Errorf("Unexpected number of pre-warm invocations %d", platform.invocations)
+			}
+			// the minimum scale is kept past the phase, so the warm instances are still there when the trace starts
+			for name, minScale := range test.minScale {
+				if platform.updated[name] != minScale || platform.minScale[name] != minScale || platform.revisions[name] != 1 {
+					t.Errorf("Unexpected minimum scale of function %s - %d, revisions: %d", name, platform.minScale[name], platform.revisions[name])
+				}
+			}
+			if len(platform.updated) != len(test.minScale) || len(platform.restored) != 0 || len(raised) != len(test.minScale) {
+				t.Errorf("Unexpected minimum scales - updated: %v; restored: %v; raised: %d", platform.updated, platform.restored, len(raised))
+			}
+		})
+	}
+}
+
+func TestRestorePreWarmScale(t *testing.T) {
+	tests := []struct {
+		testName string
+		strategy string
+		restored map[string]bool
+	}{
+		{testName: "restore_all", strategy: common.ScaleProfilingFirstMinute, restored: map[string]bool{"f0": true, "f1": true}},
+		{testName: "leave_scheduled", strategy: common.ScaleProfilingSchedule, restored: map[string]bool{"f1": true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			functions := []*common.Function{{Name: "f0", ScaleSchedule: []int{3, 1}}, {Name: "f1"}}
+			platform := newWarmingPlatform(10)
+			for _, function := range functions {
+				_ = platform.UpdateMinScale(function, 3)
+			}
+
+			driver := &Driver{
+				Configuration: &config.Configuration{
+					LoaderConfiguration: &config.LoaderConfiguration{ScaleProfilingStrategy: test.strategy},
+					Functions:           functions,
+				},
+			}
+
+			done := make(chan struct{})
+			go func() {
+				driver.restorePreWarmScale(platform, functions, 50*time.Millisecond)
+				close(done)
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			scales, _ := platform.DeploymentScales()
+			platform.mutex.Lock()
+			if len(platform.restored) != 0 || scales[0].RunningPods != 3 || scales[1].RunningPods != 3 {
+				t.Errorf("The warm instances should be kept until the delay expires - restored: %v; scales: %v", platform.restored, scales)
+			}
+			platform.mutex.Unlock()
+
+			<-done
+			scales, _ = platform.DeploymentScales()
+			for i, function := range functions {
+				restored := test.restored[function.Name]
+				if platform.restored[function.Name] != restored || platform.revisions[function.Name] != map[bool]int{false: 1, true: 2}[restored] {
+					t.Errorf("Unexpected restore of function %s - restored: %t; revisions: %d", function.Name, platform.restored[function.Name], platform.revisions[function.Name])
+				}
+				// the revision rolled out by the restore starts without the warm instances
+				if restored && scales[i].RunningPods != 0 {
+					t.Errorf("Unexpected scale of the new revision of function %s - %d", function.Name, scales[i].RunningPods)
+				}
+			}
+		})
+	}
+}
+
+func TestPreWarmRestoreDelay(t *testing.T) {
+	for warmup, expected := range map[int]time.Duration{0: time.Minute, 1: time.Minute, 3: 3 * time.Minute} {
+		driver := &Driver{Configuration: &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{WarmupDuration: warmup}}}
+		if delay := driver.preWarmRestoreDelay(); delay != expected {
+			t.Errorf("Unexpected restore delay with a warmup of %d minutes - %v", warmup, delay)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package driver
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	defaultBackoffMultiplier = 2.0
+	defaultHedgingMinSamples = 20
+	// latencyWindow Number of the most recent latencies of a function the hedging percentile is computed over
+	latencyWindow = 1000
+)
+
+// retryPolicy Policy of a function, resolved from its configuration
+type retryPolicy struct {
+	*config.RetryPolicy
+	// nil if all the failures are retried
+	retryable map[mc.ErrorClass]bool
+}
+
+func newRetryPolicy(cfg *config.RetryPolicy) *retryPolicy {
+	policy := &retryPolicy{RetryPolicy: cfg}
+
+	if len(cfg.RetryableErrorClasses) > 0 {
+		policy.retryable = make(map[mc.ErrorClass]bool)
+		for _, class := range cfg.RetryableErrorClasses {
+			policy.retryable[mc.ErrorClass(class)] = true
+		}
+	}
+
+	return policy
+}
+
+func (p *retryPolicy) maxAttempts() int {
+	return common.MaxOf(p.MaxAttempts, 1)
+}
+
+// retries Returns true if the failure of the attempt is retried
+func (p *retryPolicy) retries(record *mc.ExecutionRecord) bool {
+	return p.retryable == nil || p.retryable[record.ErrorClass]
+}
+
+func (p *retryPolicy) hedges() bool {
+	return p.HedgingPercentile > 0
+}
+
+func (p *retryPolicy) hedgingMinSamples() int {
+	if p.HedgingMinSamples <= 0 {
+		return defaultHedgingMinSamples
+	}
+
+	return p.HedgingMinSamples
+}
+
+// latencyTracker Keeps the latencies of the most recent successful invocations of each function
+type latencyTracker struct {
+	mutex     sync.Mutex
+	latencies map[string][]int64
+	next      map[string]int
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{
+		latencies: make(map[string][]int64),
+		next:      make(map[string]int),
+	}
+}
+
+// Add Adds the latency in microseconds, replacing the oldest one if the window is full
+func (t *latencyTracker) Add(function string, latency int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	latencies := t.latencies[function]
+	if len(latencies) < latencyWindow {
+		t.latencies[function] = append(latencies, latency)
+		return
+	}
+
+	latencies[t.next[function]] = latency
+	t.next[function] = (t.next[function] + 1) % latencyWindow
+}
+
+// Percentile Returns the percentile of the latencies of the function, if at least minSamples latencies are known
+func (t *latencyTracker) Percentile(function string, percentile float64, minSamples int) (time.Duration, bool) {
+	t.mutex.Lock()
+	sorted := append([]int64(nil), t.latencies[function]...)
+	t.mutex.Unlock()
+
+	if len(sorted) == 0 || len(sorted) < minSamples {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+	index = common.MinOf(common.MaxOf(index, 0), len(sorted)-1)
+
+	return time.Duration(sorted[index]) * time.Microsecond, true
+}
+
+// invocationRetrier Resolves the retry policies of the functions, and holds the state needed to apply them
+type invocationRetrier struct {
+	global    *retryPolicy
+	functions map[string]*retryPolicy
+	latencies *latencyTracker
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+func newInvocationRetrier(cfg *config.LoaderConfiguration) *invocationRetrier {
+	retrier := &invocationRetrier{
+		functions: make(map[string]*retryPolicy),
+		latencies: newLatencyTracker(),
+		random:    rand.New(rand.NewSource(cfg.Seed)),
+	}
+
+	if cfg.RetryPolicy != nil {
+		retrier.global = newRetryPolicy(cfg.RetryPolicy)
+	} else if cfg.DAGMode {
+		// failed functions of a DAG are retried once unless configured otherwise
+		retrier.global = newRetryPolicy(&config.RetryPolicy{MaxAttempts: 2})
+	}
+
+	for key, policy := range cfg.FunctionRetryPolicies {
+		if policy != nil {
+			retrier.functions[key] = newRetryPolicy(policy)
+		}
+	}
+
+	return retrier
+}
+
+// policy Returns the policy of the function, or nil if the function is invoked once
+func (r *invocationRetrier) policy(function *common.Function) *retryPolicy {
+	if policy, ok := r.functions[function.Name]; ok {
+		return policy
+	}
+	if function.InvocationStats != nil {
+		if policy, ok := r.functions[function.InvocationStats.HashFunction]; ok {
+			return policy
+		}
+	}
+
+	return r.global
+}
+
+// backoff Returns the delay before the given retry, which grows exponentially from the initial backoff up to the
+// maximum backoff, and is randomly shortened or extended by up to the jitter fraction
+func (r *invocationRetrier) backoff(policy *retryPolicy, retry int) time.Duration {
+	multiplier := policy.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	delay := float64(policy.InitialBackoffMs) * math.Pow(multiplier, float64(retry-1))
+	if policy.MaxBackoffMs > 0 {
+		delay = math.Min(delay, float64(policy.MaxBackoffMs))
+	}
+
+	if policy.BackoffJitter > 0 {
+		r.mutex.Lock()
+		delay *= 1 + policy.BackoffJitter*(2*r.random.Float64()-1)
+		r.mutex.Unlock()
+	}
+
+	return time.Duration(delay * float64(time.Millisecond))
+}
+
+type attemptResult struct {
+	success bool
+	record  *mc.ExecutionRecord
+}
+
+// invokeWithRetries Invokes the function according to its retry policy. The records of the attempts that did not
+// decide the outcome of the invocation are written out as they complete, while the decisive record is returned.
+func (d *Driver) invokeWithRetries(invoker clients.Invoker, node *common.Node, runtimeSpec *common.RuntimeSpecification, metadata *InvocationMetadata) (bool, *mc.ExecutionRecord) {
+	function := node.Function
+
+	policy := d.retrier.policy(function)
+	if policy == nil {
+		return invoker.Invoke(function, runtimeSpec)
+	}
+
+	// duplicates of submissions would be completed twice
+	hedges := policy.hedges() && !d.submitsAsynchronously(invoker)
+
+	for attempt := 0; ; attempt++ {
+		var success bool
+		var record *mc.ExecutionRecord
+		if hedges {
+			success, record = d.invokeHedged(invoker, node, runtimeSpec, policy, attempt, metadata)
+		} else {
+			success, record = invoker.Invoke(function, runtimeSpec)
+		}
+		record.RetryCount = attempt
+
+		if success || attempt+1 >= policy.maxAttempts() || !policy.retries(record) {
+			return success, record
+		}
+
+		d.writeAttemptRecord(record, node, metadata)
+		time.Sleep(d.retrier.backoff(policy, attempt+1))
+	}
+}
+
+// invokeHedged Issues a duplicate request if the attempt did not return within the hedging percentile of the
+// latencies of the function. The first successful request decides the attempt, and the record of the other request is
+// written out once it returns.
+func (d *Driver) invokeHedged(invoker clients.Invoker, node *common.Node, runtimeSpec *common.RuntimeSpecification, policy *retryPolicy, attempt int, metadata *InvocationMetadata) (bool, *mc.ExecutionRecord) {
+	function := node.Function
+
+	results := make(chan attemptResult, 2)
+	invoke := func(hedged bool) {
+		success, record := invoker.Invoke(function, runtimeSpec)
+		record.RetryCount = attempt
+		record.Hedged = hedged
+
+		if success {
+			d.retrier.latencies.Add(function.Name, record.ResponseTime)
+		}
+		results <- attemptResult{success: success, record: record}
+	}
+
+	delay, ok := d.retrier.latencies.Percentile(function.Name, policy.HedgingPercentile, policy.hedgingMinSamples())
+	if !ok {
+		invoke(false)
+		result := <-results
+
+		return result.success, result.record
+	}
+
+	go invoke(false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case result := <-results:
+		return result.success, result.record
+	case <-timer.C:
+	}
+
+	go invoke(true)
+
+	first := <-results
+	if first.success {
+		metadata.AnnounceDoneWG.Add(1)
+		go func() {
+			defer metadata.AnnounceDoneWG.Done()
+
+			other := <-results
+			d.writeAttemptRecord(other.record, node, metadata)
+		}()
+
+		return true, first.record
+	}
+
+	second := <-results
+	d.writeAttemptRecord(first.record, node, metadata)
+
+	return second.success, second.record
+}
+
+// writeAttemptRecord Writes out the record of an attempt that did not decide the outcome of the invocation
+func (d *Driver) writeAttemptRecord(record *mc.ExecutionRecord, node *common.Node, metadata *InvocationMetadata) {
+	labelRecord(record, node, metadata)
+
+	metadata.RecordOutputChannel <- record
+	atomic.AddInt64(metadata.FunctionsInvoked, 1)
+}
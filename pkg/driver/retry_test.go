@@ -0,0 +1,173 @@
+package driver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// scriptedInvoker fails the first invocations with the given error class, and delays the given invocations
+type scriptedInvoker struct {
+	mutex    sync.Mutex
+	calls    int
+	failures int
+	class    metric.ErrorClass
+	delays   map[int]time.Duration
+}
+
+func (i *scriptedInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *metric.ExecutionRecord) {
+	i.mutex.Lock()
+	call := i.calls
+	i.calls++
+	i.mutex.Unlock()
+
+	time.Sleep(i.delays[call])
+
+	record := &metric.ExecutionRecord{}
+	record.ResponseTime = i.delays[call].Microseconds()
+	if call < i.failures {
+		record.FunctionTimeout = true
+		record.Fail(i.class, 0, "failed")
+		return false, record
+	}
+
+	return true, record
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		testName string
+		policy   config.RetryPolicy
+		retry    int
+		minimum  time.Duration
+		maximum  time.Duration
+	}{
+		{testName: "first_retry", policy: config.RetryPolicy{InitialBackoffMs: 100}, retry: 1, minimum: 100 * time.Millisecond, maximum: 100 * time.Millisecond},
+		{testName: "exponential", policy: config.RetryPolicy{InitialBackoffMs: 100, BackoffMultiplier: 3}, retry: 3, minimum: 900 * time.Millisecond, maximum: 900 * time.Millisecond},
+		{testName: "capped", policy: config.RetryPolicy{InitialBackoffMs: 100, MaxBackoffMs: 300}, retry: 5, minimum: 300 * time.Millisecond, maximum: 300 * time.Millisecond},
+		{testName: "jitter", policy: config.RetryPolicy{InitialBackoffMs: 100, BackoffJitter: 0.5}, retry: 2, minimum: 100 * time.Millisecond, maximum: 300 * time.Millisecond},
+	}
+
+	retrier := newInvocationRetrier(&config.LoaderConfiguration{Seed: 42})
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				backoff := retrier.backoff(newRetryPolicy(&test.policy), test.retry)
+				if backoff < test.minimum || backoff > test.maximum {
+					t.Fatalf("Backoff %v out of [%v, %v]", backoff, test.minimum, test.maximum)
+				}
+			}
+		})
+	}
+}
+
+func TestLatencyTrackerPercentile(t *testing.T) {
+	tracker := newLatencyTracker()
+	for i := 1; i <= latencyWindow+100; i++ {
+		tracker.Add("f", int64(i))
+	}
+
+	if _, ok := tracker.Percentile("g", 50, 1); ok {
+		t.Error("Percentile of a function without latencies should be unknown.")
+	}
+	// the oldest 100 latencies were replaced
+	if p, ok := tracker.Percentile("f", 50, 10); !ok || p != 600*time.Microsecond {
+		t.Errorf("Unexpected median %v", p)
+	}
+	if p, _ := tracker.Percentile("f", 100, 10); p != time.Duration(latencyWindow+100)*time.Microsecond {
+		t.Errorf("Unexpected maximum %v", p)
+	}
+}
+
+func invokeScripted(t *testing.T, driver *Driver, invoker *scriptedInvoker) (bool, []*metric.ExecutionRecord) {
+	var functionsInvoked int64
+	output := make(chan *metric.ExecutionRecord, 10)
+	announceDone := &sync.WaitGroup{}
+
+	metadata := &InvocationMetadata{
+		Phase:               common.ExecutionPhase,
+		InvocationID:        "min0.inv0",
+		FunctionsInvoked:    &functionsInvoked,
+		RecordOutputChannel: output,
+		AnnounceDoneWG:      announceDone,
+	}
+	node := &common.Node{Function: driver.Configuration.Functions[0]}
+
+	announceDone.Add(1)
+	success, record := driver.invokeWithRetries(invoker, node, &common.RuntimeSpecification{}, metadata)
+	announceDone.Done()
+	announceDone.Wait()
+
+	close(output)
+	var records []*metric.ExecutionRecord
+	for attempt := range output {
+		records = append(records, attempt)
+	}
+	if int(functionsInvoked) != len(records) {
+		t.Errorf("Written records not counted as invoked - %d records; %d invoked", len(records), functionsInvoked)
+	}
+
+	return success, append(records, record)
+}
+
+func TestInvokeWithRetries(t *testing.T) {
+	tests := []struct {
+		testName         string
+		policy           *config.RetryPolicy
+		failures         int
+		class            metric.ErrorClass
+		expectedSuccess  bool
+		expectedAttempts int
+	}{
+		{testName: "no_policy", failures: 1, class: metric.ErrorClassConnect, expectedSuccess: false, expectedAttempts: 1},
+		{testName: "retried", policy: &config.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1}, failures: 2, class: metric.ErrorClassOverload, expectedSuccess: true, expectedAttempts: 3},
+		{testName: "attempts_exhausted", policy: &config.RetryPolicy{MaxAttempts: 2}, failures: 2, class: metric.ErrorClassOverload, expectedSuccess: false, expectedAttempts: 2},
+		{testName: "not_retryable", policy: &config.RetryPolicy{MaxAttempts: 3, RetryableErrorClasses: []string{"overload"}}, failures: 1, class: metric.ErrorClassNotFound, expectedSuccess: false, expectedAttempts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			driver := createTestDriver([]int{1}, false)
+			driver.retrier = newInvocationRetrier(&config.LoaderConfiguration{
+				FunctionRetryPolicies: map[string]*config.RetryPolicy{"test-function": test.policy},
+			})
+
+			success, records := invokeScripted(t, driver, &scriptedInvoker{failures: test.failures, class: test.class})
+
+			if success != test.expectedSuccess || len(records) != test.expectedAttempts {
+				t.Fatalf("Unexpected outcome - success: %t; attempts: %d", success, len(records))
+			}
+			for attempt, record := range records {
+				if record.RetryCount != attempt || (attempt < len(records)-1 && record.InvocationID != "min0.inv0") {
+					t.Errorf("Unexpected record of attempt %d %+v", attempt, record.ExecutionRecordBase)
+				}
+			}
+		})
+	}
+}
+
+func TestInvokeHedged(t *testing.T) {
+	driver := createTestDriver([]int{1}, false)
+	driver.retrier = newInvocationRetrier(&config.LoaderConfiguration{
+		RetryPolicy: &config.RetryPolicy{HedgingPercentile: 90, HedgingMinSamples: 5},
+	})
+	for i := 0; i < 10; i++ {
+		driver.retrier.latencies.Add("test-function", (50 * time.Millisecond).Microseconds())
+	}
+
+	// the first request is slow, so the duplicate issued after 50 ms decides the invocation
+	invoker := &scriptedInvoker{delays: map[int]time.Duration{0: 500 * time.Millisecond}}
+	success, records := invokeScripted(t, driver, invoker)
+
+	if !success || len(records) != 2 {
+		t.Fatalf("Unexpected outcome - success: %t; records: %d", success, len(records))
+	}
+	if slow, decisive := records[0], records[1]; !decisive.Hedged || slow.Hedged || slow.ResponseTime == 0 {
+		t.Errorf("Unexpected hedged records %+v %+v", slow.ExecutionRecordBase, decisive.ExecutionRecordBase)
+	}
+}
@@ -50,10 +50,27 @@ type Driver struct {
 	Configuration          *config.Configuration
 	SpecificationGenerator *generator.SpecificationGenerator
 	Invoker                clients.Invoker
+	// AsyncInvoker used for asynchronously triggered functions if trigger semantics are enabled
+	AsyncInvoker clients.Invoker
+	// Deployer deploys the functions before the experiment and cleans them up afterwards
+	Deployer deployment.FunctionDeployer
 
 	AsyncRecords          *common.LockFreeQueue[*mc.ExecutionRecord]
+	completionReceiver    *completionReceiver
+	liveMetrics           *liveMetrics
+	latencySummary        *mc.LatencySummary
+	scaleReporter         deployment.ScaleReporter
+	scraper               *mc.Scraper
+	scraperOnce           sync.Once
+	triggerBreakdown      *invocationBreakdown
+	failureBreakdown      *invocationBreakdown
+	retrier               *invocationRetrier
 	readOpenWhiskMetadata sync.Mutex
 	allFunctionsInvoked   sync.WaitGroup
+
+	// asyncCompleted and asyncFailed count the outcomes of the invocations tracked by the completion receiver
+	asyncCompleted int64
+	asyncFailed    int64
 }
 
 func NewDriver(driverConfig *config.Configuration) *Driver {
@@ -62,11 +79,18 @@ func NewDriver(driverConfig *config.Configuration) *Driver {
 		SpecificationGenerator: generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed),
 
 		AsyncRecords:          common.NewLockFreeQueue[*mc.ExecutionRecord](),
+		triggerBreakdown:      newInvocationBreakdown(),
+		failureBreakdown:      newInvocationBreakdown(),
+		retrier:               newInvocationRetrier(driverConfig.LoaderConfiguration),
+		liveMetrics:           newLiveMetrics(),
+		latencySummary:        mc.NewLatencySummary(mc.DefaultSignificantFigures),
 		readOpenWhiskMetadata: sync.Mutex{},
 		allFunctionsInvoked:   sync.WaitGroup{},
 	}
 
 	d.Invoker = clients.CreateInvoker(driverConfig, &d.allFunctionsInvoked, &d.readOpenWhiskMetadata)
+	d.AsyncInvoker = clients.CreateAsyncInvoker(driverConfig)
+	d.Deployer = deployment.CreateDeployer(driverConfig)
 
 	return d
 }
@@ -78,6 +102,26 @@ func (d *Driver) outputFilename(name string) string {
 	return fmt.Sprintf("%s_%s_%d.csv", d.Configuration.LoaderConfiguration.OutputPathPrefix, name, d.Configuration.TraceDuration)
 }
 
+// recordSink Creates the sink of the records of the schema in the output format selected for their type, or in the
+// default format without schema version otherwise, in which case the file is named as it always was
+func (d *Driver) recordSink(schema mc.RecordSchema, name string, defaultFormat string) mc.RecordSink {
+	format := d.Configuration.LoaderConfiguration.OutputFormats[schema.Name]
+	filename := fmt.Sprintf("%s_%s_%d%s", d.Configuration.LoaderConfiguration.OutputPathPrefix, name,
+		d.Configuration.TraceDuration, mc.OutputExtension(format))
+	if format == "" {
+		format, filename, schema = defaultFormat, d.outputFilename(name), schema.Unversioned()
+	}
+
+	sink, err := mc.NewRecordSink(format, filename, schema)
+	common.Check(err)
+
+	return sink
+}
+
+func (d *Driver) outputMetadataFilename(name string) string {
+	return fmt.Sprintf("%s_%s_%d.json", d.Configuration.LoaderConfiguration.OutputPathPrefix, name, d.Configuration.TraceDuration)
+}
+
 /////////////////////////////////////////
 // DRIVER LOGIC
 /////////////////////////////////////////
@@ -88,6 +132,8 @@ type InvocationMetadata struct {
 
 	InvocationID string
 	IatIndex     int
+	// Minute of the trace in which the invocation is issued
+	Minute int
 
 	SuccessCount        *int64
 	FailedCount         *int64
@@ -112,6 +158,53 @@ func composeInvocationID(timeGranularity common.TraceGranularity, minuteIndex in
 	return fmt.Sprintf("%s%d.inv%d", timePrefix, minuteIndex, invocationIndex)
 }
 
+// collectsAsyncResponses returns true if the platform invoker only submits invocations, whose responses are
+// either pushed to the completion receiver or collected at the end of the experiment
+func (d *Driver) collectsAsyncResponses() bool {
+	return (d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode) ||
+		d.Configuration.LoaderConfiguration.KnativeAsyncMode
+}
+
+// receivesPushedCompletions returns true if the completions of asynchronous invocations are pushed to the loader
+// during the experiment, rather than polled for after it. Only the functions invoked through Knative Eventing push
+// their completions, while the responses of Dirigent are always polled from AsyncResponseURL.
+func (d *Driver) receivesPushedCompletions() bool {
+	return d.Configuration.LoaderConfiguration.KnativeAsyncMode
+}
+
+// selectInvoker returns the invoker matching the trigger of the function
+func (d *Driver) selectInvoker(function *common.Function) clients.Invoker {
+	if d.AsyncInvoker != nil && common.IsAsyncTrigger(common.GetTrigger(function)) {
+		return d.AsyncInvoker
+	}
+
+	return d.Invoker
+}
+
+// submitsAsynchronously returns true if the invoker only submits invocations, whose completion is collected later
+func (d *Driver) submitsAsynchronously(invoker clients.Invoker) bool {
+	return (d.AsyncInvoker != nil && invoker == d.AsyncInvoker) || (d.collectsAsyncResponses() && invoker == d.Invoker)
+}
+
+// labelRecord sets the fields of the record that identify the invocation
+func labelRecord(record *mc.ExecutionRecord, node *common.Node, metadata *InvocationMetadata) {
+	record.Phase = int(metadata.Phase)
+	record.Instance = fmt.Sprintf("%s%s", node.DAG, record.Instance)
+	record.InvocationID = metadata.InvocationID
+	record.Trigger = common.GetTrigger(node.Function)
+	record.Function = node.Function.Name
+	record.Minute = metadata.Minute
+}
+
+// traceMinute Returns the minute of the trace of the interval with the given index
+func traceMinute(granularity common.TraceGranularity, interval int) int {
+	if granularity == common.SecondGranularity {
+		return interval / 60
+	}
+
+	return interval
+}
+
 func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	defer metadata.AnnounceDoneWG.Done()
 
@@ -120,36 +213,47 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	var record *mc.ExecutionRecord
 	var runtimeSpecifications *common.RuntimeSpecification
 	var branches []*list.List
-	var invocationRetries int
 	for node != nil {
 		function := node.Value.(*common.Node).Function
 		runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.IatIndex]
 
-		success, record = d.Invoker.Invoke(function, runtimeSpecifications)
-
-		if !success && (d.Configuration.LoaderConfiguration.DAGMode && invocationRetries == 0) {
-			log.Debugf("Invocation with for function %s with ID %s failed. Retrying Invocation", function.Name, metadata.InvocationID)
-			invocationRetries += 1
-			continue
-		}
-		record.Phase = int(metadata.Phase)
-		record.Instance = fmt.Sprintf("%s%s", node.Value.(*common.Node).DAG, record.Instance)
-		record.InvocationID = metadata.InvocationID
+		invoker := d.selectInvoker(function)
+		d.liveMetrics.invocationIssued(function.Name)
+		success, record = d.invokeWithRetries(invoker, node.Value.(*common.Node), runtimeSpecifications, metadata)
+		labelRecord(record, node.Value.(*common.Node), metadata)
+		d.liveMetrics.invocationReturned(function.Name, record, success)
 
-		if d.Configuration.DirigentConfiguration != nil &&
-			d.Configuration.DirigentConfiguration.AsyncMode && record.AsyncResponseID != "" {
+		// the outcome of invocations tracked by the completion receiver is counted once their completion arrives
+		tracked := false
+		if d.collectsAsyncResponses() && invoker == d.Invoker && record.AsyncResponseID != "" {
 			record.TimeToSubmitMs = record.ResponseTime
-			d.AsyncRecords.Enqueue(record)
+			if d.completionReceiver != nil {
+				var onOutcome func(bool)
+				if success {
+					tracked = true
+					onOutcome = func(completed bool) { d.countAsyncOutcome(record, completed) }
+				}
+				d.completionReceiver.Track(record, metadata.RecordOutputChannel, onOutcome)
+			} else {
+				d.AsyncRecords.Enqueue(record)
+			}
 		} else {
+			d.liveMetrics.observeResponseTime(function.Name, record)
 			metadata.RecordOutputChannel <- record
 		}
 		atomic.AddInt64(metadata.FunctionsInvoked, 1)
+		if !tracked {
+			d.triggerBreakdown.Add(record.Trigger, success)
+		}
 		if !success {
+			d.failureBreakdown.Add(failureCategory(record), false)
 			log.Errorf("Invocation with for function %s with ID %s failed.", function.Name, metadata.InvocationID)
 			atomic.AddInt64(metadata.FailedCount, 1)
 			break
 		}
-		atomic.AddInt64(metadata.SuccessCount, 1)
+		if !tracked {
+			atomic.AddInt64(metadata.SuccessCount, 1)
+		}
 		branches = node.Value.(*common.Node).Branches
 		for i := 0; i < len(branches); i++ {
 			newMetadataValue := *metadata
@@ -163,6 +267,17 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	}
 }
 
+// countAsyncOutcome Counts the outcome of an invocation tracked by the completion receiver
+func (d *Driver) countAsyncOutcome(record *mc.ExecutionRecord, completed bool) {
+	d.triggerBreakdown.Add(record.Trigger, completed)
+	if completed {
+		atomic.AddInt64(&d.asyncCompleted, 1)
+	} else {
+		d.failureBreakdown.Add(failureCategory(record), false)
+		atomic.AddInt64(&d.asyncFailed, 1)
+	}
+}
+
 func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunctionDone *sync.WaitGroup, addInvocationsToGroup *sync.WaitGroup, totalSuccessful *int64, totalFailed *int64, totalIssued *int64, recordOutputChannel chan *mc.ExecutionRecord) {
 	defer announceFunctionDone.Done()
 
@@ -212,6 +327,7 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		time.Sleep(time.Duration(sleepFor) * time.Microsecond)
 
 		previousIATSum += iat.Microseconds()
+		d.liveMetrics.observeSchedulingLag(function.Name, time.Since(startOfExperiment)-time.Duration(previousIATSum)*time.Microsecond)
 
 		if !d.Configuration.TestMode {
 			waitForInvocations.Add(1)
@@ -220,6 +336,7 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 				Phase:               currentPhase,
 				InvocationID:        composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute),
 				IatIndex:            iatIndex,
+				Minute:              traceMinute(d.Configuration.TraceGranularity, minuteIndex),
 				SuccessCount:        &successfulInvocations,
 				FailedCount:         &failedInvocations,
 				FunctionsInvoked:    &functionsInvoked,
@@ -237,6 +354,8 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 					Phase:        int(currentPhase),
 					InvocationID: invocationID,
 					StartTime:    time.Now().UnixNano(),
+					Function:     function.Name,
+					Minute:       traceMinute(d.Configuration.TraceGranularity, minuteIndex),
 				},
 			}
 			functionsInvoked++
@@ -317,6 +436,7 @@ func (d *Driver) globalTimekeeper(totalTraceDuration int, signalReady *sync.Wait
 	ticker := time.NewTicker(time.Minute)
 	globalTimeCounter := 0
 
+	d.liveMetrics.startMinute(globalTimeCounter, d.Configuration.Functions, d.Configuration.TraceGranularity)
 	signalReady.Done()
 
 	for {
@@ -324,6 +444,7 @@ func (d *Driver) globalTimekeeper(totalTraceDuration int, signalReady *sync.Wait
 
 		log.Debugf("End of minute %d\n", globalTimeCounter)
 		globalTimeCounter++
+		d.liveMetrics.startMinute(globalTimeCounter, d.Configuration.Functions, d.Configuration.TraceGranularity)
 		if globalTimeCounter >= totalTraceDuration {
 			break
 		}
@@ -351,7 +472,8 @@ func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*s
 
 	globalMetricsCollector := make(chan *mc.ExecutionRecord)
 	totalIssuedChannel := make(chan int64)
-	go mc.CreateGlobalMetricsCollector(d.outputFilename("duration"), globalMetricsCollector, auxiliaryProcessBarrier, allRecordsWritten, totalIssuedChannel)
+	executionSink := d.recordSink(mc.ExecutionRecordSchema, "duration", common.OutputFormatCSV)
+	go mc.CreateGlobalMetricsCollector(executionSink, globalMetricsCollector, d.latencySummary, auxiliaryProcessBarrier, allRecordsWritten, totalIssuedChannel)
 
 	traceDurationInMinutes := d.Configuration.TraceDuration
 	go d.globalTimekeeper(traceDurationInMinutes, auxiliaryProcessBarrier)
@@ -372,6 +494,27 @@ func (d *Driver) internalRun() {
 	backgroundProcessesInitializationBarrier, globalMetricsCollector, totalIssuedChannel, scraperFinishCh := d.startBackgroundProcesses(&allRecordsWritten)
 	backgroundProcessesInitializationBarrier.Wait()
 
+	if address := d.Configuration.LoaderConfiguration.MetricsEndpointAddress; address != "" {
+		if err := d.liveMetrics.Start(address); err != nil {
+			log.Fatalf("Failed to start the metrics endpoint - %v", err)
+		}
+		defer d.liveMetrics.Stop()
+	}
+
+	if d.receivesPushedCompletions() {
+		cfg := d.Configuration.LoaderConfiguration
+
+		d.completionReceiver = newCompletionReceiver(
+			cfg.AsyncCallbackAddress,
+			cfg.AsyncCallbackGRPCAddress,
+			time.Duration(cfg.AsyncCompletionTimeoutSeconds)*time.Second,
+		)
+		if err := d.completionReceiver.Start(); err != nil {
+			log.Fatalf("Failed to start the completion receiver - %v", err)
+		}
+		defer d.completionReceiver.Stop()
+	}
+
 	if d.Configuration.LoaderConfiguration.DAGMode {
 		functions := d.Configuration.Functions
 		dagLists := generator.GenerateDAGs(d.Configuration.LoaderConfiguration, functions, false)
@@ -406,10 +549,15 @@ func (d *Driver) internalRun() {
 		}
 	}
 	allIndividualDriversCompleted.Wait()
-	if atomic.LoadInt64(&successfulInvocations)+atomic.LoadInt64(&failedInvocations) != 0 {
+	if atomic.LoadInt64(&invocationsIssued) != 0 {
 		log.Debugf("Waiting for all the invocations record to be written.\n")
 
-		if d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode {
+		if d.completionReceiver != nil {
+			d.completionReceiver.Wait()
+
+			atomic.AddInt64(&successfulInvocations, atomic.LoadInt64(&d.asyncCompleted))
+			atomic.AddInt64(&failedInvocations, atomic.LoadInt64(&d.asyncFailed))
+		} else if d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode {
 			sleepFor := time.Duration(d.Configuration.DirigentConfiguration.AsyncWaitToCollectMin) * time.Minute
 
 			log.Infof("Sleeping for %v...", sleepFor)
@@ -421,6 +569,10 @@ func (d *Driver) internalRun() {
 		scraperFinishCh <- 0 // Ask the scraper to finish metrics collection
 
 		allRecordsWritten.Wait()
+
+		if err := mc.WriteLatencySummary(d.outputMetadataFilename("latency"), d.latencySummary); err != nil {
+			log.Errorf("Failed to write the latency summary - %v", err)
+		}
 	}
 
 	statSuccess := atomic.LoadInt64(&successfulInvocations)
@@ -431,6 +583,11 @@ func (d *Driver) internalRun() {
 	log.Infof("Number of failed invocations: \t%d", statFailed)
 	log.Infof("Total invocations: \t\t\t%d", statSuccess+statFailed)
 	log.Infof("Failure rate: \t\t\t%.2f%%", float64(statFailed)*100.0/float64(statSuccess+statFailed))
+
+	if d.Configuration.LoaderConfiguration.TriggerSemantics {
+		d.triggerBreakdown.Log("Invocations by trigger")
+	}
+	d.failureBreakdown.LogFailures("Failed invocations by class")
 }
 
 func (d *Driver) GenerateSpecification() {
@@ -441,9 +598,15 @@ func (d *Driver) GenerateSpecification() {
 		if d.Configuration.LoaderConfiguration.DAGMode {
 			function.InvocationStats.Invocations = d.Configuration.Functions[0].InvocationStats.Invocations
 		}
+		iatDistribution := d.Configuration.IATDistribution
+		if d.Configuration.LoaderConfiguration.TriggerSemantics && common.GetTrigger(function) == common.TriggerTimer {
+			// timer-triggered functions fire on a schedule rather than following a sampled arrival process
+			iatDistribution = common.Periodic
+		}
+
 		spec := d.SpecificationGenerator.GenerateInvocationData(
 			function,
-			d.Configuration.IATDistribution,
+			iatDistribution,
 			d.Configuration.ShiftIAT,
 			d.Configuration.TraceGranularity,
 		)
@@ -489,21 +652,74 @@ func (d *Driver) ReadOrWriteFileSpecification(writeIATsToFile bool, readIATsFrom
 	}
 }
 
-func (d *Driver) RunExperiment() {
+// prepareDeployment Sets the initial scale and the resource requests of the functions to deploy
+func (d *Driver) prepareDeployment() {
 	if d.Configuration.WithWarmup() {
-		trace.DoStaticTraceProfiling(d.Configuration.Functions)
+		trace.DoStaticTraceProfiling(d.Configuration.Functions, trace.ScaleProfilingConfiguration{
+			Strategy:           d.Configuration.LoaderConfiguration.ScaleProfilingStrategy,
+			WarmupDuration:     d.Configuration.LoaderConfiguration.WarmupDuration,
+			ExperimentDuration: d.Configuration.LoaderConfiguration.ExperimentDuration,
+			Percentile:         d.Configuration.LoaderConfiguration.ScaleProfilingPercentile,
+		})
 	}
 
 	trace.ApplyResourceLimits(d.Configuration.Functions, d.Configuration.LoaderConfiguration.CPULimit)
+}
+
+// RenderDeployment Writes out what would be deployed for the experiment to the directory, instead of deploying it
+func (d *Driver) RenderDeployment(dir string) {
+	d.prepareDeployment()
 
 	deployer := deployment.CreateDeployer(d.Configuration)
-	deployer.Deploy(d.Configuration)
+	if err := deployer.Render(d.Configuration, dir); err != nil {
+		log.Fatalf("Failed to render the deployment - %v", err)
+	}
+
+	log.Infof("Rendered the deployment of %d functions to %s", len(d.Configuration.Functions), dir)
+}
+
+func (d *Driver) RunExperiment() {
+	d.prepareDeployment()
+
+	deployer := d.Deployer
+	if err := d.deploy(deployer); err != nil {
+		deployer.Clean()
+		log.Fatalf("Aborting the experiment - %v", err)
+	}
+
+	if reporter, ok := deployer.(deployment.ScaleReporter); ok {
+		d.scaleReporter = reporter
+	}
+
+	preWarmed := d.preWarm(deployer, preWarmPollInterval)
 
 	go failure.ScheduleFailure(d.Configuration.LoaderConfiguration.Platform, d.Configuration.FailureConfiguration)
 
+	if scheduler, ok := deployer.(deployment.ScaleScheduler); ok && len(preWarmed) > 0 {
+		go d.restorePreWarmScale(scheduler, preWarmed, d.preWarmRestoreDelay())
+	}
+
+	if d.Configuration.LoaderConfiguration.ScaleProfilingStrategy == common.ScaleProfilingSchedule {
+		if scheduler, ok := deployer.(deployment.ScaleScheduler); ok {
+			go d.runPreScalingSchedule(scheduler)
+		} else {
+			log.Warnf("Platform %s does not support pre-scaling. The scaling schedule will not be applied.", d.Configuration.LoaderConfiguration.Platform)
+		}
+	}
+
 	// Generate load
 	d.internalRun()
 
 	// Clean up
+	d.closeInvokers()
 	deployer.Clean()
 }
+
+// closeInvokers Releases the resources the invokers hold across invocations
+func (d *Driver) closeInvokers() {
+	for _, invoker := range []clients.Invoker{d.Invoker, d.AsyncInvoker} {
+		if closer, ok := invoker.(clients.Closer); ok {
+			closer.Close()
+		}
+	}
+}
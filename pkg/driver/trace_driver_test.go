@@ -29,6 +29,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -101,6 +103,8 @@ func createTestDriver(invocationStats []int, vSwarm bool) *Driver {
 		},
 		TestMode: true,
 	})
+	// the experiments run without a cluster to deploy the functions to
+	driver.Deployer = &scriptedDeployer{}
 
 	return driver
 }
@@ -422,7 +426,8 @@ func TestGlobalMetricsCollector(t *testing.T) {
 	collectorReady.Add(1)
 	collectorFinished.Add(1)
 
-	go metric.CreateGlobalMetricsCollector(driver.outputFilename("duration"), inputChannel, collectorReady, collectorFinished, totalIssuedChannel)
+	sink := driver.recordSink(metric.ExecutionRecordSchema, "duration", common.OutputFormatCSV)
+	go metric.CreateGlobalMetricsCollector(sink, inputChannel, driver.latencySummary, collectorReady, collectorFinished, totalIssuedChannel)
 	collectorReady.Wait()
 
 	bogusRecord := &metric.ExecutionRecord{
@@ -465,6 +470,61 @@ func TestGlobalMetricsCollector(t *testing.T) {
 			t.Error("Failed due to unexpected data received.")
 		}
 	}
+
+	// the failed invocations are counted in their minute and in the whole phase
+	summary := driver.latencySummary.Summarize()
+	if len(summary.Groups) != 2 || summary.Groups[0].Failed != 5 || summary.Groups[1].Failed != 5 || summary.Groups[0].ResponseTime.Count != 0 {
+		t.Errorf("Unexpected latency summary %+v", summary.Groups)
+	}
+}
+
+func TestRecordSink(t *testing.T) {
+	tests := []struct {
+		testName      string
+		formats       map[string]string
+		schema        metric.RecordSchema
+		name          string
+		defaultFormat string
+		record        interface{}
+		file          string
+		content       string
+	}{
+		{testName: "default_cluster_usage", schema: metric.ClusterUsageSchema, name: "cluster_usage", defaultFormat: common.OutputFormatJSONLines, record: metric.ClusterUsage{}, file: "test_cluster_usage_1.csv", content: `{"timestamp":0,`},
+		{testName: "default_duration", formats: map[string]string{"ClusterUsage": common.OutputFormatSQLite}, schema: metric.ExecutionRecordSchema, name: "duration", defaultFormat: common.OutputFormatCSV, file: "test_duration_1.csv", content: "phase,"},
+		{testName: "selected_jsonl", formats: map[string]string{"ExecutionRecord": common.OutputFormatJSONLines}, schema: metric.ExecutionRecordSchema, name: "duration", defaultFormat: common.OutputFormatCSV, file: "test_duration_1.jsonl", content: `{"schema":"ExecutionRecord","version":1}`},
+		{testName: "selected_parquet", formats: map[string]string{"KnStats": common.OutputFormatParquet}, schema: metric.KnStatsSchema, name: "kn_stats", defaultFormat: common.OutputFormatCSV, file: "test_kn_stats_1.parquet", content: "PAR1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			directory := t.TempDir()
+			driver := &Driver{Configuration: &config.Configuration{
+				LoaderConfiguration: &config.LoaderConfiguration{
+					OutputPathPrefix: filepath.Join(directory, "test"),
+					OutputFormats:    test.formats,
+				},
+				TraceDuration: 1,
+			}}
+
+			sink := driver.recordSink(test.schema, test.name, test.defaultFormat)
+			if test.record != nil {
+				if err := sink.Write(test.record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(directory, test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), test.content) {
+				t.Errorf("Unexpected content %q", data)
+			}
+		})
+	}
 }
 
 func TestDriverBackgroundProcesses(t *testing.T) {
@@ -769,3 +829,104 @@ func TestRequestedVsIssued(t *testing.T) {
 		t.Error("Unexpected value received.")
 	}
 }
+
+func TestPreScalingUpdates(t *testing.T) {
+	scheduled := &common.Function{Name: "scheduled", ScaleSchedule: []int{1, 1, 3}}
+	unscheduled := &common.Function{Name: "unscheduled"}
+	functions := []*common.Function{scheduled, unscheduled}
+
+	expected := []map[*common.Function]int{
+		{scheduled: 1},
+		{},
+		{scheduled: 3},
+		{},
+		{},
+	}
+
+	for minute, expectedUpdates := range expected {
+		updates, restores := preScalingUpdates(functions, minute)
+		if len(updates) != len(expectedUpdates) {
+			t.Fatalf("Unexpected number of updates in minute %d: %v", minute, updates)
+		}
+
+		for function, minScale := range expectedUpdates {
+			if updates[function] != minScale {
+				t.Errorf("Unexpected minimum scale of %s in minute %d - expected: %d; got: %d", function.Name, minute, minScale, updates[function])
+			}
+		}
+
+		// the minimum scale is restored once the schedule is over
+		if minute == len(scheduled.ScaleSchedule) {
+			if len(restores) != 1 || restores[0] != scheduled {
+				t.Errorf("Unexpected restores in minute %d: %v", minute, restores)
+			}
+		} else if len(restores) != 0 {
+			t.Errorf("Unexpected restores in minute %d: %v", minute, restores)
+		}
+	}
+}
+
+// submittingInvoker Accepts every submission, identifying it by the name of the function
+type submittingInvoker struct{}
+
+func (submittingInvoker) Invoke(function *common.Function, _ *common.RuntimeSpecification) (bool, *metric.ExecutionRecord) {
+	return true, &metric.ExecutionRecord{AsyncResponseID: function.Name + "-submission"}
+}
+
+func TestAsyncOutcomeCounting(t *testing.T) {
+	tests := []struct {
+		testName  string
+		completed bool
+	}{
+		{testName: "completed", completed: true},
+		{testName: "timed_out", completed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			driver := createTestDriver([]int{1}, false)
+			driver.Configuration.LoaderConfiguration.KnativeAsyncMode = true
+			driver.Invoker = submittingInvoker{}
+			driver.completionReceiver = newCompletionReceiver("", "", time.Minute)
+
+			function := driver.Configuration.Functions[0]
+			function.Specification.RuntimeSpecification = []common.RuntimeSpecification{{Runtime: 10, Memory: 10}}
+			functionList := list.New()
+			functionList.PushBack(&common.Node{Function: function})
+
+			var successCount, failedCount, functionsInvoked int64
+			output := make(chan *metric.ExecutionRecord, 1)
+			done := &sync.WaitGroup{}
+			done.Add(1)
+
+			driver.invokeFunction(&InvocationMetadata{
+				RootFunction:        functionList,
+				SuccessCount:        &successCount,
+				FailedCount:         &failedCount,
+				FunctionsInvoked:    &functionsInvoked,
+				RecordOutputChannel: output,
+				AnnounceDoneWG:      done,
+			})
+
+			if successCount != 0 || failedCount != 0 || functionsInvoked != 1 {
+				t.Errorf("Tracked submissions should not be counted before their outcome - successful: %d; failed: %d", successCount, failedCount)
+			}
+
+			if test.completed {
+				driver.completionReceiver.receive(&pushedCompletion{
+					AsyncCompletion: common.AsyncCompletion{InvocationID: function.Name + "-submission"},
+					arrival:         time.Now().UnixMicro(),
+				})
+			} else {
+				driver.completionReceiver.expire(time.Now().Add(2 * time.Minute))
+			}
+			<-output
+
+			if test.completed && (driver.asyncCompleted != 1 || driver.asyncFailed != 0) ||
+				!test.completed && (driver.asyncCompleted != 0 || driver.asyncFailed != 1) {
+
+				t.Errorf("Unexpected outcome - completed: %d; failed: %d", driver.asyncCompleted, driver.asyncFailed)
+			}
+		})
+	}
+}
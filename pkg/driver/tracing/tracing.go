@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterZipkin   = "zipkin"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterOTLPGRPC = "otlp-grpc"
+
+	// defaultZipkinEndpoint Collector used if Zipkin tracing is enabled without an endpoint
+	defaultZipkinEndpoint = "http://localhost:9411/api/v2/spans"
+
+	serviceName = "loader"
+	tracerName  = "github.com/vhive-serverless/loader/pkg/driver/tracing"
+)
+
+// Enabled returns true if the spans of the invocations are exported
+func Enabled(cfg *config.LoaderConfiguration) bool {
+	return cfg.EnableZipkinTracing || cfg.TracingExporter != ""
+}
+
+func createExporter(cfg *config.LoaderConfiguration) (sdktrace.SpanExporter, error) {
+	exporter, endpoint := strings.ToLower(cfg.TracingExporter), cfg.TracingEndpoint
+	if exporter == "" {
+		exporter = ExporterZipkin
+	}
+
+	switch exporter {
+	case ExporterZipkin:
+		if endpoint == "" {
+			endpoint = defaultZipkinEndpoint
+		}
+
+		return zipkin.New(endpoint)
+	case ExporterOTLPHTTP:
+		var options []otlptracehttp.Option
+		if endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+		}
+
+		return otlptracehttp.New(context.Background(), options...)
+	case ExporterOTLPGRPC:
+		var options []otlptracegrpc.Option
+		if endpoint != "" {
+			options = append(options, otlptracegrpc.WithEndpointURL(endpoint))
+		}
+
+		return otlptracegrpc.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter '%s'", cfg.TracingExporter)
+	}
+}
+
+// sampler Samples the given ratio of the invocations, or all of them if the ratio is not set
+func sampler(ratio float64) sdktrace.Sampler {
+	if ratio <= 0 || ratio >= 1 {
+		return sdktrace.AlwaysSample()
+	}
+
+	return sdktrace.TraceIDRatioBased(ratio)
+}
+
+// Init Sets up the exporter of the spans and the W3C trace-context propagation. Returns the function flushing the
+// spans that were not exported yet.
+func Init(cfg *config.LoaderConfiguration) (func(), error) {
+	exporter, err := createExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sampler(cfg.TracingSamplingRatio)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Warnf("Failed to flush the invocation spans - %v", err)
+		}
+	}, nil
+}
+
+// StartInvocation Starts the span of the invocation, and stores its trace ID in the record. If tracing is not set up,
+// the span is a no-op.
+func StartInvocation(ctx context.Context, function *common.Function, runtimeSpec *common.RuntimeSpecification, record *mc.ExecutionRecordBase) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, function.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("faas.invoked_name", function.Name),
+			attribute.Int("loader.requested_runtime_ms", runtimeSpec.Runtime),
+			attribute.Int("loader.requested_memory_mib", runtimeSpec.Memory),
+		),
+	)
+
+	if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+		record.TraceID = spanContext.TraceID().String()
+	}
+
+	return ctx, span
+}
+
+// InjectHTTP Sets the traceparent header of the request to the span in the context
+func InjectHTTP(ctx context.Context, req *http.Request) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// EndInvocation Records the outcome of the invocation in the span and ends it
+func EndInvocation(span trace.Span, record *mc.ExecutionRecordBase) {
+	if record.StatusCode != 0 {
+		span.SetAttributes(attribute.Int("loader.status_code", record.StatusCode))
+	}
+	if record.ErrorClass != "" {
+		span.SetAttributes(attribute.String("error.type", string(record.ErrorClass)))
+		span.SetStatus(codes.Error, string(record.ErrorClass))
+	}
+
+	span.End()
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCreateExporter(t *testing.T) {
+	tests := []struct {
+		testName string
+		exporter string
+		endpoint string
+		valid    bool
+	}{
+		{testName: "zipkin_default", exporter: "", valid: true},
+		{testName: "otlp_http", exporter: ExporterOTLPHTTP, endpoint: "http://localhost:4318/v1/traces", valid: true},
+		{testName: "otlp_grpc", exporter: ExporterOTLPGRPC, endpoint: "http://localhost:4317", valid: true},
+		{testName: "unsupported", exporter: "jaeger", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			exporter, err := createExporter(&config.LoaderConfiguration{
+				TracingExporter: test.exporter,
+				TracingEndpoint: test.endpoint,
+			})
+
+			if (err == nil) != test.valid {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if exporter != nil {
+				_ = exporter.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestInvocationSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	record := &mc.ExecutionRecordBase{}
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost", nil)
+
+	ctx, span := StartInvocation(req.Context(), &common.Function{Name: "f"}, &common.RuntimeSpecification{Runtime: 10}, record)
+	InjectHTTP(ctx, req)
+	record.Fail(mc.ErrorClassOverload, http.StatusServiceUnavailable, "overloaded")
+	EndInvocation(span, record)
+
+	// traceparent: 00-<trace ID>-<span ID>-<flags>
+	if parts := strings.Split(req.Header.Get("traceparent"), "-"); len(parts) != 4 || parts[1] != record.TraceID {
+		t.Errorf("Trace ID %s not propagated in traceparent '%s'", record.TraceID, req.Header.Get("traceparent"))
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].SpanContext().TraceID().String() != record.TraceID || spans[0].Status().Code != codes.Error {
+		t.Errorf("Unexpected spans %v", spans)
+	}
+}
@@ -162,11 +162,51 @@ func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDist
 	return IAT[:len(IAT)-1], perMinuteCount, nonScaledDuration
 }
 
+// generatePeriodicIAT spreads all the invocations at a fixed period over the whole trace, as timer-triggered functions
+// fire on a schedule rather than following a sampled arrival process. The number of minutes is the length of
+// invocationsPerMinute array.
+func (s *SpecificationGenerator) generatePeriodicIAT(invocationsPerMinute []int, granularity common.TraceGranularity) (common.IATArray, []int) {
+	numberOfMinutes := len(invocationsPerMinute)
+	perMinuteCount := make([]int, numberOfMinutes)
+
+	totalInvocations := 0
+	for _, count := range invocationsPerMinute {
+		totalInvocations += count
+	}
+
+	if totalInvocations == 0 {
+		return common.IATArray{}, perMinuteCount
+	}
+
+	timeUnit := getBlankTimeUnit(granularity)
+	period := float64(numberOfMinutes) * timeUnit / float64(totalInvocations)
+
+	iat := make(common.IATArray, totalInvocations)
+	for i := 0; i < totalInvocations; i++ {
+		if i > 0 {
+			iat[i] = period
+		}
+
+		minute := common.MinOf(int(float64(i)*period/timeUnit), numberOfMinutes-1)
+		perMinuteCount[minute]++
+	}
+
+	return iat, perMinuteCount
+}
+
 func (s *SpecificationGenerator) GenerateInvocationData(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) *common.FunctionSpecification {
 	invocationsPerMinute := function.InvocationStats.Invocations
 
 	// Generating IAT
-	iat, perMinuteCount, rawDuration := s.generateIAT(invocationsPerMinute, iatDistribution, shiftIAT, granularity)
+	var iat common.IATArray
+	var perMinuteCount []int
+	var rawDuration common.ProbabilisticDuration
+
+	if iatDistribution == common.Periodic {
+		iat, perMinuteCount = s.generatePeriodicIAT(invocationsPerMinute, granularity)
+	} else {
+		iat, perMinuteCount, rawDuration = s.generateIAT(invocationsPerMinute, iatDistribution, shiftIAT, granularity)
+	}
 
 	// Generating runtime specifications
 	var runtimeArray common.RuntimeSpecificationArray
@@ -836,3 +836,57 @@ func TestGenerateExecutionSpecifications(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratePeriodicIAT(t *testing.T) {
+	tests := []struct {
+		testName               string
+		invocations            []int
+		expectedIAT            common.IATArray
+		expectedPerMinuteCount []int
+	}{
+		{
+			testName:               "no_invocations",
+			invocations:            []int{0, 0},
+			expectedIAT:            common.IATArray{},
+			expectedPerMinuteCount: []int{0, 0},
+		},
+		{
+			testName:               "uneven_minutes",
+			invocations:            []int{2, 0, 4},
+			expectedIAT:            common.IATArray{0, 30_000_000, 30_000_000, 30_000_000, 30_000_000, 30_000_000},
+			expectedPerMinuteCount: []int{2, 2, 2},
+		},
+		{
+			testName:               "single_invocation",
+			invocations:            []int{0, 1, 0, 0},
+			expectedIAT:            common.IATArray{0},
+			expectedPerMinuteCount: []int{1, 0, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			sg := NewSpecificationGenerator(123)
+			testFunction.InvocationStats = &common.FunctionInvocationStats{
+				Trigger:     common.TriggerTimer,
+				Invocations: test.invocations,
+			}
+
+			spec := sg.GenerateInvocationData(&testFunction, common.Periodic, false, common.MinuteGranularity)
+
+			if len(spec.IAT) != len(test.expectedIAT) || len(spec.RuntimeSpecification) != len(test.expectedIAT) {
+				t.Fatalf("wrong number of IATs, got: %d, expected: %d", len(spec.IAT), len(test.expectedIAT))
+			}
+			for i := 0; i < len(test.expectedIAT); i++ {
+				if math.Abs(spec.IAT[i]-test.expectedIAT[i]) > 10e-3 {
+					t.Errorf("wrong IAT at index %d, got: %f, expected: %f", i, spec.IAT[i], test.expectedIAT[i])
+				}
+			}
+			for i := 0; i < len(test.expectedPerMinuteCount); i++ {
+				if spec.PerMinuteCount[i] != test.expectedPerMinuteCount[i] {
+					t.Errorf("wrong count for minute %d, got: %d, expected: %d", i, spec.PerMinuteCount[i], test.expectedPerMinuteCount[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SynthesisSpecification Declarative specification of a group of identical functions in a synthetic trace. Each of
+// the functions is invoked with an RPS ramping from BeginningRPS to TargetRPS in steps of StepRPS, where each step
+// lasts StepDurationMinutes. The trace is padded with zeros up to DurationMinutes.
+type SynthesisSpecification struct {
+	Functions int    `json:"Functions"`
+	Trigger   string `json:"Trigger"`
+
+	BeginningRPS        float64 `json:"BeginningRPS"`
+	TargetRPS           float64 `json:"TargetRPS"`
+	StepRPS             float64 `json:"StepRPS"`
+	StepDurationMinutes int     `json:"StepDurationMinutes"`
+	DurationMinutes     int     `json:"DurationMinutes"`
+
+	RuntimeMs int `json:"RuntimeMs"`
+	MemoryMB  int `json:"MemoryMB"`
+
+	// used only for dirigent.json
+	DirigentImage       string `json:"DirigentImage"`
+	IterationMultiplier int    `json:"IterationMultiplier"`
+}
+
+func ReadSynthesisSpecifications(path string) ([]SynthesisSpecification, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var specifications []SynthesisSpecification
+	if err = json.Unmarshal(data, &specifications); err != nil {
+		return nil, err
+	}
+
+	return specifications, nil
+}
+
+// rampRPS Returns the RPS of each step of the ramp, including both BeginningRPS and TargetRPS
+func (s *SynthesisSpecification) rampRPS() []float64 {
+	if s.StepRPS == 0 || s.BeginningRPS == s.TargetRPS {
+		return []float64{s.BeginningRPS}
+	}
+
+	step := math.Abs(s.StepRPS)
+	if s.TargetRPS < s.BeginningRPS {
+		step = -step
+	}
+
+	steps := int(math.Floor((s.TargetRPS-s.BeginningRPS)/step+1e-9)) + 1
+
+	result := make([]float64, steps)
+	for i := 0; i < steps; i++ {
+		result[i] = s.BeginningRPS + float64(i)*step
+	}
+
+	return result
+}
+
+// invocationsInMinute Number of invocations the RPS generator issues in one minute for the given RPS
+func invocationsInMinute(rps float64) int {
+	if rps <= 0 {
+		return 0
+	}
+
+	return countNumberOfInvocationsPerMinute(1, generateFunctionByRPS(1, rps))[0]
+}
+
+// SynthesizeInvocations Returns the number of invocations in each minute for a single function of the specification
+func (s *SynthesisSpecification) SynthesizeInvocations() []int {
+	stepDuration := common.MaxOf(s.StepDurationMinutes, 1)
+
+	var result []int
+	for _, rps := range s.rampRPS() {
+		count := invocationsInMinute(rps)
+		for i := 0; i < stepDuration; i++ {
+			result = append(result, count)
+		}
+	}
+
+	for len(result) < s.DurationMinutes {
+		result = append(result, 0)
+	}
+
+	return result
+}
+
+func randomHash(gen *rand.Rand) string {
+	hash := make([]byte, 32)
+	gen.Read(hash)
+
+	return hex.EncodeToString(hash)
+}
+
+// SynthesizeFunctions Creates the functions described by the specifications, with random owner, application, and
+// function hashes, in the same form the trace parsers produce them.
+func SynthesizeFunctions(specifications []SynthesisSpecification, seed int64) []*common.Function {
+	gen := rand.New(rand.NewSource(seed))
+
+	var result []*common.Function
+	for _, spec := range specifications {
+		invocations := spec.SynthesizeInvocations()
+		trigger := spec.Trigger
+		if trigger == "" {
+			trigger = common.TriggerHTTP
+		}
+
+		for i := 0; i < spec.Functions; i++ {
+			hashOwner, hashApp, hashFunction := randomHash(gen), randomHash(gen), randomHash(gen)
+			runtime, memory := float64(spec.RuntimeMs), float64(spec.MemoryMB)
+
+			function := &common.Function{
+				Name: fmt.Sprintf("%s-%d", common.FunctionNamePrefix, len(result)),
+
+				InvocationStats: &common.FunctionInvocationStats{
+					HashOwner:    hashOwner,
+					HashApp:      hashApp,
+					HashFunction: hashFunction,
+					Trigger:      trigger,
+					Invocations:  append([]int{}, invocations...),
+				},
+				// count needs to be > 0 for the specification generator to pick runtime and memory
+				RuntimeStats: &common.FunctionRuntimeStats{
+					HashOwner:     hashOwner,
+					HashApp:       hashApp,
+					HashFunction:  hashFunction,
+					Average:       runtime,
+					Count:         1,
+					Minimum:       runtime,
+					Maximum:       runtime,
+					Percentile0:   runtime,
+					Percentile1:   runtime,
+					Percentile25:  runtime,
+					Percentile50:  runtime,
+					Percentile75:  runtime,
+					Percentile99:  runtime,
+					Percentile100: runtime,
+				},
+				MemoryStats: &common.FunctionMemoryStats{
+					HashOwner:     hashOwner,
+					HashApp:       hashApp,
+					HashFunction:  hashFunction,
+					Count:         1,
+					Average:       memory,
+					Percentile1:   memory,
+					Percentile5:   memory,
+					Percentile25:  memory,
+					Percentile50:  memory,
+					Percentile75:  memory,
+					Percentile95:  memory,
+					Percentile99:  memory,
+					Percentile100: memory,
+				},
+				ColdStartBusyLoopMs: ComputeBusyLoopPeriod(spec.MemoryMB),
+			}
+
+			if spec.DirigentImage != "" {
+				function.DirigentMetadata = &common.DirigentMetadata{
+					HashFunction:        hashFunction,
+					Image:               spec.DirigentImage,
+					Port:                80,
+					Protocol:            "tcp",
+					ScalingUpperBound:   1024,
+					ScalingLowerBound:   0,
+					IterationMultiplier: spec.IterationMultiplier,
+					IOPercentage:        0,
+				}
+			}
+
+			result = append(result, function)
+		}
+	}
+
+	return result
+}
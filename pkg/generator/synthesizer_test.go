@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestSynthesizeInvocations(t *testing.T) {
+	tests := []struct {
+		testName      string
+		specification SynthesisSpecification
+		expected      []int
+	}{
+		{
+			testName:      "constant_rps",
+			specification: SynthesisSpecification{BeginningRPS: 1, TargetRPS: 1, StepDurationMinutes: 2},
+			expected:      []int{60, 60},
+		},
+		{
+			testName:      "ascending_ramp",
+			specification: SynthesisSpecification{BeginningRPS: 0, TargetRPS: 2, StepRPS: 1, StepDurationMinutes: 1},
+			expected:      []int{0, 60, 120},
+		},
+		{
+			testName:      "descending_ramp",
+			specification: SynthesisSpecification{BeginningRPS: 2, TargetRPS: 1, StepRPS: 1, StepDurationMinutes: 2},
+			expected:      []int{120, 120, 60, 60},
+		},
+		{
+			testName:      "padded",
+			specification: SynthesisSpecification{BeginningRPS: 0.5, TargetRPS: 0.5, StepDurationMinutes: 1, DurationMinutes: 3},
+			expected:      []int{30, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			got := test.specification.SynthesizeInvocations()
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("Unexpected invocations - expected: %v; got: %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestSynthesizeFunctions(t *testing.T) {
+	specifications := []SynthesisSpecification{
+		{Functions: 2, BeginningRPS: 1, TargetRPS: 1, RuntimeMs: 100, MemoryMB: 256},
+		{Functions: 1, BeginningRPS: 1, TargetRPS: 1, RuntimeMs: 50, MemoryMB: 128, Trigger: common.TriggerTimer, DirigentImage: "image"},
+	}
+
+	functions := SynthesizeFunctions(specifications, 42)
+	if len(functions) != 3 {
+		t.Fatalf("Unexpected number of functions %d.", len(functions))
+	}
+
+	if functions[0].InvocationStats.HashFunction == functions[1].InvocationStats.HashFunction {
+		t.Error("Synthetic functions should have distinct hashes.")
+	}
+	if functions[0].InvocationStats.Trigger != common.TriggerHTTP || functions[2].InvocationStats.Trigger != common.TriggerTimer {
+		t.Error("Unexpected trigger of synthetic functions.")
+	}
+	if functions[0].RuntimeStats.Percentile50 != 100 || functions[2].MemoryStats.Percentile100 != 128 {
+		t.Error("Unexpected runtime or memory of synthetic functions.")
+	}
+	if functions[0].DirigentMetadata != nil || functions[2].DirigentMetadata == nil ||
+		functions[2].DirigentMetadata.HashFunction != functions[2].InvocationStats.HashFunction {
+
+		t.Error("Unexpected Dirigent metadata of synthetic functions.")
+	}
+
+	again := SynthesizeFunctions(specifications, 42)
+	if again[0].InvocationStats.HashFunction != functions[0].InvocationStats.HashFunction {
+		t.Error("Synthesis should be reproducible for the same seed.")
+	}
+}
@@ -0,0 +1,271 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// activeNodeCpuPct CPU utilization above which a worker node counts as active
+	activeNodeCpuPct = 5
+	// functionContainer Name of the container of a Knative service running the function
+	functionContainer = "user-container"
+)
+
+var (
+	nodeResource        = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	podMetricsResource  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+	controlPlaneLabels = []string{"node-role.kubernetes.io/control-plane", "node-role.kubernetes.io/master"}
+)
+
+// processUsage CPU time of the loader at the previous scrape
+type processUsage struct {
+	cpuTime time.Duration
+	time    time.Time
+}
+
+// ClusterUsage Returns the resource usage of the nodes and of the function containers from the Kubernetes metrics API,
+// the resources requested and limited on each node from Prometheus, and the usage of the loader itself. The control
+// plane node is reported separately from the workers. The usage is returned as far as it could be scraped.
+func (s *Scraper) ClusterUsage() (ClusterUsage, error) {
+	result := ClusterUsage{}
+	result.LoaderCpu, result.LoaderMem = s.scrapeLoaderUsage()
+
+	if s.client == nil {
+		return result, errors.New("no Kubernetes client to scrape the usage of the nodes")
+	}
+
+	nodes, err := s.client.Resource(nodeResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list the nodes - %w", err)
+	}
+	nodeMetrics, err := s.client.Resource(nodeMetricsResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to read the metrics of the nodes - %w", err)
+	}
+
+	var errs []error
+	byNode := func(query string) map[string]float64 {
+		values, err := s.prometheus.byLabel(query, "node")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return values
+	}
+	running := "and on(container, pod) (kube_pod_container_status_running==1) or on(node) (kube_node_info*0)"
+	memoryRequests := byNode(`sum(kube_pod_container_resource_requests{resource="memory"} ` + running + `) by (node)`)
+	memoryLimits := byNode(`sum(kube_pod_container_resource_limits{resource="memory"} ` + running + `) by (node)`)
+	cpuRequests := byNode(`sum(kube_pod_container_resource_requests{resource="cpu"} ` + running + `) by (node)`)
+	cpuLimits := byNode(`sum(kube_pod_container_resource_limits{resource="cpu"} ` + running + `) by (node)`)
+	pods := byNode("count(kube_pod_info and on(pod) max(kube_pod_container_status_running==1) by (pod)) by(node)")
+
+	usage := make(map[string]map[string]interface{})
+	for _, item := range nodeMetrics.Items {
+		usage[item.GetName()], _, _ = unstructured.NestedMap(item.Object, "usage")
+	}
+
+	items := nodes.Items
+	sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+	master := controlPlaneNode(items)
+
+	var cpuPcts, memoryPcts []float64
+	for _, node := range items {
+		name := node.GetName()
+		cpu, _ := usage[name]["cpu"].(string)
+		memory, _ := usage[name]["memory"].(string)
+		allocatableCpu, _, _ := unstructured.NestedString(node.Object, "status", "allocatable", "cpu")
+		allocatableMemory, _, _ := unstructured.NestedString(node.Object, "status", "allocatable", "memory")
+		cpuPct := quantityPct(cpu, allocatableCpu)
+		memoryPct := quantityPct(memory, allocatableMemory)
+
+		if name == master {
+			result.MasterCpuPct, result.MasterMemoryPct = cpuPct, memoryPct
+			result.MasterCpuReq, result.MasterCpuLim = cpuRequests[name], cpuLimits[name]
+			result.MasterMemoryReq, result.MasterMemoryLim = memoryRequests[name], memoryLimits[name]
+			result.MasterPods = int(pods[name])
+			continue
+		}
+
+		result.Cpu = append(result.Cpu, cpu)
+		result.CpuReq = append(result.CpuReq, cpuRequests[name])
+		result.CpuLim = append(result.CpuLim, cpuLimits[name])
+		result.Memory = append(result.Memory, memory)
+		result.MemoryReq = append(result.MemoryReq, memoryRequests[name])
+		result.MemoryLim = append(result.MemoryLim, memoryLimits[name])
+		result.Pods = append(result.Pods, int(pods[name]))
+		cpuPcts = append(cpuPcts, cpuPct)
+		memoryPcts = append(memoryPcts, memoryPct)
+	}
+
+	if len(cpuPcts) > 0 {
+		var activeNodes int
+		var activeCpu, activeMemory float64
+		for i, cpuPct := range cpuPcts {
+			result.CpuPctAvg += cpuPct / float64(len(cpuPcts))
+			result.CpuPctMax = max(result.CpuPctMax, cpuPct)
+			if cpuPct >= activeNodeCpuPct {
+				activeNodes++
+				activeCpu += cpuPct
+				activeMemory += memoryPcts[i]
+			}
+		}
+
+		activeNodes = max(activeNodes, 1)
+		result.CpuPctActiveAvg = activeCpu / float64(activeNodes)
+		result.MemoryPctAvg = activeMemory / float64(activeNodes)
+	}
+
+	podMetrics, err := s.client.Resource(podMetricsResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to read the metrics of the pods - %w", err))
+	} else {
+		for _, pod := range podMetrics.Items {
+			containers, _, _ := unstructured.NestedSlice(pod.Object, "containers")
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok || container["name"] != functionContainer {
+					continue
+				}
+
+				cpu, _, _ := unstructured.NestedString(container, "usage", "cpu")
+				memory, _, _ := unstructured.NestedString(container, "usage", "memory")
+				result.PodCpu = append(result.PodCpu, cpu)
+				result.PodMemory = append(result.PodMemory, memory)
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// controlPlaneNode Returns the name of the node labeled as the control plane, or of the first node if none is
+func controlPlaneNode(nodes []unstructured.Unstructured) string {
+	for _, node := range nodes {
+		for _, label := range controlPlaneLabels {
+			if _, ok := node.GetLabels()[label]; ok {
+				return node.GetName()
+			}
+		}
+	}
+
+	if len(nodes) == 0 {
+		return ""
+	}
+	return nodes[0].GetName()
+}
+
+// quantityPct Returns the usage as a percentage of the capacity, both given as resource quantities
+func quantityPct(usage string, capacity string) float64 {
+	used, err := resource.ParseQuantity(usage)
+	if err != nil {
+		return 0
+	}
+	total, err := resource.ParseQuantity(capacity)
+	if err != nil || total.IsZero() {
+		return 0
+	}
+
+	return used.AsApproximateFloat64() / total.AsApproximateFloat64() * 100
+}
+
+// scrapeLoaderUsage Returns the CPU utilization of the loader since the previous scrape and the share of the memory
+// of the host it uses, both in percent
+func (s *Scraper) scrapeLoaderUsage() (float64, float64) {
+	var cpuPct, memoryPct float64
+
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err == nil {
+		cpuTime := time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+		now := time.Now()
+
+		s.loaderUsageLock.Lock()
+		if !s.loaderUsage.time.IsZero() && now.After(s.loaderUsage.time) {
+			cpuPct = float64(cpuTime-s.loaderUsage.cpuTime) / float64(now.Sub(s.loaderUsage.time)) * 100
+		}
+		s.loaderUsage = processUsage{cpuTime: cpuTime, time: now}
+		s.loaderUsageLock.Unlock()
+	}
+
+	if resident, err := residentMemory(); err == nil {
+		if total, err := totalMemory(); err == nil && total > 0 {
+			memoryPct = float64(resident) / float64(total) * 100
+		}
+	}
+
+	return cpuPct, memoryPct
+}
+
+// residentMemory Returns the resident memory of the loader in bytes
+func residentMemory() (int64, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed /proc/self/statm")
+	}
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+
+	return pages * int64(os.Getpagesize()), err
+}
+
+// totalMemory Returns the memory of the host in bytes
+func totalMemory() (int64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			return kb * 1024, err
+		}
+	}
+
+	return 0, fmt.Errorf("no MemTotal in /proc/meminfo")
+}
@@ -0,0 +1,366 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ExpositionContentType Content type of the Prometheus text exposition format, which OpenMetrics scrapers accept too
+const ExpositionContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelSeparator Separates the label values in the key of a series, as it cannot appear in a valid UTF-8 value
+const labelSeparator = "\xff"
+
+// Registry Metric families exposed in the Prometheus text exposition format, in the order they were registered
+type Registry struct {
+	mutex    sync.Mutex
+	families []metricFamily
+}
+
+type metricFamily interface {
+	write(w *bufio.Writer)
+}
+
+// vector Series of a metric family by the values of its labels
+type vector[T any] struct {
+	name   string
+	help   string
+	labels []string
+
+	mutex  sync.Mutex
+	series map[string]*T
+	values map[string][]string
+	create func() *T
+}
+
+// CounterVec Monotonically increasing values by their labels
+type CounterVec struct {
+	vector[float64]
+}
+
+// GaugeVec Values that go up and down by their labels
+type GaugeVec struct {
+	vector[float64]
+}
+
+// HistogramVec Distributions of the observed values in cumulative buckets by their labels
+type HistogramVec struct {
+	vector[histogram]
+	buckets []float64
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// ExponentialBuckets Returns count upper bounds starting at start, each factor times the previous one
+func ExponentialBuckets(start float64, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+
+	return buckets
+}
+
+func newVector[T any](name string, help string, labels []string, create func() *T) vector[T] {
+	return vector[T]{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*T),
+		values: make(map[string][]string),
+		create: create,
+	}
+}
+
+func (r *Registry) register(family metricFamily) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.families = append(r.families, family)
+}
+
+func (r *Registry) NewCounter(name string, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{newVector(name, help, labels, func() *float64 { return new(float64) })}
+	r.register(counter)
+
+	return counter
+}
+
+func (r *Registry) NewGauge(name string, help string, labels ...string) *GaugeVec {
+	gauge := &GaugeVec{newVector(name, help, labels, func() *float64 { return new(float64) })}
+	r.register(gauge)
+
+	return gauge
+}
+
+// NewHistogram Registers a histogram with the given ascending upper bounds, to which the +Inf bucket is added
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	histogramVec := &HistogramVec{buckets: buckets}
+	histogramVec.vector = newVector(name, help, labels, func() *histogram {
+		return &histogram{counts: make([]uint64, len(buckets))}
+	})
+	r.register(histogramVec)
+
+	return histogramVec
+}
+
+// with Returns the series of the label values, creating it if needed. The caller holds the mutex.
+func (v *vector[T]) with(labelValues []string) *T {
+	if len(labelValues) != len(v.labels) {
+		log.Fatalf("Metric %s has %d labels, but %d values were given.", v.name, len(v.labels), len(labelValues))
+	}
+
+	key := strings.Join(labelValues, labelSeparator)
+	series, ok := v.series[key]
+	if !ok {
+		series = v.create()
+		v.series[key] = series
+		v.values[key] = append([]string{}, labelValues...)
+	}
+
+	return series
+}
+
+// sortedKeys Returns the keys of the series in a stable order. The caller holds the mutex.
+func (v *vector[T]) sortedKeys() []string {
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (v *vector[T]) writeHeader(w *bufio.Writer, metricType string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", v.name, metricType)
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add Increases the counter by the value, which must not be negative
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		log.Warnf("Ignoring the decrease of counter %s by %f.", c.name, value)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	*c.with(labelValues) += value
+}
+
+func (c *CounterVec) Get(labelValues ...string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return *c.with(labelValues)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.writeHeader(w, "counter")
+	for _, key := range c.sortedKeys() {
+		writeSample(w, c.name, c.labels, c.values[key], "", "", *c.series[key])
+	}
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	*g.with(labelValues) = value
+}
+
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	*g.with(labelValues) += value
+}
+
+func (g *GaugeVec) Get(labelValues ...string) float64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return *g.with(labelValues)
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.writeHeader(w, "gauge")
+	for _, key := range g.sortedKeys() {
+		writeSample(w, g.name, g.labels, g.values[key], "", "", *g.series[key])
+	}
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	series := h.with(labelValues)
+	// the counts are kept per bucket, and made cumulative when written
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += value
+}
+
+// Count Returns the number of observed values
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.with(labelValues).count
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.writeHeader(w, "histogram")
+	for _, key := range h.sortedKeys() {
+		series, values := h.series[key], h.values[key]
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			writeSample(w, h.name+"_bucket", h.labels, values, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, values, "le", "+Inf", float64(series.count))
+		writeSample(w, h.name+"_sum", h.labels, values, "", "", series.sum)
+		writeSample(w, h.name+"_count", h.labels, values, "", "", float64(series.count))
+	}
+}
+
+// writeSample Writes a sample line, with the extra label appended to the labels of the series if set
+func writeSample(w *bufio.Writer, name string, labels []string, values []string, extraLabel string, extraValue string, value float64) {
+	_, _ = w.WriteString(name)
+
+	if len(labels) > 0 || extraLabel != "" {
+		_ = w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabelValue(values[i]))
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", extraLabel, extraValue)
+		}
+		_ = w.WriteByte('}')
+	}
+
+	_ = w.WriteByte(' ')
+	_, _ = w.WriteString(formatFloat(value))
+	_ = w.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// Write Writes all metric families in the text exposition format
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.Lock()
+	families := append([]metricFamily{}, r.families...)
+	r.mutex.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, family := range families {
+		family.write(buffered)
+	}
+
+	return buffered.Flush()
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", ExpositionContentType)
+	if err := r.Write(w); err != nil {
+		log.Debugf("Failed to write the metrics - %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package metric
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("test_total", "Counted\nevents.", "function", "class")
+	counter.Inc("f1", "timeout")
+	counter.Add(2, "f0", `quoted "\ value`)
+	counter.Add(-1, "f0", `quoted "\ value`)
+
+	gauge := registry.NewGauge("test_gauge", "Current value.")
+	gauge.Set(3)
+	gauge.Add(-0.5)
+
+	histogram := registry.NewHistogram("test_seconds", "Observed values.", []float64{1, 0.1}, "function")
+	for _, value := range []float64{0.05, 0.1, 0.5, 2} {
+		histogram.Observe(value, "f0")
+	}
+
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != ExpositionContentType {
+		t.Errorf("Unexpected content type %s", resp.Header.Get("Content-Type"))
+	}
+
+	var body strings.Builder
+	if err = registry.Write(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`# HELP test_total Counted\nevents.`,
+		`# TYPE test_total counter`,
+		`test_total{function="f0",class="quoted \"\\ value"} 2`,
+		`test_total{function="f1",class="timeout"} 1`,
+		`# HELP test_gauge Current value.`,
+		`# TYPE test_gauge gauge`,
+		`test_gauge 2.5`,
+		`# HELP test_seconds Observed values.`,
+		`# TYPE test_seconds histogram`,
+		`test_seconds_bucket{function="f0",le="0.1"} 2`,
+		`test_seconds_bucket{function="f0",le="1"} 3`,
+		`test_seconds_bucket{function="f0",le="+Inf"} 4`,
+		`test_seconds_sum{function="f0"} 2.65`,
+		`test_seconds_count{function="f0"} 4`,
+	}
+	if lines := strings.Split(strings.TrimSpace(body.String()), "\n"); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Unexpected exposition\n%s", body.String())
+	}
+
+	if counter.Get("f0", `quoted "\ value`) != 2 || gauge.Get() != 2.5 || histogram.Count("f0") != 4 {
+		t.Error("Unexpected values of the metrics.")
+	}
+}
+
+func TestExponentialBuckets(t *testing.T) {
+	if buckets := ExponentialBuckets(0.5, 2, 4); !reflect.DeepEqual(buckets, []float64{0.5, 1, 2, 4}) {
+		t.Errorf("Unexpected buckets %v", buckets)
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// DefaultSignificantFigures Decimal digits to which the HDR histograms preserve the recorded values
+const DefaultSignificantFigures = 3
+
+// HdrHistogram High dynamic range histogram of non-negative integer values, which preserves any recorded value to the
+// given number of significant figures. Values are counted in buckets whose width doubles with each power of two, each
+// split into the same number of sub-buckets. Only the non-empty buckets are kept, so the histogram stays compact
+// regardless of the range of the values. Histograms with the same precision can be merged.
+type HdrHistogram struct {
+	significantFigures int
+	// subBucketHalfCountMagnitude log2 of half the number of sub-buckets per bucket
+	subBucketHalfCountMagnitude int
+	subBucketMask               int64
+
+	counts     map[int]int64
+	totalCount int64
+	min        int64
+	max        int64
+}
+
+// hdrHistogramJSON Serialized histogram, whose counts are flattened pairs of bucket index and count
+type hdrHistogramJSON struct {
+	SignificantFigures int     `json:"significantFigures"`
+	Count              int64   `json:"count"`
+	Min                int64   `json:"min"`
+	Max                int64   `json:"max"`
+	Counts             []int64 `json:"counts"`
+}
+
+func NewHdrHistogram(significantFigures int) *HdrHistogram {
+	if significantFigures < 1 || significantFigures > 5 {
+		significantFigures = DefaultSignificantFigures
+	}
+
+	// the sub-buckets must resolve a unit in the largest value of the given precision, e.g., 2000 for 3 figures
+	largestSingleUnitResolution := 2 * int64(math.Pow10(significantFigures))
+	subBucketCountMagnitude := bits.Len64(uint64(largestSingleUnitResolution - 1))
+
+	return &HdrHistogram{
+		significantFigures:          significantFigures,
+		subBucketHalfCountMagnitude: subBucketCountMagnitude - 1,
+		subBucketMask:               int64(1)<<subBucketCountMagnitude - 1,
+		counts:                      make(map[int]int64),
+	}
+}
+
+// countsIndex Returns the index of the sub-bucket counting the value
+func (h *HdrHistogram) countsIndex(value int64) int {
+	bucket := bits.Len64(uint64(value|h.subBucketMask)) - (h.subBucketHalfCountMagnitude + 1)
+	subBucket := int(value >> bucket)
+
+	return bucket<<h.subBucketHalfCountMagnitude + subBucket
+}
+
+// valueRange Returns the lowest and the highest value counted in the sub-bucket
+func (h *HdrHistogram) valueRange(index int) (int64, int64) {
+	halfCount := 1 << h.subBucketHalfCountMagnitude
+
+	bucket, subBucket := 0, index
+	if index >= 2*halfCount {
+		bucket = index>>h.subBucketHalfCountMagnitude - 1
+		subBucket = index - bucket<<h.subBucketHalfCountMagnitude
+	}
+
+	lowest := int64(subBucket) << bucket
+	return lowest, lowest + int64(1)<<bucket - 1
+}
+
+// Record Counts the value, where negative values are counted as zero
+func (h *HdrHistogram) Record(value int64) {
+	h.RecordN(value, 1)
+}
+
+func (h *HdrHistogram) RecordN(value int64, count int64) {
+	if count <= 0 {
+		return
+	}
+	value = max(value, 0)
+
+	h.counts[h.countsIndex(value)] += count
+	if h.totalCount == 0 || value < h.min {
+		h.min = value
+	}
+	if h.totalCount == 0 || value > h.max {
+		h.max = value
+	}
+	h.totalCount += count
+}
+
+// Merge Adds the counts of the other histogram, which must have the same precision
+func (h *HdrHistogram) Merge(other *HdrHistogram) error {
+	if other.significantFigures != h.significantFigures {
+		return fmt.Errorf("cannot merge a histogram of %d significant figures into one of %d",
+			other.significantFigures, h.significantFigures)
+	}
+	if other.totalCount == 0 {
+		return nil
+	}
+
+	for index, count := range other.counts {
+		h.counts[index] += count
+	}
+	if h.totalCount == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if h.totalCount == 0 || other.max > h.max {
+		h.max = other.max
+	}
+	h.totalCount += other.totalCount
+
+	return nil
+}
+
+func (h *HdrHistogram) TotalCount() int64 {
+	return h.totalCount
+}
+
+func (h *HdrHistogram) Min() int64 {
+	return h.min
+}
+
+func (h *HdrHistogram) Max() int64 {
+	return h.max
+}
+
+// sortedIndices Returns the indices of the non-empty sub-buckets in ascending order of their values
+func (h *HdrHistogram) sortedIndices() []int {
+	indices := make([]int, 0, len(h.counts))
+	for index := range h.counts {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	return indices
+}
+
+// ValueAtPercentile Returns the highest value equivalent to the one below which the given percentage of the recorded
+// values fall, capped by the maximum. Returns 0 if no value was recorded.
+func (h *HdrHistogram) ValueAtPercentile(percentile float64) int64 {
+	if h.totalCount == 0 {
+		return 0
+	}
+
+	percentile = math.Min(math.Max(percentile, 0), 100)
+	target := max(int64(math.Ceil(percentile/100*float64(h.totalCount))), 1)
+
+	var cumulative int64
+	for _, index := range h.sortedIndices() {
+		cumulative += h.counts[index]
+		if cumulative >= target {
+			_, highest := h.valueRange(index)
+			return min(highest, h.max)
+		}
+	}
+
+	return h.max
+}
+
+func (h *HdrHistogram) MarshalJSON() ([]byte, error) {
+	serialized := hdrHistogramJSON{
+		SignificantFigures: h.significantFigures,
+		Count:              h.totalCount,
+		Min:                h.min,
+		Max:                h.max,
+		Counts:             make([]int64, 0, 2*len(h.counts)),
+	}
+	for _, index := range h.sortedIndices() {
+		serialized.Counts = append(serialized.Counts, int64(index), h.counts[index])
+	}
+
+	return json.Marshal(serialized)
+}
+
+func (h *HdrHistogram) UnmarshalJSON(data []byte) error {
+	var serialized hdrHistogramJSON
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return err
+	}
+	if len(serialized.Counts)%2 != 0 {
+		return fmt.Errorf("odd number of values in the counts of a histogram")
+	}
+
+	*h = *NewHdrHistogram(serialized.SignificantFigures)
+
+	var total int64
+	for i := 0; i < len(serialized.Counts); i += 2 {
+		if serialized.Counts[i] < 0 || serialized.Counts[i+1] < 0 {
+			return fmt.Errorf("negative bucket index or count in a histogram")
+		}
+		h.counts[int(serialized.Counts[i])] += serialized.Counts[i+1]
+		total += serialized.Counts[i+1]
+	}
+	if total != serialized.Count {
+		return fmt.Errorf("histogram counts sum up to %d instead of %d", total, serialized.Count)
+	}
+
+	h.totalCount, h.min, h.max = serialized.Count, serialized.Min, serialized.Max
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package metric
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestHdrHistogramPrecision(t *testing.T) {
+	histogram := NewHdrHistogram(3)
+
+	for _, value := range []int64{0, 1, 2047, 2048, 2049, 123456, 987654321, math.MaxInt64 / 2} {
+		lowest, highest := histogram.valueRange(histogram.countsIndex(value))
+		if value < lowest || value > highest {
+			t.Errorf("Value %d counted in the bucket [%d, %d]", value, lowest, highest)
+		}
+		if float64(highest-lowest) > float64(value)/1000 {
+			t.Errorf("Value %d counted in the bucket [%d, %d] wider than three significant figures", value, lowest, highest)
+		}
+	}
+}
+
+func TestHdrHistogramPercentiles(t *testing.T) {
+	histogram := NewHdrHistogram(3)
+
+	generator := rand.New(rand.NewSource(42))
+	values := make([]int64, 100_000)
+	for i := range values {
+		values[i] = int64(generator.ExpFloat64() * 50_000)
+		histogram.Record(values[i])
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	tests := []struct {
+		testName   string
+		percentile float64
+	}{
+		{testName: "p50", percentile: 50},
+		{testName: "p90", percentile: 90},
+		{testName: "p99", percentile: 99},
+		{testName: "p99.9", percentile: 99.9},
+		{testName: "max", percentile: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			exact := values[int(math.Ceil(test.percentile/100*float64(len(values))))-1]
+			if approximate := histogram.ValueAtPercentile(test.percentile); math.Abs(float64(approximate-exact)) > float64(exact)/1000+1 {
+				t.Errorf("Value %d at percentile %.1f instead of %d", approximate, test.percentile, exact)
+			}
+		})
+	}
+
+	if histogram.TotalCount() != int64(len(values)) || histogram.Min() != values[0] || histogram.Max() != values[len(values)-1] {
+		t.Errorf("Unexpected count %d, minimum %d, or maximum %d", histogram.TotalCount(), histogram.Min(), histogram.Max())
+	}
+	if NewHdrHistogram(3).ValueAtPercentile(50) != 0 {
+		t.Error("Percentile of an empty histogram should be zero.")
+	}
+}
+
+func TestHdrHistogramMerge(t *testing.T) {
+	merged, first, second := NewHdrHistogram(3), NewHdrHistogram(3), NewHdrHistogram(3)
+	for value := int64(1); value <= 1000; value++ {
+		merged.Record(value * 10)
+		if value%2 == 0 {
+			first.Record(value * 10)
+		} else {
+			second.Record(value * 10)
+		}
+	}
+
+	// a histogram restored from its serialization merges as the original one
+	data, err := json.Marshal(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored HdrHistogram
+	if err = json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = first.Merge(&restored); err != nil {
+		t.Fatal(err)
+	}
+	for _, percentile := range []float64{1, 50, 99, 100} {
+		if first.ValueAtPercentile(percentile) != merged.ValueAtPercentile(percentile) {
+			t.Errorf("Merged histograms differ at percentile %.0f", percentile)
+		}
+	}
+	if first.TotalCount() != 1000 || first.Min() != 10 || first.Max() != 10000 {
+		t.Errorf("Unexpected count %d, minimum %d, or maximum %d", first.TotalCount(), first.Min(), first.Max())
+	}
+
+	if err = first.Merge(NewHdrHistogram(2)); err == nil {
+		t.Error("Histograms of different precision should not merge.")
+	}
+	if err = json.Unmarshal([]byte(`{"significantFigures":3,"count":5,"counts":[1,2]}`), &restored); err == nil {
+		t.Error("Histograms whose counts do not sum up to the total should not be restored.")
+	}
+}
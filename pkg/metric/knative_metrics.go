@@ -25,59 +25,178 @@
 package metric
 
 import (
-	"encoding/json"
-	"os/exec"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
-func ScrapeDeploymentScales() []DeploymentScale {
-	cmd := exec.Command("python3", "pkg/metric/scrape_scales.py")
-	out, err := cmd.CombinedOutput()
+const (
+	// the Prometheus of the kube-prometheus-stack, used if the address is not configured
+	prometheusNamespace = "monitoring"
+	prometheusService   = "prometheus-kube-prometheus-prometheus"
+	prometheusPort      = 9090
+
+	// missingMetric Value of the Knative statistics that are not reported
+	missingMetric = -99
+)
+
+var serviceResource = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+
+// Scraper Scrapes the scale of the Knative services and the usage of the cluster from the Prometheus HTTP API and the
+// Kubernetes metrics API
+type Scraper struct {
+	prometheus *prometheusClient
+	client     dynamic.Interface
+
+	loaderUsageLock sync.Mutex
+	loaderUsage     processUsage
+}
+
+// NewScraper Creates a scraper querying the Prometheus at the address, which is discovered in the cluster if not set,
+// and the Kubernetes API through the kubeconfig at the path, the KUBECONFIG environment variable or ~/.kube/config,
+// falling back to the in-cluster configuration
+func NewScraper(prometheusAddress string, kubeconfigPath string) (*Scraper, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfigPath
+
+	var client dynamic.Interface
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err == nil {
+		client, err = dynamic.NewForConfig(restConfig)
+	}
 	if err != nil {
-		log.Warn("Fail to scrape deployment scales: ", err)
+		if prometheusAddress == "" {
+			return nil, fmt.Errorf("failed to create a Kubernetes client - %w", err)
+		}
+		log.Warnf("Failed to create a Kubernetes client, scraping only Prometheus - %v", err)
+		client = nil
 	}
 
-	var results []DeploymentScale
-	err = json.Unmarshal(out, &results)
-	if err != nil {
-		log.Warn("Fail to parse deployment scales: ", string(out[:]), err)
+	if prometheusAddress == "" {
+		prometheusAddress, err = discoverPrometheus(client)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return results
+	return newScraper(prometheusAddress, client), nil
 }
 
-func ScrapeKnStats() KnStats {
-	cmd := exec.Command(
-		"python3",
-		"pkg/metric/scrape_kn.py",
-	)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Warn("Fail to scrape Knative: ", err)
+func newScraper(prometheusAddress string, client dynamic.Interface) *Scraper {
+	return &Scraper{
+		prometheus: newPrometheusClient(prometheusAddress),
+		client:     client,
 	}
+}
 
-	var result KnStats
-	err = json.Unmarshal(out, &result)
+// discoverPrometheus Returns the address of the Prometheus service of the kube-prometheus-stack
+func discoverPrometheus(client dynamic.Interface) (string, error) {
+	service, err := client.Resource(serviceResource).Namespace(prometheusNamespace).Get(context.Background(), prometheusService, metav1.GetOptions{})
 	if err != nil {
-		log.Warn("Fail to parse Knative: ", string(out[:]), err)
+		return "", fmt.Errorf("failed to discover Prometheus - %w", err)
 	}
 
-	return result
+	clusterIP, _, _ := unstructured.NestedString(service.Object, "spec", "clusterIP")
+	if clusterIP == "" || clusterIP == "None" {
+		return "", fmt.Errorf("service %s/%s has no cluster IP", prometheusNamespace, prometheusService)
+	}
+
+	return fmt.Sprintf("http://%s:%d", clusterIP, prometheusPort), nil
 }
 
-func ScrapeClusterUsage() ClusterUsage {
-	cmd := exec.Command("python3", "pkg/metric/scrape_infra.py")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Warn("Fail to scrape cluster usage: ", err)
+// DeploymentScales Returns the scale of each Knative service from the metrics of its autoscaler
+func (s *Scraper) DeploymentScales() ([]DeploymentScale, error) {
+	queries := []string{
+		"max(autoscaler_desired_pods) by(configuration_name)",
+		"max(autoscaler_actual_pods) by(configuration_name)",
+		"max(autoscaler_not_ready_pods) by(configuration_name)",
+		"max(autoscaler_pending_pods) by(configuration_name)",
+		"max(autoscaler_terminating_pods) by(configuration_name)",
+		"sum(activator_request_concurrency) by(configuration_name)",
 	}
 
-	var result ClusterUsage
-	err = json.Unmarshal(out, &result)
-	if err != nil {
-		log.Warn("Fail to parse cluster usage: ", string(out[:]), err)
+	values := make([]map[string]float64, len(queries))
+	for i, query := range queries {
+		var err error
+		if values[i], err = s.prometheus.byLabel(query, "configuration_name"); err != nil {
+			return nil, err
+		}
+	}
+	desired, running, unready, pending, terminating, queue := values[0], values[1], values[2], values[3], values[4], values[5]
+
+	functions := make([]string, 0, len(desired))
+	for function := range desired {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+
+	result := make([]DeploymentScale, 0, len(functions))
+	for _, function := range functions {
+		result = append(result, DeploymentScale{
+			Function:        function,
+			DesiredPods:     int(desired[function]),
+			RunningPods:     int(running[function]),
+			UnreadyPods:     int(unready[function]),
+			PendingPods:     int(pending[function]),
+			TerminatingPods: int(terminating[function]),
+			ActivatorQueue:  queue[function],
+		})
+	}
+
+	return result, nil
+}
+
+// KnStats Returns the cluster-wide statistics of the Knative autoscalers and activators, and the scheduling latency of
+// the pods. The statistics that are not reported are set to -99.
+func (s *Scraper) KnStats() (KnStats, error) {
+	var errs []error
+	value := func(query string) float64 {
+		result, err := s.prometheus.scalar(query)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if err != nil || math.IsNaN(result) {
+			return missingMetric
+		}
+		return result
+	}
+	schedulingQuantile := func(quantile float64, histogram string) float64 {
+		return value(fmt.Sprintf(`histogram_quantile(%.2f, sum by (le) (rate(%s{job="kube-scheduler"}[30s])))`, quantile, histogram))
+	}
+
+	result := KnStats{
+		// desired counts set by the autoscalers
+		DesiredPods: int(value("sum(autoscaler_desired_pods)")),
+		// creating containers
+		UnreadyPods: int(value("sum(autoscaler_not_ready_pods)")),
+		// scheduling and image pulling
+		PendingPods: int(value("sum(autoscaler_pending_pods)")),
+		// pods the autoscalers requested from Kubernetes
+		RequestedPods:         int(value("sum(autoscaler_requested_pods)")),
+		RunningPods:           int(value("sum(autoscaler_actual_pods)")),
+		ActivatorRequestCount: int(value("sum(activator_request_count)")),
+
+		AutoscalerStableQueue: value("avg(autoscaler_stable_request_concurrency)"),
+		AutoscalerPanicQueue:  value("avg(autoscaler_panic_request_concurrency)"),
+		ActivatorQueue:        value("avg(activator_request_concurrency)"),
+
+		// latency of a single scheduling round (algorithm and binding) over a window of 30s
+		SchedulingP95: schedulingQuantile(0.95, "scheduler_e2e_scheduling_duration_seconds_bucket"),
+		SchedulingP50: schedulingQuantile(0.50, "scheduler_e2e_scheduling_duration_seconds_bucket"),
+		// latency of the end-to-end placement of a pod, potentially over multiple scheduling rounds
+		E2ePlacementP95: schedulingQuantile(0.95, "scheduler_pod_scheduling_duration_seconds_bucket"),
+		E2ePlacementP50: schedulingQuantile(0.50, "scheduler_pod_scheduling_duration_seconds_bucket"),
 	}
 
-	return result
+	return result, errors.Join(errs...)
 }
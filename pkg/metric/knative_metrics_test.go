@@ -0,0 +1,215 @@
+package metric
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+// newFakePrometheus Answers the instant queries containing a key of the samples with the samples, each being the
+// labels and the value of an element of the resulting vector, and the other queries with an empty vector
+func newFakePrometheus(t *testing.T, samples map[string][]prometheusSample) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if r.URL.Path != "/api/v1/query" || query == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"invalid query"}`))
+			return
+		}
+
+		result := []interface{}{}
+		for key, elements := range samples {
+			if !strings.Contains(query, key) {
+				continue
+			}
+			for _, element := range elements {
+				result = append(result, map[string]interface{}{
+					"metric": element.Labels,
+					"value":  []interface{}{1700000000.0, formatSampleValue(element.Value)},
+				})
+			}
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   map[string]interface{}{"resultType": "vector", "result": result},
+		}); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func formatSampleValue(value float64) string {
+	if math.IsNaN(value) {
+		return "NaN"
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func byConfiguration(values map[string]float64) []prometheusSample {
+	var samples []prometheusSample
+	for name, value := range values {
+		samples = append(samples, prometheusSample{Labels: map[string]string{"configuration_name": name}, Value: value})
+	}
+	return samples
+}
+
+func TestScraperDeploymentScales(t *testing.T) {
+	server := newFakePrometheus(t, map[string][]prometheusSample{
+		"autoscaler_desired_pods":        byConfiguration(map[string]float64{"f1": 3, "f0": 1}),
+		"autoscaler_actual_pods":         byConfiguration(map[string]float64{"f1": 2, "f0": 1}),
+		"autoscaler_not_ready_pods":      byConfiguration(map[string]float64{"f1": 1}),
+		"autoscaler_pending_pods":        byConfiguration(map[string]float64{"f1": 1}),
+		"autoscaler_terminating_pods":    byConfiguration(map[string]float64{"f0": 0}),
+		"activator_request_concurrency":  byConfiguration(map[string]float64{"f1": 2.5}),
+		"unrelated_metric_never_queried": byConfiguration(map[string]float64{"f2": 7}),
+	})
+	defer server.Close()
+
+	scales, err := newScraper(server.URL, nil).DeploymentScales()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []DeploymentScale{
+		{Function: "f0", DesiredPods: 1, RunningPods: 1},
+		{Function: "f1", DesiredPods: 3, RunningPods: 2, UnreadyPods: 1, PendingPods: 1, ActivatorQueue: 2.5},
+	}
+	if !reflect.DeepEqual(scales, expected) {
+		t.Errorf("Unexpected deployment scales %+v", scales)
+	}
+}
+
+func TestScraperKnStats(t *testing.T) {
+	server := newFakePrometheus(t, map[string][]prometheusSample{
+		"sum(autoscaler_desired_pods)":                     {{Value: 10}},
+		"sum(autoscaler_actual_pods)":                      {{Value: 8}},
+		"avg(activator_request_concurrency)":               {{Value: 1.5}},
+		"histogram_quantile(0.95, sum by (le) (rate(sched": {{Value: 0.02}},
+		"histogram_quantile(0.50, sum by (le) (rate(sched": {{Value: math.NaN()}},
+	})
+	defer server.Close()
+
+	stats, err := newScraper(server.URL, nil).KnStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.DesiredPods != 10 || stats.RunningPods != 8 || stats.ActivatorQueue != 1.5 ||
+		stats.SchedulingP95 != 0.02 || stats.E2ePlacementP95 != 0.02 {
+
+		t.Errorf("Unexpected reported statistics %+v", stats)
+	}
+	if stats.PendingPods != missingMetric || stats.AutoscalerPanicQueue != missingMetric || stats.SchedulingP50 != missingMetric {
+		t.Errorf("Unexpected statistics that are not reported %+v", stats)
+	}
+
+	if _, err = newScraper(server.URL+"/missing", nil).KnStats(); err == nil {
+		t.Error("Scraping an unavailable Prometheus should fail.")
+	}
+}
+
+func newTestObject(apiVersion string, kind string, name string, labels map[string]string, fields map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: fields}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetName(name)
+	object.SetLabels(labels)
+
+	return object
+}
+
+func TestScraperClusterUsage(t *testing.T) {
+	byNode := func(master float64, worker0 float64, worker1 float64) []prometheusSample {
+		return []prometheusSample{
+			{Labels: map[string]string{"node": "master"}, Value: master},
+			{Labels: map[string]string{"node": "worker-0"}, Value: worker0},
+			{Labels: map[string]string{"node": "worker-1"}, Value: worker1},
+		}
+	}
+	server := newFakePrometheus(t, map[string][]prometheusSample{
+		`kube_pod_container_resource_requests{resource="cpu"}`:    byNode(1.5, 2, 0.5),
+		`kube_pod_container_resource_limits{resource="cpu"}`:      byNode(3, 4, 1),
+		`kube_pod_container_resource_requests{resource="memory"}`: byNode(1e9, 2e9, 3e9),
+		`kube_pod_container_resource_limits{resource="memory"}`:   byNode(2e9, 4e9, 6e9),
+		"count(kube_pod_info": byNode(20, 5, 1),
+	})
+	defer server.Close()
+
+	node := func(name string, labels map[string]string) k8sruntime.Object {
+		return newTestObject("v1", "Node", name, labels, map[string]interface{}{
+			"status": map[string]interface{}{"allocatable": map[string]interface{}{"cpu": "4", "memory": "8Gi"}},
+		})
+	}
+	nodeMetrics := func(name string, cpu string, memory string) k8sruntime.Object {
+		return newTestObject("metrics.k8s.io/v1beta1", "NodeMetrics", name, nil, map[string]interface{}{
+			"usage": map[string]interface{}{"cpu": cpu, "memory": memory},
+		})
+	}
+	podMetrics := newTestObject("metrics.k8s.io/v1beta1", "PodMetrics", "function-pod", nil, map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "queue-proxy", "usage": map[string]interface{}{"cpu": "5m", "memory": "20Mi"}},
+			map[string]interface{}{"name": functionContainer, "usage": map[string]interface{}{"cpu": "250m", "memory": "100Mi"}},
+		},
+	})
+	podMetrics.SetNamespace("default")
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(), map[schema.GroupVersionResource]string{
+		nodeResource:        "NodeList",
+		nodeMetricsResource: "NodeMetricsList",
+		podMetricsResource:  "PodMetricsList",
+	},
+		node("worker-1", nil),
+		node("master", map[string]string{"node-role.kubernetes.io/control-plane": ""}),
+		node("worker-0", nil),
+	)
+	// the resources of the metrics API cannot be guessed from their kinds
+	for _, object := range []k8sruntime.Object{
+		nodeMetrics("master", "1", "2Gi"),
+		nodeMetrics("worker-0", "2", "4Gi"),
+		nodeMetrics("worker-1", "100m", "1Gi"),
+	} {
+		if err := client.Tracker().Create(nodeMetricsResource, object, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Tracker().Create(podMetricsResource, podMetrics, "default"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := newScraper(server.URL, client).ClusterUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if usage.MasterCpuPct != 25 || usage.MasterMemoryPct != 25 || usage.MasterCpuReq != 1.5 || usage.MasterMemoryLim != 2e9 || usage.MasterPods != 20 {
+		t.Errorf("Unexpected usage of the master node %+v", usage)
+	}
+	if !reflect.DeepEqual(usage.Cpu, []string{"2", "100m"}) || !reflect.DeepEqual(usage.Memory, []string{"4Gi", "1Gi"}) ||
+		!reflect.DeepEqual(usage.CpuReq, []float64{2, 0.5}) || !reflect.DeepEqual(usage.MemoryLim, []float64{4e9, 6e9}) ||
+		!reflect.DeepEqual(usage.Pods, []int{5, 1}) {
+
+		t.Errorf("Unexpected usage of the worker nodes %+v", usage)
+	}
+	// only worker-0 is active, with 50% of its CPU and memory used
+	if usage.CpuPctAvg != 26.25 || usage.CpuPctMax != 50 || usage.CpuPctActiveAvg != 50 || usage.MemoryPctAvg != 50 {
+		t.Errorf("Unexpected aggregate usage of the worker nodes %+v", usage)
+	}
+	if !reflect.DeepEqual(usage.PodCpu, []string{"250m"}) || !reflect.DeepEqual(usage.PodMemory, []string{"100Mi"}) {
+		t.Errorf("Unexpected usage of the function containers %v %v", usage.PodCpu, usage.PodMemory)
+	}
+
+	if _, err = newScraper(server.URL, nil).ClusterUsage(); err == nil {
+		t.Error("Scraping the usage of the nodes without a Kubernetes client should fail.")
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// slowdownScale Factor by which the slowdown is recorded as an integer, i.e., in thousandths
+const slowdownScale = 1000
+
+// wholePhase Minute of the group summarizing all minutes of a phase
+const wholePhase = -1
+
+// LatencySummary Streaming latency distributions of the successful invocations by function, by phase, and by minute
+// of the trace, kept as HDR histograms of the response time, of the platform overhead, i.e., the response time minus
+// the execution time reported by the function, and of the slowdown, i.e., the response time over the requested
+// execution time. Summaries of loaders running parts of the same experiment can be merged.
+type LatencySummary struct {
+	mutex              sync.Mutex
+	significantFigures int
+	groups             map[latencyGroupKey]*latencyGroup
+}
+
+type latencyGroupKey struct {
+	function string
+	phase    int
+	minute   int
+}
+
+type latencyGroup struct {
+	failed       int64
+	responseTime *HdrHistogram
+	overhead     *HdrHistogram
+	slowdown     *HdrHistogram
+}
+
+// LatencySummaryFile Summary as written out, with the percentiles of each distribution next to its histogram
+type LatencySummaryFile struct {
+	SignificantFigures int                   `json:"significantFigures"`
+	Groups             []LatencyGroupSummary `json:"groups"`
+}
+
+// LatencyGroupSummary Distributions of a function in a minute of a phase, or in the whole phase if the minute is not
+// set. The response time and the overhead are in microseconds.
+type LatencyGroupSummary struct {
+	Function     string                     `json:"function"`
+	Phase        int                        `json:"phase"`
+	Minute       *int                       `json:"minute,omitempty"`
+	Failed       int64                      `json:"failed"`
+	ResponseTime LatencyDistributionSummary `json:"responseTime"`
+	Overhead     LatencyDistributionSummary `json:"overhead"`
+	Slowdown     LatencyDistributionSummary `json:"slowdown"`
+}
+
+type LatencyDistributionSummary struct {
+	Count     int64         `json:"count"`
+	P50       float64       `json:"p50"`
+	P90       float64       `json:"p90"`
+	P99       float64       `json:"p99"`
+	P999      float64       `json:"p99.9"`
+	Max       float64       `json:"max"`
+	Histogram *HdrHistogram `json:"histogram"`
+}
+
+func NewLatencySummary(significantFigures int) *LatencySummary {
+	if significantFigures <= 0 {
+		significantFigures = DefaultSignificantFigures
+	}
+
+	return &LatencySummary{
+		significantFigures: significantFigures,
+		groups:             make(map[latencyGroupKey]*latencyGroup),
+	}
+}
+
+// group Returns the group of the key, creating it if needed. The caller holds the mutex.
+func (s *LatencySummary) group(key latencyGroupKey) *latencyGroup {
+	group, ok := s.groups[key]
+	if !ok {
+		group = &latencyGroup{
+			responseTime: NewHdrHistogram(s.significantFigures),
+			overhead:     NewHdrHistogram(s.significantFigures),
+			slowdown:     NewHdrHistogram(s.significantFigures),
+		}
+		s.groups[key] = group
+	}
+
+	return group
+}
+
+// Add Records the invocation in its minute and in its whole phase
+func (s *LatencySummary) Add(record *ExecutionRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	failed := record.ConnectionTimeout || record.FunctionTimeout || record.ErrorClass != ""
+	for _, minute := range []int{record.Minute, wholePhase} {
+		group := s.group(latencyGroupKey{function: record.Function, phase: record.Phase, minute: minute})
+		if failed {
+			group.failed++
+			continue
+		}
+
+		group.responseTime.Record(record.ResponseTime)
+		group.overhead.Record(record.ResponseTime - int64(record.ActualDuration))
+		if record.RequestedDuration > 0 {
+			group.slowdown.Record(record.ResponseTime * slowdownScale / int64(record.RequestedDuration))
+		}
+	}
+}
+
+// Merge Adds the distributions of the other summary, which must have the same precision
+func (s *LatencySummary) Merge(other *LatencySummary) error {
+	other.mutex.Lock()
+	defer other.mutex.Unlock()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, otherGroup := range other.groups {
+		group := s.group(key)
+		group.failed += otherGroup.failed
+
+		for _, pair := range [][2]*HdrHistogram{
+			{group.responseTime, otherGroup.responseTime},
+			{group.overhead, otherGroup.overhead},
+			{group.slowdown, otherGroup.slowdown},
+		} {
+			if err := pair[0].Merge(pair[1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func summarizeDistribution(histogram *HdrHistogram, scale float64) LatencyDistributionSummary {
+	return LatencyDistributionSummary{
+		Count:     histogram.TotalCount(),
+		P50:       float64(histogram.ValueAtPercentile(50)) / scale,
+		P90:       float64(histogram.ValueAtPercentile(90)) / scale,
+		P99:       float64(histogram.ValueAtPercentile(99)) / scale,
+		P999:      float64(histogram.ValueAtPercentile(99.9)) / scale,
+		Max:       float64(histogram.Max()) / scale,
+		Histogram: histogram,
+	}
+}
+
+// Summarize Returns the summary of each group, ordered by function, phase, and minute, with the whole phase first
+func (s *LatencySummary) Summarize() *LatencySummaryFile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]latencyGroupKey, 0, len(s.groups))
+	for key := range s.groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].function != keys[j].function {
+			return keys[i].function < keys[j].function
+		}
+		if keys[i].phase != keys[j].phase {
+			return keys[i].phase < keys[j].phase
+		}
+		return keys[i].minute < keys[j].minute
+	})
+
+	file := &LatencySummaryFile{SignificantFigures: s.significantFigures, Groups: make([]LatencyGroupSummary, 0, len(keys))}
+	for _, key := range keys {
+		group := s.groups[key]
+
+		summary := LatencyGroupSummary{
+			Function:     key.function,
+			Phase:        key.phase,
+			Failed:       group.failed,
+			ResponseTime: summarizeDistribution(group.responseTime, 1),
+			Overhead:     summarizeDistribution(group.overhead, 1),
+			Slowdown:     summarizeDistribution(group.slowdown, slowdownScale),
+		}
+		if key.minute != wholePhase {
+			minute := key.minute
+			summary.Minute = &minute
+		}
+
+		file.Groups = append(file.Groups, summary)
+	}
+
+	return file
+}
+
+// WriteLatencySummary Writes the summary as JSON to the path
+func WriteLatencySummary(path string, summary *LatencySummary) error {
+	data, err := json.Marshal(summary.Summarize())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadLatencySummary Reads a summary written by WriteLatencySummary, so that it can be merged with others
+func ReadLatencySummary(path string) (*LatencySummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file LatencySummaryFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse the latency summary %s - %w", path, err)
+	}
+
+	summary := NewLatencySummary(file.SignificantFigures)
+	for _, groupSummary := range file.Groups {
+		key := latencyGroupKey{function: groupSummary.Function, phase: groupSummary.Phase, minute: wholePhase}
+		if groupSummary.Minute != nil {
+			key.minute = *groupSummary.Minute
+		}
+
+		group := &latencyGroup{
+			failed:       groupSummary.Failed,
+			responseTime: groupSummary.ResponseTime.Histogram,
+			overhead:     groupSummary.Overhead.Histogram,
+			slowdown:     groupSummary.Slowdown.Histogram,
+		}
+		for _, histogram := range []**HdrHistogram{&group.responseTime, &group.overhead, &group.slowdown} {
+			if *histogram == nil {
+				*histogram = NewHdrHistogram(file.SignificantFigures)
+			} else if (*histogram).significantFigures != file.SignificantFigures {
+				return nil, fmt.Errorf("histogram of %s with %d significant figures in a summary of %d",
+					groupSummary.Function, (*histogram).significantFigures, file.SignificantFigures)
+			}
+		}
+		summary.groups[key] = group
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,119 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func newLatencyRecord(function string, phase int, minute int, responseTime int64, actualDuration uint32, requestedDuration uint32) *ExecutionRecord {
+	return &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{
+		Function:          function,
+		Phase:             phase,
+		Minute:            minute,
+		ResponseTime:      responseTime,
+		ActualDuration:    actualDuration,
+		RequestedDuration: requestedDuration,
+	}}
+}
+
+// withinPrecision Tells whether the value is the expected one to three significant figures
+func withinPrecision(value float64, expected float64) bool {
+	return math.Abs(value-expected) <= expected/1000
+}
+
+func findGroup(t *testing.T, file *LatencySummaryFile, function string, phase int, minute *int) LatencyGroupSummary {
+	for _, group := range file.Groups {
+		if group.Function == function && group.Phase == phase && (group.Minute == nil) == (minute == nil) &&
+			(minute == nil || *group.Minute == *minute) {
+
+			return group
+		}
+	}
+
+	t.Fatalf("No group of function %s in phase %d", function, phase)
+	return LatencyGroupSummary{}
+}
+
+func TestLatencySummary(t *testing.T) {
+	summary := NewLatencySummary(3)
+	for i := int64(1); i <= 100; i++ {
+		summary.Add(newLatencyRecord("f0", 2, 1, i*1000, 500, 1000))
+	}
+	summary.Add(newLatencyRecord("f0", 2, 2, 250_000, 200_000, 100_000))
+	summary.Add(newLatencyRecord("f1", 1, 0, 4000, 5000, 0))
+
+	failed := newLatencyRecord("f0", 2, 2, 900_000, 0, 1000)
+	failed.Fail(ErrorClassTimeout, 0, "deadline exceeded")
+	summary.Add(failed)
+
+	file := summary.Summarize()
+	if len(file.Groups) != 5 || file.Groups[0].Function != "f0" || file.Groups[0].Minute != nil {
+		t.Fatalf("Unexpected groups %+v", file.Groups)
+	}
+
+	minute := 1
+	perMinute := findGroup(t, file, "f0", 2, &minute)
+	if perMinute.ResponseTime.Count != 100 || !withinPrecision(perMinute.ResponseTime.P50, 50_000) ||
+		!withinPrecision(perMinute.ResponseTime.P90, 90_000) || !withinPrecision(perMinute.ResponseTime.P99, 99_000) ||
+		perMinute.ResponseTime.Max != 100_000 {
+
+		t.Errorf("Unexpected response time %+v", perMinute.ResponseTime)
+	}
+	if !withinPrecision(perMinute.Overhead.P50, 49_500) || !withinPrecision(perMinute.Slowdown.P50, 50) || perMinute.Slowdown.Max != 100 {
+		t.Errorf("Unexpected overhead %+v or slowdown %+v", perMinute.Overhead, perMinute.Slowdown)
+	}
+
+	phase := findGroup(t, file, "f0", 2, nil)
+	if phase.ResponseTime.Count != 101 || phase.ResponseTime.Max != 250_000 || phase.Slowdown.Max != 100 || phase.Failed != 1 {
+		t.Errorf("Unexpected summary of the phase %+v", phase)
+	}
+
+	// the overhead is not negative, and the slowdown is not recorded without a requested duration
+	other := findGroup(t, file, "f1", 1, nil)
+	if other.Overhead.Max != 0 || other.Slowdown.Count != 0 || other.ResponseTime.Count != 1 {
+		t.Errorf("Unexpected summary %+v", other)
+	}
+}
+
+func TestLatencySummaryMerge(t *testing.T) {
+	dir := t.TempDir()
+
+	var paths []string
+	for loader := int64(0); loader < 2; loader++ {
+		summary := NewLatencySummary(3)
+		for i := int64(1); i <= 50; i++ {
+			summary.Add(newLatencyRecord("f0", 2, 0, (2*i-loader)*1000, 0, 1000))
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("loader%d_latency_1.json", loader))
+		if err := WriteLatencySummary(path, summary); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	merged, err := ReadLatencySummary(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ReadLatencySummary(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = merged.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+
+	phase := findGroup(t, merged.Summarize(), "f0", 2, nil)
+	if phase.ResponseTime.Count != 100 || !withinPrecision(phase.ResponseTime.P50, 50_000) ||
+		!withinPrecision(phase.ResponseTime.P99, 99_000) || phase.ResponseTime.Max != 100_000 || phase.ResponseTime.Histogram.Min() != 1000 {
+
+		t.Errorf("Unexpected merged response time %+v", phase.ResponseTime)
+	}
+
+	if _, err = ReadLatencySummary(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Reading a missing summary should fail.")
+	}
+}
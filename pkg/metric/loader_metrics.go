@@ -1,31 +1,13 @@
 package metric
 
 import (
-	"encoding/csv"
-	"github.com/gocarina/gocsv"
-	log "github.com/sirupsen/logrus"
-	"github.com/vhive-serverless/loader/pkg/common"
 	"math"
-	"os"
 	"sync"
 )
 
-func RunCSVWriter(records chan interface{}, filename string, writerDone *sync.WaitGroup) {
-	log.Debugf("Starting writer for %s", filename)
-
-	file, err := os.Create(filename)
-	common.Check(err)
-	defer file.Close()
-
-	writer := gocsv.NewSafeCSVWriter(csv.NewWriter(file))
-	if err := gocsv.MarshalChan(records, writer); err != nil {
-		log.Fatal(err)
-	}
-
-	writerDone.Done()
-}
-
-func CreateGlobalMetricsCollector(filename string, collector chan *ExecutionRecord,
+// CreateGlobalMetricsCollector Writes the records to the sink as they arrive, and adds them to the latency summary if
+// one is given
+func CreateGlobalMetricsCollector(sink RecordSink, collector chan *ExecutionRecord, latencySummary *LatencySummary,
 	signalReady *sync.WaitGroup, signalEverythingWritten *sync.WaitGroup, totalIssuedChannel chan int64) {
 
 	// NOTE: totalNumberOfInvocations is initialized to MaxInt64 not to allow collector to complete before
@@ -35,21 +17,20 @@ func CreateGlobalMetricsCollector(filename string, collector chan *ExecutionReco
 	var totalNumberOfInvocations int64 = math.MaxInt64
 	var currentlyWritten int64
 
-	file, err := os.Create(filename)
-	common.Check(err)
-	defer file.Close()
-
 	signalReady.Done()
 
 	records := make(chan interface{}, 100)
 	writerDone := sync.WaitGroup{}
 	writerDone.Add(1)
-	go RunCSVWriter(records, filename, &writerDone)
+	go RunRecordWriter(records, sink, &writerDone)
 
 	for {
 		select {
 		case record := <-collector:
 			records <- record
+			if latencySummary != nil {
+				latencySummary.Add(record)
+			}
 
 			currentlyWritten++
 		case record := <-totalIssuedChannel:
@@ -0,0 +1,392 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"strconv"
+)
+
+// parquetMagic Marks the start and the end of a Parquet file
+const parquetMagic = "PAR1"
+
+// parquetRowGroupSize Records buffered before they are written out as a row group
+const parquetRowGroupSize = 64 * 1024
+
+// physical types, encodings, and page types of the Parquet format, as numbered in its Thrift definition
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetUTF8     = 0
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetDataPage = 0
+)
+
+// parquetSink Writes the records as an uncompressed Parquet file of required, plain encoded columns, one page per
+// column in each row group. The name and the version of the schema are kept in the metadata of the file.
+type parquetSink struct {
+	file   *os.File
+	writer *bufio.Writer
+	offset int64
+
+	schema  RecordSchema
+	columns []parquetColumn
+
+	rows      int
+	totalRows int64
+	rowGroups [][]parquetColumnChunk
+}
+
+// parquetColumn Column with the plain encoded values of the records of the current row group
+type parquetColumn struct {
+	recordColumn
+	values bytes.Buffer
+}
+
+type parquetColumnChunk struct {
+	offset int64
+	size   int64
+}
+
+func newParquetSink(path string, schema RecordSchema) (*parquetSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &parquetSink{file: file, writer: bufio.NewWriter(file), schema: schema}
+	for _, column := range recordColumns(schema.recordType) {
+		sink.columns = append(sink.columns, parquetColumn{recordColumn: column})
+	}
+
+	if err = sink.write([]byte(parquetMagic)); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (s *parquetSink) write(data []byte) error {
+	n, err := s.writer.Write(data)
+	s.offset += int64(n)
+
+	return err
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	switch c.kind {
+	case columnInt:
+		return parquetInt64
+	case columnFloat:
+		return parquetDouble
+	case columnBool:
+		return parquetBoolean
+	default:
+		return parquetByteArray
+	}
+}
+
+func (s *parquetSink) Write(record interface{}) error {
+	value, err := recordValue(s.schema, record)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.columns {
+		column := &s.columns[i]
+
+		columnValue, err := column.value(value)
+		if err != nil {
+			return err
+		}
+
+		switch v := columnValue.(type) {
+		case int64:
+			_ = binary.Write(&column.values, binary.LittleEndian, v)
+		case float64:
+			_ = binary.Write(&column.values, binary.LittleEndian, math.Float64bits(v))
+		case bool:
+			// booleans are bit-packed, starting from the least significant bit
+			if s.rows%8 == 0 {
+				column.values.WriteByte(0)
+			}
+			if v {
+				column.values.Bytes()[column.values.Len()-1] |= 1 << (s.rows % 8)
+			}
+		case string:
+			_ = binary.Write(&column.values, binary.LittleEndian, uint32(len(v)))
+			column.values.WriteString(v)
+		}
+	}
+
+	s.rows++
+	if s.rows == parquetRowGroupSize {
+		return s.flushRowGroup()
+	}
+
+	return nil
+}
+
+// flushRowGroup Writes the buffered records as a row group with a data page per column
+func (s *parquetSink) flushRowGroup() error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	chunks := make([]parquetColumnChunk, len(s.columns))
+	for i := range s.columns {
+		column := &s.columns[i]
+
+		header := &thriftCompactWriter{}
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(column.values.Len()))
+		header.i32(3, int32(column.values.Len()))
+		header.structField(5, func() {
+			header.i32(1, int32(s.rows))
+			header.i32(2, parquetPlain)
+			header.i32(3, parquetRLE)
+			header.i32(4, parquetRLE)
+		})
+		header.stop()
+
+		chunks[i] = parquetColumnChunk{offset: s.offset, size: int64(header.Len() + column.values.Len())}
+		if err := s.write(header.Bytes()); err != nil {
+			return err
+		}
+		if err := s.write(column.values.Bytes()); err != nil {
+			return err
+		}
+
+		column.values.Reset()
+	}
+
+	s.rowGroups = append(s.rowGroups, chunks)
+	s.totalRows += int64(s.rows)
+	s.rows = 0
+
+	return nil
+}
+
+// fileMetadata Returns the footer of the file, describing its schema and the location of each column chunk
+func (s *parquetSink) fileMetadata() []byte {
+	rowGroupRows := func(i int) int64 {
+		if i < len(s.rowGroups)-1 {
+			return parquetRowGroupSize
+		}
+		return s.totalRows - int64(i)*parquetRowGroupSize
+	}
+
+	w := &thriftCompactWriter{}
+	w.i32(1, 1)
+
+	w.listHeader(2, thriftStruct, len(s.columns)+1)
+	w.structElement(func() {
+		w.binary(4, "schema")
+		w.i32(5, int32(len(s.columns)))
+	})
+	for i := range s.columns {
+		column := &s.columns[i]
+		w.structElement(func() {
+			w.i32(1, column.physicalType())
+			w.i32(3, parquetRequired)
+			w.binary(4, column.name)
+			if column.physicalType() == parquetByteArray {
+				w.i32(6, parquetUTF8)
+			}
+		})
+	}
+
+	w.i64(3, s.totalRows)
+
+	w.listHeader(4, thriftStruct, len(s.rowGroups))
+	for i, chunks := range s.rowGroups {
+		w.structElement(func() {
+			var totalSize int64
+
+			w.listHeader(1, thriftStruct, len(chunks))
+			for j, chunk := range chunks {
+				column := &s.columns[j]
+				totalSize += chunk.size
+
+				w.structElement(func() {
+					w.i64(2, chunk.offset)
+					w.structField(3, func() {
+						w.i32(1, column.physicalType())
+						w.listHeader(2, thriftI32, 2)
+						w.i32Element(parquetPlain)
+						w.i32Element(parquetRLE)
+						w.listHeader(3, thriftBinary, 1)
+						w.binaryElement(column.name)
+						w.i32(4, 0) // uncompressed
+						w.i64(5, rowGroupRows(i))
+						w.i64(6, chunk.size)
+						w.i64(7, chunk.size)
+						w.i64(9, chunk.offset)
+					})
+				})
+			}
+
+			w.i64(2, totalSize)
+			w.i64(3, rowGroupRows(i))
+		})
+	}
+
+	if s.schema.Version > 0 {
+		w.listHeader(5, thriftStruct, 2)
+		for _, keyValue := range [][2]string{{"schema", s.schema.Name}, {"schema_version", strconv.Itoa(s.schema.Version)}} {
+			w.structElement(func() {
+				w.binary(1, keyValue[0])
+				w.binary(2, keyValue[1])
+			})
+		}
+	}
+
+	w.binary(6, "vhive-serverless loader")
+	w.stop()
+
+	return w.Bytes()
+}
+
+func (s *parquetSink) Close() error {
+	err := s.flushRowGroup()
+	if err == nil {
+		footer := s.fileMetadata()
+		if err = s.write(footer); err == nil {
+			_ = binary.Write(s.writer, binary.LittleEndian, uint32(len(footer)))
+			_, _ = s.writer.WriteString(parquetMagic)
+			err = s.writer.Flush()
+		}
+	}
+
+	if err != nil {
+		_ = s.file.Close()
+		return err
+	}
+
+	return s.file.Close()
+}
+
+// types of the Thrift compact protocol
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftCompactWriter Encodes the structs of the metadata of Parquet files in the Thrift compact protocol, in which the
+// ID of each field is encoded as the difference from the previous field of the same struct
+type thriftCompactWriter struct {
+	bytes.Buffer
+	lastField  int16
+	outerField []int16
+}
+
+func (w *thriftCompactWriter) varint(value uint64) {
+	w.Write(binary.AppendUvarint(nil, value))
+}
+
+func zigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func (w *thriftCompactWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - w.lastField; delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.WriteByte(fieldType)
+		w.varint(zigzag(int64(id)))
+	}
+	w.lastField = id
+}
+
+func (w *thriftCompactWriter) i32(id int16, value int32) {
+	w.fieldHeader(id, thriftI32)
+	w.i32Element(value)
+}
+
+func (w *thriftCompactWriter) i64(id int16, value int64) {
+	w.fieldHeader(id, thriftI64)
+	w.varint(zigzag(value))
+}
+
+func (w *thriftCompactWriter) binary(id int16, value string) {
+	w.fieldHeader(id, thriftBinary)
+	w.binaryElement(value)
+}
+
+func (w *thriftCompactWriter) i32Element(value int32) {
+	w.varint(zigzag(int64(value)))
+}
+
+func (w *thriftCompactWriter) binaryElement(value string) {
+	w.varint(uint64(len(value)))
+	w.WriteString(value)
+}
+
+func (w *thriftCompactWriter) listHeader(id int16, elementType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		w.WriteByte(0xf0 | elementType)
+		w.varint(uint64(size))
+	}
+}
+
+// structElement Writes a struct whose fields are written by the function, as a list element
+func (w *thriftCompactWriter) structElement(fields func()) {
+	w.outerField = append(w.outerField, w.lastField)
+	w.lastField = 0
+
+	fields()
+	w.stop()
+
+	w.lastField = w.outerField[len(w.outerField)-1]
+	w.outerField = w.outerField[:len(w.outerField)-1]
+}
+
+func (w *thriftCompactWriter) structField(id int16, fields func()) {
+	w.fieldHeader(id, thriftStruct)
+	w.structElement(fields)
+}
+
+// stop Ends the current struct
+func (w *thriftCompactWriter) stop() {
+	w.WriteByte(0)
+}
@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prometheusClient Evaluates instant queries through the Prometheus HTTP API
+type prometheusClient struct {
+	address string
+	client  *http.Client
+}
+
+// prometheusSample Labels and value of an element of the instant vector a query evaluates to
+type prometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+func newPrometheusClient(address string) *prometheusClient {
+	return &prometheusClient{
+		address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// query Evaluates the query at the current time, returning the elements of the resulting instant vector, or the
+// single element of a scalar
+func (p *prometheusClient) query(query string) ([]prometheusSample, error) {
+	resp, err := p.client.Get(p.address + "/api/v1/query?" + url.Values{"query": {query}}.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response prometheusResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("got status code %d and an unparsable response - %w", resp.StatusCode, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query %s failed with %s - %s", query, response.ErrorType, response.Error)
+	}
+
+	var samples []prometheusSample
+	switch response.Data.ResultType {
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		}
+		if err = json.Unmarshal(response.Data.Result, &vector); err != nil {
+			return nil, err
+		}
+
+		for _, element := range vector {
+			value, err := prometheusValue(element.Value)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, prometheusSample{Labels: element.Metric, Value: value})
+		}
+	case "scalar":
+		var scalar []interface{}
+		if err = json.Unmarshal(response.Data.Result, &scalar); err != nil {
+			return nil, err
+		}
+
+		value, err := prometheusValue(scalar)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, prometheusSample{Value: value})
+	default:
+		return nil, fmt.Errorf("unsupported result type %s of query %s", response.Data.ResultType, query)
+	}
+
+	return samples, nil
+}
+
+// scalar Returns the value of the first element the query evaluates to, or NaN if it evaluates to none
+func (p *prometheusClient) scalar(query string) (float64, error) {
+	samples, err := p.query(query)
+	if err != nil || len(samples) == 0 {
+		return math.NaN(), err
+	}
+
+	return samples[0].Value, nil
+}
+
+// byLabel Returns the values the query evaluates to by the value of the given label
+func (p *prometheusClient) byLabel(query string, label string) (map[string]float64, error) {
+	samples, err := p.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		result[sample.Labels[label]] = sample.Value
+	}
+
+	return result, nil
+}
+
+// prometheusValue Parses the [<timestamp>, "<value>"] pair of a sample
+func prometheusValue(pair []interface{}) (float64, error) {
+	if len(pair) != 2 {
+		return 0, fmt.Errorf("malformed sample %v", pair)
+	}
+
+	value, ok := pair[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("malformed sample value %v", pair[1])
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
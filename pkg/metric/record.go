@@ -24,6 +24,11 @@
 
 package metric
 
+import (
+	"fmt"
+	"hash/fnv"
+)
+
 type StartType string
 
 const (
@@ -42,21 +47,97 @@ type MinuteInvocationRecord struct {
 }
 
 type ExecutionRecordBase struct {
-	Phase        int    `csv:"phase"`
-	Instance     string `csv:"instance"`
-	InvocationID string `csv:"invocationID"`
-	StartTime    int64  `csv:"startTime"`
+	Phase        int    `csv:"phase" json:"phase"`
+	Instance     string `csv:"instance" json:"instance"`
+	InvocationID string `csv:"invocationID" json:"invocationID"`
+	// TraceID W3C trace ID of the invocation, by which the record joins with the spans of the platform
+	TraceID   string `csv:"traceID" json:"traceID"`
+	Trigger   string `csv:"trigger" json:"trigger"`
+	StartTime int64  `csv:"startTime" json:"startTime"`
 
 	// Measurements in microseconds
-	RequestedDuration           uint32 `csv:"requestedDuration"`
-	GRPCConnectionEstablishTime int64  `csv:"grpcConnEstablish"`
-	ResponseTime                int64  `csv:"responseTime"`
-	ActualDuration              uint32 `csv:"actualDuration"`
+	RequestedDuration           uint32 `csv:"requestedDuration" json:"requestedDuration"`
+	GRPCConnectionEstablishTime int64  `csv:"grpcConnEstablish" json:"grpcConnEstablish"`
+	ResponseTime                int64  `csv:"responseTime" json:"responseTime"`
+	ActualDuration              uint32 `csv:"actualDuration" json:"actualDuration"`
+
+	// ConnectionReused set if the invocation used a pooled gRPC connection instead of dialing a new one
+	ConnectionReused  bool `csv:"connectionReused" json:"connectionReused"`
+	ConnectionTimeout bool `csv:"connectionTimeout" json:"connectionTimeout"`
+	FunctionTimeout   bool `csv:"functionTimeout" json:"functionTimeout"`
+	// TimeoutPhase phase in which the invocation exceeded its deadline, if it did
+	TimeoutPhase string `csv:"timeoutPhase" json:"timeoutPhase"`
+
+	// ErrorClass class of the failure, empty if the invocation succeeded
+	ErrorClass ErrorClass `csv:"errorClass" json:"errorClass"`
+	// StatusCode HTTP status or gRPC status code of the response, depending on the protocol of the invoker
+	StatusCode int `csv:"statusCode" json:"statusCode"`
+	// ErrorHash hash of the error message, by which failures with the same cause can be grouped
+	ErrorHash string `csv:"errorHash" json:"errorHash"`
+	// RetryCount attempt index of the record, i.e., the number of times the invocation was retried before the attempt
+	RetryCount int `csv:"retryCount" json:"retryCount"`
+	// Hedged set if the record is of a duplicate issued because the attempt was slow
+	Hedged bool `csv:"hedged" json:"hedged"`
+
+	// Function and Minute of the trace of the invocation, by which the latency summaries are grouped. Not written out,
+	// as the instance and the invocation ID identify them in the output.
+	Function string `csv:"-" json:"-"`
+	Minute   int    `csv:"-" json:"-"`
+}
 
-	ConnectionTimeout bool `csv:"connectionTimeout"`
-	FunctionTimeout   bool `csv:"functionTimeout"`
+// Fail Classifies the failure of the invocation. ConnectionTimeout and FunctionTimeout are left to the invoker, as
+// they are kept for compatibility with existing analyses.
+func (r *ExecutionRecordBase) Fail(class ErrorClass, statusCode int, message string) {
+	r.ErrorClass = class
+	r.StatusCode = statusCode
+
+	if message != "" {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(message))
+
+		r.ErrorHash = fmt.Sprintf("%08x", hash.Sum32())
+	}
 }
 
+// ErrorClass Class of the failure of an invocation
+type ErrorClass string
+
+const (
+	// ErrorClassRequest the request could not be created or authorized
+	ErrorClassRequest ErrorClass = "request"
+	// ErrorClassConnect the connection could not be established, e.g., refused, unresolvable, or timed out
+	ErrorClassConnect ErrorClass = "connect"
+	// ErrorClassTimeout the invocation exceeded its deadline after the connection was established
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassOverload the platform shed the invocation, e.g., HTTP 429 and 503, or gRPC RESOURCE_EXHAUSTED and
+	// UNAVAILABLE
+	ErrorClassOverload ErrorClass = "overload"
+	// ErrorClassNotFound the function does not exist, e.g., HTTP 404, or gRPC NOT_FOUND and UNIMPLEMENTED
+	ErrorClassNotFound ErrorClass = "not_found"
+	// ErrorClassUnauthorized the credentials were rejected, e.g., HTTP 401 and 403, or gRPC UNAUTHENTICATED and
+	// PERMISSION_DENIED
+	ErrorClassUnauthorized ErrorClass = "unauthorized"
+	// ErrorClassClient the request was rejected otherwise, e.g., other HTTP 4xx, or gRPC INVALID_ARGUMENT
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassServer the function or the platform failed, e.g., other HTTP 5xx, or gRPC INTERNAL and UNKNOWN
+	ErrorClassServer ErrorClass = "server"
+	// ErrorClassResponse the response could not be read or deserialized
+	ErrorClassResponse ErrorClass = "response"
+	// ErrorClassValidation the platform reported success, but the response failed the configured validation, e.g., it
+	// was served by another function
+	ErrorClassValidation ErrorClass = "validation"
+)
+
+// phases in which an invocation can time out
+const (
+	// TimeoutPhaseConnect the connection was not established within the connect timeout
+	TimeoutPhaseConnect = "connect"
+	// TimeoutPhaseFirstByte the connection was established, but no response arrived before the deadline
+	TimeoutPhaseFirstByte = "first_byte"
+	// TimeoutPhaseTotal the response started arriving, but was not complete before the deadline
+	TimeoutPhaseTotal = "total"
+)
+
 type ExecutionRecordOpenWhisk struct {
 	ExecutionRecordBase
 
@@ -73,14 +154,16 @@ type ExecutionRecord struct {
 	ExecutionRecordBase
 
 	// Measurements in microseconds
-	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage"`
-	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout"`
+	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage" json:"actualMemoryUsage"`
+	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout" json:"memoryAllocationTimeout"`
 
-	AsyncResponseID     string `csv:"-"`
-	TimeToSubmitMs      int64  `csv:"timeToSubmitMs"`
-	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs"`
+	AsyncResponseID     string `csv:"-" json:"-"`
+	TimeToSubmitMs      int64  `csv:"timeToSubmitMs" json:"timeToSubmitMs"`
+	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs" json:"userCodeExecutionMs"`
+	// QueueingDelay Time between the submission and the function receiving the event in the Knative asynchronous mode
+	QueueingDelay int64 `csv:"queueingDelay" json:"queueingDelay"`
 
-	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs"`
+	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs" json:"timeToGetResponseMs"`
 }
 
 type DeploymentScale struct {
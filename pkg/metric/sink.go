@@ -0,0 +1,353 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// RecordSink Output file of the records of a type, written one record at a time
+type RecordSink interface {
+	// Write Appends the record, given by value or by pointer
+	Write(record interface{}) error
+	// Close Flushes the records written and closes the file, which is complete only once closed
+	Close() error
+}
+
+// RecordSchema Record type written to a sink, and the version of its fields, to be increased whenever they change, so
+// that analyses can tell apart the outputs of different versions of the loader. Sinks write the version as a header
+// in the format of the file, unless it is zero.
+type RecordSchema struct {
+	Name    string
+	Version int
+
+	recordType reflect.Type
+}
+
+var (
+	ExecutionRecordSchema = RecordSchema{Name: "ExecutionRecord", Version: 1, recordType: reflect.TypeOf(ExecutionRecord{})}
+	DeploymentScaleSchema = RecordSchema{Name: "DeploymentScale", Version: 1, recordType: reflect.TypeOf(DeploymentScale{})}
+	KnStatsSchema         = RecordSchema{Name: "KnStats", Version: 1, recordType: reflect.TypeOf(KnStats{})}
+	ClusterUsageSchema    = RecordSchema{Name: "ClusterUsage", Version: 1, recordType: reflect.TypeOf(ClusterUsage{})}
+)
+
+// RecordSchemas Schemas of the record types whose output format can be selected
+var RecordSchemas = []RecordSchema{ExecutionRecordSchema, DeploymentScaleSchema, KnStatsSchema, ClusterUsageSchema}
+
+// Unversioned Returns the schema without a version, whose sinks write no header
+func (s RecordSchema) Unversioned() RecordSchema {
+	s.Version = 0
+	return s
+}
+
+// NewRecordSink Creates the file at the path, replacing any existing one, and returns the sink writing the records of
+// the schema to it in the format
+func NewRecordSink(format string, path string, schema RecordSchema) (RecordSink, error) {
+	switch format {
+	case common.OutputFormatCSV:
+		return newCSVSink(path, schema)
+	case common.OutputFormatJSONLines:
+		return newJSONLinesSink(path, schema)
+	case common.OutputFormatParquet:
+		return newParquetSink(path, schema)
+	case common.OutputFormatSQLite:
+		return newSQLiteSink(path, schema)
+	default:
+		return nil, fmt.Errorf("unsupported output format '%s'", format)
+	}
+}
+
+// OutputExtension Returns the file extension of the format
+func OutputExtension(format string) string {
+	switch format {
+	case common.OutputFormatJSONLines:
+		return ".jsonl"
+	case common.OutputFormatParquet:
+		return ".parquet"
+	case common.OutputFormatSQLite:
+		return ".sqlite"
+	default:
+		return ".csv"
+	}
+}
+
+// RunRecordWriter Writes the records to the sink until the channel is closed, and closes the sink
+func RunRecordWriter(records chan interface{}, sink RecordSink, writerDone *sync.WaitGroup) {
+	for record := range records {
+		if err := sink.Write(record); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	writerDone.Done()
+}
+
+type columnKind int
+
+const (
+	columnInt columnKind = iota
+	columnFloat
+	columnBool
+	columnString
+	// columnJSON values of other kinds, e.g., slices, written as JSON text
+	columnJSON
+)
+
+// recordColumn Field of a record written as a column, named by its csv tag as in the CSV files
+type recordColumn struct {
+	name  string
+	index []int
+	kind  columnKind
+}
+
+// recordColumns Returns the columns of the fields of the record type in the order of their declaration, where the
+// fields of embedded structs are inlined and the fields tagged with csv:"-" are left out
+func recordColumns(recordType reflect.Type) []recordColumn {
+	var columns []recordColumn
+
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, column := range recordColumns(field.Type) {
+				column.index = append([]int{i}, column.index...)
+				columns = append(columns, column)
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, recordColumn{name: name, index: []int{i}, kind: kindOfColumn(field.Type)})
+	}
+
+	return columns
+}
+
+func kindOfColumn(fieldType reflect.Type) columnKind {
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return columnInt
+	case reflect.Float32, reflect.Float64:
+		return columnFloat
+	case reflect.Bool:
+		return columnBool
+	case reflect.String:
+		return columnString
+	default:
+		return columnJSON
+	}
+}
+
+// value Returns the value of the column in the record as an int64, a float64, a bool, or a string
+func (c *recordColumn) value(record reflect.Value) (interface{}, error) {
+	field := record.FieldByIndex(c.index)
+
+	switch c.kind {
+	case columnInt:
+		if field.CanInt() {
+			return field.Int(), nil
+		}
+		return int64(field.Uint()), nil
+	case columnFloat:
+		return field.Float(), nil
+	case columnBool:
+		return field.Bool(), nil
+	case columnString:
+		return field.String(), nil
+	default:
+		data, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode column %s - %w", c.name, err)
+		}
+		return string(data), nil
+	}
+}
+
+// recordValue Returns the struct of the record of the schema, given by value or by pointer
+func recordValue(schema RecordSchema, record interface{}) (reflect.Value, error) {
+	value := reflect.Indirect(reflect.ValueOf(record))
+	if !value.IsValid() || value.Type() != schema.recordType {
+		return reflect.Value{}, fmt.Errorf("cannot write a %T to a sink of %s", record, schema.Name)
+	}
+
+	return value, nil
+}
+
+// csvSink Writes the header row of the column names, preceded by a comment line with the version of the schema, and
+// formats the values as gocsv does, so that unversioned files read as before
+type csvSink struct {
+	file    *os.File
+	writer  *csv.Writer
+	schema  RecordSchema
+	columns []recordColumn
+	row     []string
+}
+
+func newCSVSink(path string, schema RecordSchema) (*csvSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &csvSink{
+		file:    file,
+		writer:  csv.NewWriter(file),
+		schema:  schema,
+		columns: recordColumns(schema.recordType),
+	}
+
+	if schema.Version > 0 {
+		_, _ = fmt.Fprintf(file, "# %s schema version %d\n", schema.Name, schema.Version)
+	}
+
+	header := make([]string, len(sink.columns))
+	for i, column := range sink.columns {
+		header[i] = column.name
+	}
+	_ = sink.writer.Write(header)
+	sink.row = make([]string, len(sink.columns))
+
+	return sink, nil
+}
+
+func (s *csvSink) Write(record interface{}) error {
+	value, err := recordValue(s.schema, record)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.columns {
+		columnValue, err := s.columns[i].value(value)
+		if err != nil {
+			return err
+		}
+
+		switch v := columnValue.(type) {
+		case int64:
+			s.row[i] = strconv.FormatInt(v, 10)
+		case float64:
+			s.row[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			s.row[i] = strconv.FormatBool(v)
+		case string:
+			s.row[i] = v
+		}
+	}
+
+	return s.writer.Write(s.row)
+}
+
+func (s *csvSink) Close() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		_ = s.file.Close()
+		return err
+	}
+
+	return s.file.Close()
+}
+
+// jsonLinesSink Writes each record as a JSON object on its own line, preceded by a line with the name and the version
+// of the schema
+type jsonLinesSink struct {
+	file   *os.File
+	writer *bufio.Writer
+	schema RecordSchema
+}
+
+type jsonLinesHeader struct {
+	Schema  string `json:"schema"`
+	Version int    `json:"version"`
+}
+
+func newJSONLinesSink(path string, schema RecordSchema) (*jsonLinesSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &jsonLinesSink{file: file, writer: bufio.NewWriter(file), schema: schema}
+	if schema.Version > 0 {
+		if err = sink.writeLine(jsonLinesHeader{Schema: schema.Name, Version: schema.Version}); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+
+	return sink, nil
+}
+
+func (s *jsonLinesSink) writeLine(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, _ = s.writer.Write(data)
+	return s.writer.WriteByte('\n')
+}
+
+func (s *jsonLinesSink) Write(record interface{}) error {
+	value, err := recordValue(s.schema, record)
+	if err != nil {
+		return err
+	}
+
+	return s.writeLine(value.Interface())
+}
+
+func (s *jsonLinesSink) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		_ = s.file.Close()
+		return err
+	}
+
+	return s.file.Close()
+}
@@ -0,0 +1,424 @@
+package metric
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func testExecutionRecords() []*ExecutionRecord {
+	records := make([]*ExecutionRecord, 10)
+	for i := range records {
+		records[i] = &ExecutionRecord{
+			ExecutionRecordBase: ExecutionRecordBase{
+				Phase:             2,
+				Instance:          "trace-func-0-abc",
+				InvocationID:      "min0.inv" + string(rune('0'+i)),
+				StartTime:         int64(1_700_000_000_000_000 + i),
+				RequestedDuration: uint32(1000 * i),
+				ResponseTime:      int64(1500 * i),
+				ActualDuration:    uint32(1100 * i),
+				FunctionTimeout:   i%3 == 0,
+				Hedged:            i%2 == 1,
+				Function:          "trace-func-0",
+			},
+			ActualMemoryUsage: uint32(i),
+			QueueingDelay:     int64(-i),
+		}
+	}
+	records[4].Fail(ErrorClassServer, 500, "internal error, with a \"quoted\" message")
+
+	return records
+}
+
+func writeRecords(t *testing.T, format string, path string, schema RecordSchema, records ...interface{}) {
+	sink, err := NewRecordSink(format, path, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range records {
+		if err = sink.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	records := testExecutionRecords()
+	expected, err := gocsv.MarshalString(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName string
+		schema   RecordSchema
+		header   string
+	}{
+		{testName: "unversioned", schema: ExecutionRecordSchema.Unversioned()},
+		{testName: "versioned", schema: ExecutionRecordSchema, header: "# ExecutionRecord schema version 1\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "duration.csv")
+			writeRecords(t, common.OutputFormatCSV, path, test.schema, toInterfaces(records)...)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the values are formatted as gocsv did before the sinks
+			if string(data) != test.header+expected {
+				t.Errorf("Unexpected file\n%s\ninstead of\n%s", data, test.header+expected)
+			}
+		})
+	}
+}
+
+func TestCSVSinkSlices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster_usage.csv")
+	writeRecords(t, common.OutputFormatCSV, path, ClusterUsageSchema.Unversioned(),
+		ClusterUsage{Timestamp: 1, Cpu: []string{"10.0%", "20.5%"}, Pods: []int{3, 4}})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"[""10.0%"",""20.5%""]"`) || !strings.Contains(lines[1], `"[3,4]"`) {
+		t.Errorf("Slices not written as JSON in %s", data)
+	}
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	usage := ClusterUsage{Timestamp: 7, MasterCpuPct: 12.5, Cpu: []string{"1%"}, PodMemory: []string{"2%"}, Pods: []int{1}}
+	legacy, err := json.Marshal(usage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName string
+		schema   RecordSchema
+		lines    []string
+	}{
+		{testName: "unversioned", schema: ClusterUsageSchema.Unversioned(), lines: []string{string(legacy)}},
+		{testName: "versioned", schema: ClusterUsageSchema, lines: []string{`{"schema":"ClusterUsage","version":1}`, string(legacy)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cluster_usage.jsonl")
+			writeRecords(t, common.OutputFormatJSONLines, path, test.schema, usage)
+
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			var lines []string
+			for scanner := bufio.NewScanner(file); scanner.Scan(); {
+				lines = append(lines, scanner.Text())
+			}
+			if !reflect.DeepEqual(lines, test.lines) {
+				t.Errorf("Unexpected lines %v", lines)
+			}
+		})
+	}
+
+	// the execution records are keyed as the columns of the CSV files, without the fields left out of them
+	path := filepath.Join(t.TempDir(), "duration.jsonl")
+	writeRecords(t, common.OutputFormatJSONLines, path, ExecutionRecordSchema.Unversioned(), testExecutionRecords()[4])
+
+	var fields map[string]interface{}
+	data, _ := os.ReadFile(path)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["errorClass"] != "server" || fields["invocationID"] != "min0.inv4" || len(fields) != len(recordColumns(ExecutionRecordSchema.recordType)) {
+		t.Errorf("Unexpected record %v", fields)
+	}
+}
+
+func TestSinkRejectsOtherRecordTypes(t *testing.T) {
+	for _, format := range common.ValidOutputFormats {
+		t.Run(format, func(t *testing.T) {
+			sink, err := NewRecordSink(format, filepath.Join(t.TempDir(), "out"+OutputExtension(format)), KnStatsSchema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sink.Close()
+
+			if sink.Write(DeploymentScale{}) == nil {
+				t.Error("Record of another type written")
+			}
+		})
+	}
+}
+
+func TestSQLiteSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duration.sqlite")
+	records := testExecutionRecords()
+
+	// enough records to commit more than one batch
+	var written []interface{}
+	for i := 0; i < sqliteBatchSize+len(records); i++ {
+		written = append(written, records[i%len(records)])
+	}
+	writeRecords(t, common.OutputFormatSQLite, path, ExecutionRecordSchema, written...)
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var recordType string
+	var version, count int
+	if err = db.QueryRow("SELECT record_type, version FROM schema_version").Scan(&recordType, &version); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.QueryRow("SELECT COUNT(*) FROM execution_record").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if recordType != "ExecutionRecord" || version != 1 || count != len(written) {
+		t.Errorf("Unexpected schema %s version %d with %d records", recordType, version, count)
+	}
+
+	var invocationID, errorClass string
+	var responseTime, queueingDelay int64
+	var functionTimeout, hedged bool
+	err = db.QueryRow(`SELECT invocationID, errorClass, responseTime, queueingDelay, functionTimeout, hedged
+		FROM execution_record WHERE rowid = 5`).Scan(&invocationID, &errorClass, &responseTime, &queueingDelay, &functionTimeout, &hedged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invocationID != "min0.inv4" || errorClass != "server" || responseTime != 6000 || queueingDelay != -4 || functionTimeout || hedged {
+		t.Errorf("Unexpected record %s %s %d %d %t %t", invocationID, errorClass, responseTime, queueingDelay, functionTimeout, hedged)
+	}
+}
+
+func TestParquetSink(t *testing.T) {
+	t.Run("execution_records", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "duration.parquet")
+		records := testExecutionRecords()
+		writeRecords(t, common.OutputFormatParquet, path, ExecutionRecordSchema, toInterfaces(records)...)
+
+		metadata, columns := readParquet(t, path)
+		if metadata["schema"] != "ExecutionRecord" || metadata["schema_version"] != "1" {
+			t.Errorf("Unexpected metadata %v", metadata)
+		}
+		if len(columns) != len(recordColumns(ExecutionRecordSchema.recordType)) {
+			t.Errorf("Unexpected columns %v", columns)
+		}
+
+		for i, record := range records {
+			row := []interface{}{columns["invocationID"][i], columns["requestedDuration"][i], columns["queueingDelay"][i],
+				columns["functionTimeout"][i], columns["hedged"][i], columns["errorClass"][i]}
+			expected := []interface{}{record.InvocationID, int64(record.RequestedDuration), record.QueueingDelay,
+				record.FunctionTimeout, record.Hedged, string(record.ErrorClass)}
+
+			if !reflect.DeepEqual(row, expected) {
+				t.Errorf("Unexpected row %v instead of %v", row, expected)
+			}
+		}
+	})
+
+	t.Run("row_groups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "deployment_scale.parquet")
+
+		var scales []interface{}
+		for i := 0; i < parquetRowGroupSize+3; i++ {
+			scales = append(scales, DeploymentScale{Timestamp: int64(i), Function: "f", ActivatorQueue: float64(i) / 2})
+		}
+		writeRecords(t, common.OutputFormatParquet, path, DeploymentScaleSchema.Unversioned(), scales...)
+
+		metadata, columns := readParquet(t, path)
+		if len(metadata) != 0 || len(columns["timestamp"]) != len(scales) {
+			t.Fatalf("Unexpected metadata %v or number of rows %d", metadata, len(columns["timestamp"]))
+		}
+
+		last := len(scales) - 1
+		if columns["timestamp"][last] != int64(last) || columns["activator_queue"][last] != float64(last)/2 || columns["function"][last] != "f" {
+			t.Errorf("Unexpected last row")
+		}
+	})
+}
+
+func toInterfaces(records []*ExecutionRecord) []interface{} {
+	result := make([]interface{}, len(records))
+	for i, record := range records {
+		result[i] = record
+	}
+
+	return result
+}
+
+// readParquet Returns the key-value metadata and the values of each column of the Parquet file, checking its layout
+func readParquet(t *testing.T, path string) (map[string]string, map[string][]interface{}) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatal("Missing magic bytes")
+	}
+
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftCompactReader{data: data[len(data)-8-footerLength : len(data)-8]}
+	fileMetadata := footer.readStruct()
+
+	metadata := make(map[string]string)
+	if keyValues, ok := fileMetadata[5].([]interface{}); ok {
+		for _, keyValue := range keyValues {
+			fields := keyValue.(map[int16]interface{})
+			metadata[string(fields[1].([]byte))] = string(fields[2].([]byte))
+		}
+	}
+
+	schema := fileMetadata[2].([]interface{})
+	if int(schema[0].(map[int16]interface{})[5].(int64)) != len(schema)-1 {
+		t.Fatal("Unexpected number of children of the root")
+	}
+
+	columns := make(map[string][]interface{})
+	var rows int64
+	for _, rowGroup := range fileMetadata[4].([]interface{}) {
+		rowGroupFields := rowGroup.(map[int16]interface{})
+		rows += rowGroupFields[3].(int64)
+
+		for i, chunk := range rowGroupFields[1].([]interface{}) {
+			chunkMetadata := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			name := string(schema[i+1].(map[int16]interface{})[4].([]byte))
+			if string(chunkMetadata[3].([]interface{})[0].([]byte)) != name {
+				t.Fatalf("Column chunk %d not of column %s", i, name)
+			}
+
+			page := &thriftCompactReader{data: data, position: int(chunkMetadata[9].(int64))}
+			pageHeader := page.readStruct()
+			values := data[page.position : page.position+int(pageHeader[3].(int64))]
+			count := int(pageHeader[5].(map[int16]interface{})[1].(int64))
+
+			columns[name] = append(columns[name], decodePlain(chunkMetadata[1].(int64), values, count)...)
+		}
+	}
+
+	if rows != fileMetadata[3].(int64) {
+		t.Errorf("Row groups of %d rows in a file of %d", rows, fileMetadata[3].(int64))
+	}
+
+	return metadata, columns
+}
+
+func decodePlain(physicalType int64, values []byte, count int) []interface{} {
+	result := make([]interface{}, count)
+	for i := range result {
+		switch physicalType {
+		case parquetInt64:
+			result[i] = int64(binary.LittleEndian.Uint64(values[8*i:]))
+		case parquetDouble:
+			result[i] = math.Float64frombits(binary.LittleEndian.Uint64(values[8*i:]))
+		case parquetBoolean:
+			result[i] = values[i/8]&(1<<(i%8)) != 0
+		case parquetByteArray:
+			length := int(binary.LittleEndian.Uint32(values))
+			result[i] = string(values[4 : 4+length])
+			values = values[4+length:]
+		}
+	}
+
+	return result
+}
+
+// thriftCompactReader Decodes structs of the Thrift compact protocol into maps by field ID, with integers as int64
+// and binaries as byte slices
+type thriftCompactReader struct {
+	data     []byte
+	position int
+}
+
+func (r *thriftCompactReader) readByte() byte {
+	r.position++
+	return r.data[r.position-1]
+}
+
+func (r *thriftCompactReader) readVarint() uint64 {
+	value, n := binary.Uvarint(r.data[r.position:])
+	r.position += n
+
+	return value
+}
+
+func (r *thriftCompactReader) readValue(valueType byte) interface{} {
+	switch valueType {
+	case 1, 2:
+		return valueType == 1
+	case 3:
+		return int64(r.readByte())
+	case 4, thriftI32, thriftI64:
+		value := r.readVarint()
+		return int64(value>>1) ^ -int64(value&1)
+	case 7:
+		r.position += 8
+		return math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.position-8:]))
+	case thriftBinary:
+		length := int(r.readVarint())
+		r.position += length
+		return r.data[r.position-length : r.position]
+	case thriftList:
+		header := r.readByte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.readVarint())
+		}
+
+		elements := make([]interface{}, size)
+		for i := range elements {
+			elements[i] = r.readValue(header & 0x0f)
+		}
+		return elements
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		panic("unexpected Thrift type")
+	}
+}
+
+func (r *thriftCompactReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+
+	var id int16
+	for {
+		header := r.readByte()
+		if header == 0 {
+			return fields
+		}
+
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			value := r.readVarint()
+			id = int16(int64(value>>1) ^ -int64(value&1))
+		}
+		fields[id] = r.readValue(header & 0x0f)
+	}
+}
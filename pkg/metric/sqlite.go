@@ -0,0 +1,200 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteBatchSize Records inserted in a transaction, as committing each record on its own is slow
+const sqliteBatchSize = 10000
+
+// sqliteVersionTable Table of the names and the versions of the schemas of the tables in the database
+const sqliteVersionTable = "schema_version"
+
+// sqliteSink Writes the records to a table named after their type, e.g., execution_record, of an SQLite database
+type sqliteSink struct {
+	db     *sql.DB
+	insert *sql.Stmt
+	// tx transaction of the current batch, with the insert statement prepared in it
+	tx       *sql.Tx
+	txInsert *sql.Stmt
+
+	schema  RecordSchema
+	columns []recordColumn
+	row     []interface{}
+	pending int
+}
+
+func newSQLiteSink(path string, schema RecordSchema) (*sqliteSink, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	// the records are inserted on a single connection, one transaction at a time
+	db.SetMaxOpenConns(1)
+
+	sink := &sqliteSink{db: db, schema: schema, columns: recordColumns(schema.recordType)}
+	sink.row = make([]interface{}, len(sink.columns))
+
+	if err = sink.createTable(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create the table of %s - %w", schema.Name, err)
+	}
+	if err = sink.begin(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+// sqliteTableName Returns the table of the record type in snake case, e.g., kn_stats for KnStats
+func sqliteTableName(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+func (c *recordColumn) sqliteType() string {
+	switch c.kind {
+	case columnInt, columnBool:
+		return "INTEGER"
+	case columnFloat:
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
+
+func (s *sqliteSink) createTable() error {
+	table := quoteIdentifier(sqliteTableName(s.schema.Name))
+
+	definitions := make([]string, len(s.columns))
+	placeholders := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		definitions[i] = quoteIdentifier(column.name) + " " + column.sqliteType()
+		placeholders[i] = "?"
+	}
+
+	if _, err := s.db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", table, strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+
+	if s.schema.Version > 0 {
+		statements := []string{
+			fmt.Sprintf("CREATE TABLE %s (record_type TEXT PRIMARY KEY, version INTEGER)", sqliteVersionTable),
+			fmt.Sprintf("INSERT INTO %s VALUES ('%s', %d)", sqliteVersionTable, s.schema.Name, s.schema.Version),
+		}
+		for _, statement := range statements {
+			if _, err := s.db.Exec(statement); err != nil {
+				return err
+			}
+		}
+	}
+
+	insert, err := s.db.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", table, strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	s.insert = insert
+
+	return nil
+}
+
+func (s *sqliteSink) begin() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	s.tx, s.txInsert = tx, tx.Stmt(s.insert)
+
+	return nil
+}
+
+func (s *sqliteSink) Write(record interface{}) error {
+	value, err := recordValue(s.schema, record)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.columns {
+		if s.row[i], err = s.columns[i].value(value); err != nil {
+			return err
+		}
+	}
+
+	if _, err = s.txInsert.Exec(s.row...); err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending == sqliteBatchSize {
+		s.pending = 0
+		if err = s.tx.Commit(); err != nil {
+			return err
+		}
+
+		return s.begin()
+	}
+
+	return nil
+}
+
+func (s *sqliteSink) Close() error {
+	err := s.tx.Commit()
+	if closeErr := s.insert.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
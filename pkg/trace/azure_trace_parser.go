@@ -60,6 +60,12 @@ func NewAzureParser(directoryPath string, totalDuration int, yamlPath string) *A
 	}
 }
 
+// SeedFunctionNames Makes the names of the functions deterministic, so that the functions of a previous experiment on
+// the same trace can be found by name and reused
+func (p *AzureTraceParser) SeedFunctionNames(seed int64) {
+	p.functionNameGenerator = rand.New(rand.NewSource(seed))
+}
+
 func createRuntimeMap(runtime *[]common.FunctionRuntimeStats) map[string]*common.FunctionRuntimeStats {
 	result := make(map[string]*common.FunctionRuntimeStats)
 
@@ -148,7 +154,7 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 	reader := csv.NewReader(csvfile)
 
 	rowID := -1
-	hashOwnerIndex, hashAppIndex, hashFunctionIndex, invocationColumnIndex := -1, -1, -1, -1
+	hashOwnerIndex, hashAppIndex, hashFunctionIndex, triggerIndex, invocationColumnIndex := -1, -1, -1, -1, -1
 
 	for {
 		record, err := reader.Read()
@@ -170,7 +176,8 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 					hashAppIndex = i
 				case "hashfunction":
 					hashFunctionIndex = i
-				case "trigger": //! Unused field.
+				case "trigger":
+					triggerIndex = i
 					invocationColumnIndex = i + 1
 				}
 			}
@@ -199,11 +206,16 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 				totalInvocations[minute] = totalInvocations[minute] + num
 			}
 
+			trigger := ""
+			if triggerIndex != -1 {
+				trigger = strings.ToLower(record[triggerIndex])
+			}
+
 			result = append(result, common.FunctionInvocationStats{
 				HashOwner:    record[hashOwnerIndex],
 				HashApp:      record[hashAppIndex],
 				HashFunction: record[hashFunctionIndex],
-				Trigger:      record[invocationColumnIndex-1],
+				Trigger:      trigger,
 				Invocations:  invocations,
 			})
 		}
@@ -214,6 +226,29 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 	return &result
 }
 
+// ReadTraceDuration Returns the number of minutes contained in the invocation trace of the given directory
+func ReadTraceDuration(directoryPath string) int {
+	csvfile, err := os.Open(directoryPath + "/invocations.csv")
+	if err != nil {
+		log.Fatal("Failed to open invocation CSV file.", err)
+	}
+	defer csvfile.Close()
+
+	header, err := csv.NewReader(csvfile).Read()
+	if err != nil {
+		log.Fatal("Failed to read invocation CSV header.", err)
+	}
+
+	duration := 0
+	for _, column := range header {
+		if _, err := strconv.Atoi(column); err == nil {
+			duration++
+		}
+	}
+
+	return duration
+}
+
 func parseRuntimeTrace(traceFile string) *[]common.FunctionRuntimeStats {
 	log.Infof("Parsing function duration trace: %s\n", traceFile)
 
@@ -134,3 +134,16 @@ func TestParserWrapper(t *testing.T) {
 		t.Error("Unexpected results.")
 	}
 }
+
+func TestParserSeededFunctionNames(t *testing.T) {
+	var names []string
+	for i := 0; i < 2; i++ {
+		parser := NewAzureParser("test_data", 10, "workloads/container/trace_func_go.yaml")
+		parser.SeedFunctionNames(42)
+		names = append(names, parser.Parse()[0].Name)
+	}
+
+	if names[0] != names[1] {
+		t.Errorf("Function names differ with the same seed - %v", names)
+	}
+}
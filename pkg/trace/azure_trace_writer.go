@@ -0,0 +1,124 @@
+package trace
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WriteAzureTrace Writes the functions as an Azure-format trace directory (invocations.csv, durations.csv,
+// memory.csv, and dirigent.json if any of the functions carries Dirigent metadata), so that the trace can be fed back
+// to the loader through the regular parsers.
+func WriteAzureTrace(directoryPath string, functions []*common.Function) error {
+	if err := os.MkdirAll(directoryPath, 0755); err != nil {
+		return err
+	}
+
+	if err := writeInvocationTrace(filepath.Join(directoryPath, "invocations.csv"), functions); err != nil {
+		return err
+	}
+
+	var runtime []common.FunctionRuntimeStats
+	var memory []common.FunctionMemoryStats
+	var dirigentMetadata []common.DirigentMetadata
+
+	for _, function := range functions {
+		if function.RuntimeStats == nil || function.MemoryStats == nil {
+			return fmt.Errorf("function %s is missing runtime or memory statistics", function.Name)
+		}
+
+		runtime = append(runtime, *function.RuntimeStats)
+		memory = append(memory, *function.MemoryStats)
+
+		if function.DirigentMetadata != nil {
+			metadata := *function.DirigentMetadata
+			metadata.HashFunction = function.InvocationStats.HashFunction
+			dirigentMetadata = append(dirigentMetadata, metadata)
+		}
+	}
+
+	if err := writeCSV(filepath.Join(directoryPath, "durations.csv"), &runtime); err != nil {
+		return err
+	}
+	if err := writeCSV(filepath.Join(directoryPath, "memory.csv"), &memory); err != nil {
+		return err
+	}
+
+	if len(dirigentMetadata) > 0 {
+		data, err := json.MarshalIndent(dirigentMetadata, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err = os.WriteFile(filepath.Join(directoryPath, "dirigent.json"), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Trace with %d functions written to %s", len(functions), directoryPath)
+
+	return nil
+}
+
+func writeInvocationTrace(traceFile string, functions []*common.Function) error {
+	file, err := os.Create(traceFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	duration := 0
+	for _, function := range functions {
+		duration = common.MaxOf(duration, len(function.InvocationStats.Invocations))
+	}
+
+	writer := csv.NewWriter(file)
+
+	header := []string{"HashOwner", "HashApp", "HashFunction", "Trigger"}
+	for minute := 1; minute <= duration; minute++ {
+		header = append(header, strconv.Itoa(minute))
+	}
+	if err = writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, function := range functions {
+		stats := function.InvocationStats
+
+		row := []string{stats.HashOwner, stats.HashApp, stats.HashFunction, stats.Trigger}
+		for minute := 0; minute < duration; minute++ {
+			count := 0
+			if minute < len(stats.Invocations) {
+				count = stats.Invocations[minute]
+			}
+
+			row = append(row, strconv.Itoa(count))
+		}
+
+		if err = writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func writeCSV(traceFile string, data interface{}) error {
+	file, err := os.Create(traceFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gocsv.MarshalFile(data, file)
+}
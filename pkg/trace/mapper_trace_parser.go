@@ -39,6 +39,12 @@ func NewMapperParser(directoryPath string, totalDuration int) *MapperTraceParser
 	}
 }
 
+// SeedFunctionNames Makes the names of the functions deterministic, so that the functions of a previous experiment on
+// the same trace can be found by name and reused
+func (p *MapperTraceParser) SeedFunctionNames(seed int64) {
+	p.functionNameGenerator = rand.New(rand.NewSource(seed))
+}
+
 func (p *MapperTraceParser) extractFunctions(mapperOutput functionToProxy, deploymentInfo functionToDeploymentInfo, dirPath string) []*common.Function {
 	var result []*common.Function
 
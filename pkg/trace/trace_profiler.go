@@ -31,15 +31,72 @@ import (
 	"github.com/vhive-serverless/loader/pkg/common"
 )
 
-func DoStaticTraceProfiling(functions []*common.Function) {
+// defaultScaleProfilingPercentile Percentile used by the percentile strategy if none is configured
+const defaultScaleProfilingPercentile = 90
+
+// ScaleProfilingConfiguration Selects how the initial scale of the functions is derived from the trace
+type ScaleProfilingConfiguration struct {
+	Strategy           string
+	WarmupDuration     int
+	ExperimentDuration int
+	// Percentile used only by the percentile strategy, defaulting to the 90th percentile if not set
+	Percentile float64
+}
+
+func DoStaticTraceProfiling(functions []*common.Function, cfg ScaleProfilingConfiguration) {
 	for i := 0; i < len(functions); i++ {
 		f := functions[i]
 
-		f.InitialScale = int(math.Ceil(profileConcurrency(functions[i])))
+		f.InitialScale, f.ScaleSchedule = profileScale(f, cfg)
 		log.Debugf("Function %s initial scale will be %d.\n", f.Name, f.InitialScale)
 	}
 }
 
+func profileScale(function *common.Function, cfg ScaleProfilingConfiguration) (int, []int) {
+	minutes := len(function.InvocationStats.Invocations)
+	if minutes == 0 {
+		return 0, nil
+	}
+
+	warmup := common.MinOf(common.MaxOf(cfg.WarmupDuration, 1), minutes)
+	scale := func(minute int) int {
+		return int(math.Ceil(profileConcurrency(function, minute)))
+	}
+
+	switch cfg.Strategy {
+	case common.ScaleProfilingWarmupMax:
+		result := 0
+		for minute := 0; minute < warmup; minute++ {
+			result = common.MaxOf(result, scale(minute))
+		}
+
+		return result, nil
+	case common.ScaleProfilingPercentile:
+		window := common.MinOf(common.MaxOf(cfg.WarmupDuration, 0)+cfg.ExperimentDuration, minutes)
+
+		concurrency := make([]float64, common.MaxOf(window, 1))
+		for minute := range concurrency {
+			concurrency[minute] = profileConcurrency(function, minute)
+		}
+
+		percentile := cfg.Percentile
+		if percentile <= 0 {
+			percentile = defaultScaleProfilingPercentile
+		}
+
+		return int(math.Ceil(common.Percentile(concurrency, percentile))), nil
+	case common.ScaleProfilingSchedule:
+		schedule := make([]int, warmup)
+		for minute := range schedule {
+			schedule[minute] = scale(minute)
+		}
+
+		return schedule[0], schedule
+	default:
+		return scale(0), nil
+	}
+}
+
 func ApplyResourceLimits(functions []*common.Function, CPULimit string) {
 	for i := 0; i < len(functions); i++ {
 		memoryPct100 := int(functions[i].MemoryStats.Percentile100)
@@ -78,8 +135,8 @@ func ConvertMemoryToCpu(memoryRequest int) int {
 	return int(cpuRequest * 1000)
 }
 
-func profileConcurrency(function *common.Function) float64 {
-	IPM := function.InvocationStats.Invocations[0]
+func profileConcurrency(function *common.Function, minute int) float64 {
+	IPM := function.InvocationStats.Invocations[minute]
 
 	// Arrival rate - unit 1 s
 	rps := float64(IPM) / 60.0
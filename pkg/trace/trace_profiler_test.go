@@ -25,6 +25,7 @@
 package trace
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -95,7 +96,7 @@ func TestStaticTraceProfiling(t *testing.T) {
 				},
 			}
 
-			DoStaticTraceProfiling([]*common.Function{f})
+			DoStaticTraceProfiling([]*common.Function{f}, ScaleProfilingConfiguration{Strategy: common.ScaleProfilingFirstMinute})
 			ApplyResourceLimits([]*common.Function{f}, test.CPULimit)
 
 			if f.InitialScale != test.expectedInitialScale ||
@@ -108,3 +109,67 @@ func TestStaticTraceProfiling(t *testing.T) {
 		})
 	}
 }
+
+func TestScaleProfilingStrategies(t *testing.T) {
+	// concurrency of 0, 1, 2, 4, and 1 with a runtime of 1s
+	invocations := []int{0, 60, 120, 240, 60}
+
+	tests := []struct {
+		testName              string
+		cfg                   ScaleProfilingConfiguration
+		expectedInitialScale  int
+		expectedScaleSchedule []int
+	}{
+		{
+			testName:             "first_minute",
+			cfg:                  ScaleProfilingConfiguration{Strategy: common.ScaleProfilingFirstMinute, WarmupDuration: 3},
+			expectedInitialScale: 0,
+		},
+		{
+			testName:             "default",
+			cfg:                  ScaleProfilingConfiguration{WarmupDuration: 3},
+			expectedInitialScale: 0,
+		},
+		{
+			testName:             "warmup_max",
+			cfg:                  ScaleProfilingConfiguration{Strategy: common.ScaleProfilingWarmupMax, WarmupDuration: 3},
+			expectedInitialScale: 2,
+		},
+		{
+			testName:             "percentile_50",
+			cfg:                  ScaleProfilingConfiguration{Strategy: common.ScaleProfilingPercentile, WarmupDuration: 2, ExperimentDuration: 3, Percentile: 50},
+			expectedInitialScale: 1,
+		},
+		{
+			testName:             "percentile_default",
+			cfg:                  ScaleProfilingConfiguration{Strategy: common.ScaleProfilingPercentile, WarmupDuration: 2, ExperimentDuration: 3},
+			expectedInitialScale: 4,
+		},
+		{
+			testName:             "percentile_100",
+			cfg:                  ScaleProfilingConfiguration{Strategy: common.ScaleProfilingPercentile, WarmupDuration: 2, ExperimentDuration: 3, Percentile: 100},
+			expectedInitialScale: 4,
+		},
+		{
+			testName:              "schedule",
+			cfg:                   ScaleProfilingConfiguration{Strategy: common.ScaleProfilingSchedule, WarmupDuration: 3},
+			expectedInitialScale:  0,
+			expectedScaleSchedule: []int{0, 1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			f := &common.Function{
+				InvocationStats: &common.FunctionInvocationStats{Invocations: invocations},
+				RuntimeStats:    &common.FunctionRuntimeStats{Average: 1000.0},
+			}
+
+			DoStaticTraceProfiling([]*common.Function{f}, test.cfg)
+
+			if f.InitialScale != test.expectedInitialScale || !slices.Equal(f.ScaleSchedule, test.expectedScaleSchedule) {
+				t.Errorf("Unexpected scale profile - initial scale: %d; schedule: %v", f.InitialScale, f.ScaleSchedule)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// ReportPercentiles Percentiles at which the runtime and memory CDFs are reported
+var ReportPercentiles = []float64{0, 1, 5, 25, 50, 75, 95, 99, 100}
+
+// MinuteStatistics Aggregate load the trace generates in a single minute. Concurrency follows from Little's law, as in
+// the static trace profiling, whereas the instance count assumes at least one instance for each invoked function.
+type MinuteStatistics struct {
+	Minute              int     `json:"minute"`
+	Warmup              bool    `json:"warmup"`
+	Invocations         int     `json:"invocations"`
+	RPS                 float64 `json:"rps"`
+	ActiveFunctions     int     `json:"activeFunctions"`
+	ExpectedConcurrency float64 `json:"expectedConcurrency"`
+	ExpectedInstances   int     `json:"expectedInstances"`
+	MemoryFootprintMB   float64 `json:"memoryFootprintMB"`
+}
+
+// ColdStartProneFunction Function that is expected to suffer cold starts, as it becomes idle for at least the idle
+// threshold and is then invoked again.
+type ColdStartProneFunction struct {
+	Name               string  `json:"name"`
+	HashFunction       string  `json:"hashFunction"`
+	Invocations        int     `json:"invocations"`
+	ActiveMinutes      int     `json:"activeMinutes"`
+	ExpectedColdStarts int     `json:"expectedColdStarts"`
+	RuntimeMs          float64 `json:"runtimeMs"`
+}
+
+type CDFPoint struct {
+	Percentile float64 `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+type TraceReport struct {
+	Functions          int `json:"functions"`
+	WarmupMinutes      int `json:"warmupMinutes"`
+	ExperimentMinutes  int `json:"experimentMinutes"`
+	IdleThresholdMins  int `json:"idleThresholdMinutes"`
+	TotalInvocations   int `json:"totalInvocations"`
+	UninvokedFunctions int `json:"uninvokedFunctions"`
+
+	PeakRPS               float64 `json:"peakRPS"`
+	PeakConcurrency       float64 `json:"peakConcurrency"`
+	PeakMemoryFootprintMB float64 `json:"peakMemoryFootprintMB"`
+
+	Minutes                 []MinuteStatistics       `json:"minutes"`
+	ColdStartProneFunctions []ColdStartProneFunction `json:"coldStartProneFunctions"`
+
+	// RuntimeCDF and MemoryCDF are computed over the per-function averages
+	RuntimeCDF []CDFPoint `json:"runtimeCDF"`
+	MemoryCDF  []CDFPoint `json:"memoryCDF"`
+}
+
+// ComputeTraceReport Characterises the load the functions generate over the first warmup+duration minutes of the
+// trace, as given by the scale profiling configuration of the loader. Functions invoked again after at least
+// idleThreshold idle minutes, or first invoked while deployed with no instances, are reported as cold-start-prone.
+func ComputeTraceReport(functions []*common.Function, profiling ScaleProfilingConfiguration, idleThreshold int) *TraceReport {
+	functions = filterSampleableFunctions(functions)
+	warmup := common.MaxOf(profiling.WarmupDuration, 0)
+	duration := profiling.ExperimentDuration
+	idleThreshold = common.MaxOf(idleThreshold, 1)
+
+	report := &TraceReport{
+		Functions:         len(functions),
+		WarmupMinutes:     warmup,
+		ExperimentMinutes: duration,
+		IdleThresholdMins: idleThreshold,
+	}
+
+	totalMinutes := warmup + duration
+	for _, function := range functions {
+		totalMinutes = common.MinOf(totalMinutes, len(function.InvocationStats.Invocations))
+	}
+
+	for minute := 0; minute < totalMinutes; minute++ {
+		statistics := MinuteStatistics{Minute: minute, Warmup: minute < warmup}
+
+		for _, function := range functions {
+			count := function.InvocationStats.Invocations[minute]
+			if count == 0 {
+				continue
+			}
+
+			concurrency := profileConcurrency(function, minute)
+			instances := common.MaxOf(int(math.Ceil(concurrency)), 1)
+
+			statistics.Invocations += count
+			statistics.ActiveFunctions++
+			statistics.ExpectedConcurrency += concurrency
+			statistics.ExpectedInstances += instances
+			statistics.MemoryFootprintMB += float64(instances) * function.MemoryStats.Average
+		}
+		statistics.RPS = float64(statistics.Invocations) / 60.0
+
+		report.TotalInvocations += statistics.Invocations
+		report.PeakRPS = math.Max(report.PeakRPS, statistics.RPS)
+		report.PeakConcurrency = math.Max(report.PeakConcurrency, statistics.ExpectedConcurrency)
+		report.PeakMemoryFootprintMB = math.Max(report.PeakMemoryFootprintMB, statistics.MemoryFootprintMB)
+
+		report.Minutes = append(report.Minutes, statistics)
+	}
+
+	for _, function := range functions {
+		invocations := function.InvocationStats.Invocations[:totalMinutes]
+
+		coldStartProne := profileColdStarts(function, invocations, idleThreshold, profiling)
+		if coldStartProne.ActiveMinutes == 0 {
+			report.UninvokedFunctions++
+		} else if coldStartProne.ExpectedColdStarts > 0 {
+			report.ColdStartProneFunctions = append(report.ColdStartProneFunctions, coldStartProne)
+		}
+	}
+	sort.SliceStable(report.ColdStartProneFunctions, func(i, j int) bool {
+		return report.ColdStartProneFunctions[i].ExpectedColdStarts > report.ColdStartProneFunctions[j].ExpectedColdStarts
+	})
+
+	report.RuntimeCDF = computeCDF(averageRuntimes(functions))
+	report.MemoryCDF = computeCDF(averageMemory(functions))
+
+	return report
+}
+
+func profileColdStarts(function *common.Function, invocations []int, idleThreshold int, profiling ScaleProfilingConfiguration) ColdStartProneFunction {
+	result := ColdStartProneFunction{
+		Name:         function.Name,
+		HashFunction: function.InvocationStats.HashFunction,
+		RuntimeMs:    function.RuntimeStats.Average,
+	}
+
+	// the loader deploys the functions with the initial scale profiled by the configured strategy, or with no instances
+	// if there is no warmup, in which case it does not profile the trace. The first invocation only causes a cold start
+	// if the function is deployed with no instances.
+	initialScale := 0
+	if profiling.WarmupDuration > 0 {
+		initialScale, _ = profileScale(function, profiling)
+	}
+	idle := -1
+	if initialScale == 0 {
+		idle = idleThreshold
+	}
+	for _, count := range invocations {
+		if count == 0 {
+			if idle >= 0 {
+				idle++
+			}
+			continue
+		}
+
+		if idle >= idleThreshold {
+			result.ExpectedColdStarts++
+		}
+
+		result.Invocations += count
+		result.ActiveMinutes++
+		idle = 0
+	}
+
+	return result
+}
+
+func computeCDF(data []float64) []CDFPoint {
+	if len(data) == 0 {
+		return nil
+	}
+
+	sorted := append([]float64{}, data...)
+	sort.Float64s(sorted)
+
+	result := make([]CDFPoint, len(ReportPercentiles))
+	for i, p := range ReportPercentiles {
+		result[i] = CDFPoint{Percentile: p, Value: common.PercentileSorted(sorted, p)}
+	}
+
+	return result
+}
+
+// WriteTable Writes the report in a human-readable form
+func (r *TraceReport) WriteTable(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	_, _ = fmt.Fprintf(w, "Functions:\t%d (%d not invoked)\t\n", r.Functions, r.UninvokedFunctions)
+	_, _ = fmt.Fprintf(w, "Duration:\t%d min (%d min warmup)\t\n", len(r.Minutes), r.WarmupMinutes)
+	_, _ = fmt.Fprintf(w, "Invocations:\t%d\t\n", r.TotalInvocations)
+	_, _ = fmt.Fprintf(w, "Peak RPS:\t%.2f\t\n", r.PeakRPS)
+	_, _ = fmt.Fprintf(w, "Peak concurrency:\t%.2f\t\n", r.PeakConcurrency)
+	_, _ = fmt.Fprintf(w, "Peak memory footprint:\t%.0f MB\t\n", r.PeakMemoryFootprintMB)
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, "Minute\tPhase\tInvocations\tRPS\tActive functions\tConcurrency\tInstances\tMemory [MB]\t")
+	for _, m := range r.Minutes {
+		phase := "run"
+		if m.Warmup {
+			phase = "warmup"
+		}
+
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%.2f\t%d\t%.2f\t%d\t%.0f\t\n",
+			m.Minute, phase, m.Invocations, m.RPS, m.ActiveFunctions, m.ExpectedConcurrency, m.ExpectedInstances, m.MemoryFootprintMB)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, "Percentile\tRuntime [ms]\tMemory [MB]\t")
+	for i := range r.RuntimeCDF {
+		_, _ = fmt.Fprintf(w, "p%.0f\t%.2f\t%.2f\t\n", r.RuntimeCDF[i].Percentile, r.RuntimeCDF[i].Value, r.MemoryCDF[i].Value)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintf(w, "Cold-start-prone functions (idle for >= %d min):\t%d\t\n", r.IdleThresholdMins, len(r.ColdStartProneFunctions))
+	if len(r.ColdStartProneFunctions) > 0 {
+		_, _ = fmt.Fprintln(w, "Function\tInvocations\tActive minutes\tExpected cold starts\tRuntime [ms]\t")
+		for _, f := range r.ColdStartProneFunctions {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\t\n", f.Name, f.Invocations, f.ActiveMinutes, f.ExpectedColdStarts, f.RuntimeMs)
+		}
+	}
+
+	_ = w.Flush()
+}
@@ -0,0 +1,94 @@
+package trace
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestComputeTraceReport(t *testing.T) {
+	functions := createSamplingTestFunctions(3)
+	// invoked in minutes 0 and 2, i.e., after one idle minute
+	functions[1].InvocationStats.Invocations = []int{60, 0, 120}
+
+	report := ComputeTraceReport(functions, ScaleProfilingConfiguration{WarmupDuration: 1, ExperimentDuration: 5}, 1)
+
+	if len(report.Minutes) != 3 || !report.Minutes[0].Warmup || report.Minutes[1].Warmup {
+		t.Fatalf("Unexpected minutes %v.", report.Minutes)
+	}
+
+	// minute 2: function 1 - 2 RPS x 200 ms, function 2 - 0.1 RPS x 300 ms
+	last := report.Minutes[2]
+	if last.Invocations != 126 || math.Abs(last.RPS-2.1) > 1e-9 || math.Abs(last.ExpectedConcurrency-0.43) > 1e-9 ||
+		last.ActiveFunctions != 2 || last.ExpectedInstances != 2 || last.MemoryFootprintMB != 50 {
+
+		t.Errorf("Unexpected statistics of the last minute %+v.", last)
+	}
+
+	if report.TotalInvocations != 180+12 || report.UninvokedFunctions != 1 || report.PeakRPS != last.RPS {
+		t.Errorf("Unexpected aggregates %+v.", report)
+	}
+
+	if len(report.ColdStartProneFunctions) != 1 ||
+		report.ColdStartProneFunctions[0].HashFunction != functions[1].InvocationStats.HashFunction ||
+		report.ColdStartProneFunctions[0].ExpectedColdStarts != 1 {
+
+		t.Errorf("Unexpected cold-start-prone functions %v.", report.ColdStartProneFunctions)
+	}
+
+	if len(report.RuntimeCDF) != len(ReportPercentiles) || report.RuntimeCDF[0].Value != 100 ||
+		report.RuntimeCDF[len(ReportPercentiles)-1].Value != 300 || report.MemoryCDF[4].Value != 20 {
+
+		t.Errorf("Unexpected CDFs %v %v.", report.RuntimeCDF, report.MemoryCDF)
+	}
+
+	var table bytes.Buffer
+	report.WriteTable(&table)
+	if !strings.Contains(table.String(), functions[1].Name) {
+		t.Error("The table should list the cold-start-prone functions.")
+	}
+}
+
+func TestProfileColdStarts(t *testing.T) {
+	tests := []struct {
+		testName      string
+		invocations   []int
+		idleThreshold int
+		strategy      string
+		warmup        int
+		expected      int
+	}{
+		{testName: "always_active", invocations: []int{1, 1, 1}, idleThreshold: 1, warmup: 1, expected: 0},
+		// deployed with an initial scale of 0, as the function is idle in the first minute
+		{testName: "leading_idle", invocations: []int{0, 0, 1}, idleThreshold: 1, warmup: 1, expected: 1},
+		{testName: "leading_idle_with_gap", invocations: []int{0, 1, 0, 1}, idleThreshold: 1, warmup: 1, expected: 2},
+		{testName: "single_gap", invocations: []int{1, 0, 1, 1}, idleThreshold: 1, warmup: 1, expected: 1},
+		{testName: "gap_below_threshold", invocations: []int{1, 0, 1, 0, 0, 1}, idleThreshold: 2, warmup: 1, expected: 1},
+		// the trace is not profiled without warmup, hence the function is deployed with no instances
+		{testName: "no_warmup", invocations: []int{1, 1, 1}, idleThreshold: 1, warmup: 0, expected: 1},
+		// deployed with the maximum scale over the warmup, although idle in the first minute
+		{testName: "warmup_max", invocations: []int{0, 1, 1}, idleThreshold: 1, strategy: common.ScaleProfilingWarmupMax, warmup: 2, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			function := &common.Function{
+				InvocationStats: &common.FunctionInvocationStats{Invocations: test.invocations},
+				RuntimeStats:    &common.FunctionRuntimeStats{Average: 1000},
+			}
+			profiling := ScaleProfilingConfiguration{
+				Strategy:           test.strategy,
+				WarmupDuration:     test.warmup,
+				ExperimentDuration: len(test.invocations) - test.warmup,
+			}
+
+			got := profileColdStarts(function, test.invocations, test.idleThreshold, profiling).ExpectedColdStarts
+			if got != test.expected {
+				t.Errorf("Unexpected number of cold starts - expected: %d; got: %d", test.expected, got)
+			}
+		})
+	}
+}
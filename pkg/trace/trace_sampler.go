@@ -0,0 +1,144 @@
+package trace
+
+import (
+	"math"
+	"math/rand"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// ResourceNormalizationFactor Brings the per-minute resource usage (invocations x runtime x memory) to the same
+// ballpark as the per-minute invocation count, as the average runtime is ~1000ms, the invocation count is <10/min, and
+// the memory is ~200MB. Taken over from the Python sampler.
+const ResourceNormalizationFactor = 1000 * 10 * 100
+
+// SampleDistance Wasserstein distances of a sample from the original trace. Invocations and Resources are averaged
+// over all minutes of the trace, whereas Runtime and Memory compare the per-function average runtime and memory.
+type SampleDistance struct {
+	Invocations float64 `json:"invocations"`
+	Resources   float64 `json:"resources"`
+	Runtime     float64 `json:"runtime"`
+	Memory      float64 `json:"memory"`
+	// Average Mean of the invocation and resource distances, used to select the best sample
+	Average float64 `json:"average"`
+}
+
+type TraceSampler struct {
+	original []*common.Function
+	random   *rand.Rand
+}
+
+func NewTraceSampler(original []*common.Function, seed int64) *TraceSampler {
+	return &TraceSampler{
+		original: filterSampleableFunctions(original),
+		random:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+func filterSampleableFunctions(functions []*common.Function) []*common.Function {
+	var result []*common.Function
+
+	for _, function := range functions {
+		if function.InvocationStats == nil || function.RuntimeStats == nil || function.MemoryStats == nil {
+			log.Warnf("Function %s is missing from at least one of the trace dimensions and will be skipped.", function.Name)
+			continue
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
+
+// Sample Draws trials random samples of the given size out of the source functions and returns the one closest to
+// the original trace, along with its distance.
+func (s *TraceSampler) Sample(source []*common.Function, size int, trials int) ([]*common.Function, SampleDistance) {
+	source = filterSampleableFunctions(source)
+	if size > len(source) {
+		log.Warnf("Requested sample size %d is larger than the source trace. Sampling %d functions.", size, len(source))
+		size = len(source)
+	}
+
+	var best []*common.Function
+	bestDistance := SampleDistance{Average: math.MaxFloat64}
+
+	for trial := 0; trial < common.MaxOf(trials, 1); trial++ {
+		candidate := make([]*common.Function, size)
+		for i, index := range s.random.Perm(len(source))[:size] {
+			candidate[i] = source[index]
+		}
+
+		distance := ComputeSampleDistance(s.original, candidate)
+		log.Debugf("Sampling trial %d - invocation WD: %.2f, resource WD: %.2f, average WD: %.2f",
+			trial, distance.Invocations, distance.Resources, distance.Average)
+
+		if distance.Average < bestDistance.Average {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best, bestDistance
+}
+
+func ComputeSampleDistance(original []*common.Function, sample []*common.Function) SampleDistance {
+	if len(original) == 0 || len(sample) == 0 {
+		return SampleDistance{}
+	}
+
+	duration := math.MaxInt
+	for _, function := range append(append([]*common.Function{}, original...), sample...) {
+		duration = common.MinOf(duration, len(function.InvocationStats.Invocations))
+	}
+
+	var distance SampleDistance
+	for minute := 0; minute < duration; minute++ {
+		originalInvocations, originalResources := minuteLoad(original, minute)
+		sampleInvocations, sampleResources := minuteLoad(sample, minute)
+
+		distance.Invocations += common.WassersteinDistance(sampleInvocations, originalInvocations)
+		distance.Resources += common.WassersteinDistance(sampleResources, originalResources)
+	}
+	if duration > 0 {
+		distance.Invocations /= float64(duration)
+		distance.Resources /= float64(duration)
+	}
+
+	distance.Runtime = common.WassersteinDistance(averageRuntimes(sample), averageRuntimes(original))
+	distance.Memory = common.WassersteinDistance(averageMemory(sample), averageMemory(original))
+	distance.Average = (distance.Invocations + distance.Resources) / 2
+
+	return distance
+}
+
+func minuteLoad(functions []*common.Function, minute int) ([]float64, []float64) {
+	invocations := make([]float64, len(functions))
+	resources := make([]float64, len(functions))
+
+	for i, function := range functions {
+		count := float64(function.InvocationStats.Invocations[minute])
+
+		invocations[i] = count
+		resources[i] = count * function.RuntimeStats.Average * function.MemoryStats.Average / ResourceNormalizationFactor
+	}
+
+	return invocations, resources
+}
+
+func averageRuntimes(functions []*common.Function) []float64 {
+	result := make([]float64, len(functions))
+	for i, function := range functions {
+		result[i] = function.RuntimeStats.Average
+	}
+
+	return result
+}
+
+func averageMemory(functions []*common.Function) []float64 {
+	result := make([]float64, len(functions))
+	for i, function := range functions {
+		result[i] = function.MemoryStats.Average
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createSamplingTestFunctions(count int) []*common.Function {
+	var functions []*common.Function
+
+	for i := 0; i < count; i++ {
+		hash := fmt.Sprintf("function%d", i)
+		functions = append(functions, &common.Function{
+			Name: fmt.Sprintf("%s-%d", common.FunctionNamePrefix, i),
+			InvocationStats: &common.FunctionInvocationStats{
+				HashOwner:    "owner",
+				HashApp:      "app",
+				HashFunction: hash,
+				Trigger:      common.TriggerHTTP,
+				Invocations:  []int{i, 2 * i, 3 * i},
+			},
+			RuntimeStats: &common.FunctionRuntimeStats{
+				HashOwner:    "owner",
+				HashApp:      "app",
+				HashFunction: hash,
+				Average:      float64(100 * (i + 1)),
+			},
+			MemoryStats: &common.FunctionMemoryStats{
+				HashOwner:    "owner",
+				HashApp:      "app",
+				HashFunction: hash,
+				Average:      float64(10 * (i + 1)),
+			},
+		})
+	}
+
+	return functions
+}
+
+func TestTraceSampler(t *testing.T) {
+	functions := createSamplingTestFunctions(10)
+	sampler := NewTraceSampler(functions, 42)
+
+	sample, distance := sampler.Sample(functions, 4, 8)
+	if len(sample) != 4 {
+		t.Fatalf("Unexpected sample size %d.", len(sample))
+	}
+	if distance.Average <= 0 || distance.Average != (distance.Invocations+distance.Resources)/2 {
+		t.Error("Unexpected sample distance.")
+	}
+
+	unique := make(map[string]struct{})
+	for _, function := range sample {
+		unique[function.InvocationStats.HashFunction] = struct{}{}
+	}
+	if len(unique) != len(sample) {
+		t.Error("Functions should be sampled without replacement.")
+	}
+
+	full, fullDistance := sampler.Sample(functions, 20, 1)
+	if len(full) != len(functions) || fullDistance.Average != 0 || fullDistance.Runtime != 0 || fullDistance.Memory != 0 {
+		t.Error("Sampling the whole trace should yield zero distance.")
+	}
+}
+
+func TestWriteAzureTrace(t *testing.T) {
+	directory := t.TempDir()
+	functions := createSamplingTestFunctions(3)
+
+	if err := WriteAzureTrace(directory, functions); err != nil {
+		t.Fatal(err)
+	}
+
+	if duration := ReadTraceDuration(directory); duration != 3 {
+		t.Errorf("Unexpected trace duration %d.", duration)
+	}
+
+	parsed := NewAzureParser(directory, 3, "").Parse()
+	if len(parsed) != len(functions) {
+		t.Fatalf("Unexpected number of parsed functions %d.", len(parsed))
+	}
+
+	for i, function := range parsed {
+		if function.InvocationStats.HashFunction != functions[i].InvocationStats.HashFunction ||
+			function.InvocationStats.Trigger != common.TriggerHTTP ||
+			function.InvocationStats.Invocations[2] != 3*i ||
+			function.RuntimeStats.Average != functions[i].RuntimeStats.Average ||
+			function.MemoryStats.Average != functions[i].MemoryStats.Average {
+
+			t.Errorf("Unexpected function %d read back.", i)
+		}
+	}
+}
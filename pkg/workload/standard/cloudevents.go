@@ -0,0 +1,103 @@
+package standard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	util "github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+)
+
+var completionClient = &http.Client{Timeout: 10 * time.Second}
+
+// cloudEventsHandler Executes the invocation events delivered by the Knative broker and reports the completion to
+// the sink given in the replyto extension of the event.
+func cloudEventsHandler(w http.ResponseWriter, r *http.Request) {
+	receivedAt := time.Now().UnixMicro()
+
+	if r.Header.Get(util.CloudEventTypeHeader) != util.CloudEventInvocationType {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var invocation util.CloudEventInvocation
+	if err = json.Unmarshal(body, &invocation); err != nil {
+		log.Warnf("Received a malformed invocation event - %s", body)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	reply, _ := (&funcServer{}).Execute(r.Context(), &proto.FaasRequest{
+		RuntimeInMilliSec: invocation.RuntimeInMilliSec,
+		MemoryInMebiBytes: invocation.MemoryInMebiBytes,
+	})
+
+	completion := util.AsyncCompletion{
+		InvocationID:       r.Header.Get(util.CloudEventIDHeader),
+		Function:           invocation.Function,
+		Message:            reply.Message,
+		ReceivedAt:         receivedAt,
+		DurationInMicroSec: reply.DurationInMicroSec,
+		MemoryUsageInKb:    reply.MemoryUsageInKb,
+	}
+
+	if sink := r.Header.Get(util.CloudEventReplyToHeader); sink != "" {
+		if err = reportCompletion(r.Context(), sink, &completion); err != nil {
+			log.Errorf("Failed to report the completion of %s - %v", completion.InvocationID, err)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func reportCompletion(ctx context.Context, sink string, completion *util.AsyncCompletion) error {
+	data, err := json.Marshal(completion)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", sink, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(util.CloudEventIDHeader, completion.InvocationID+"-completion")
+	req.Header.Set(util.CloudEventSpecVersionHeader, util.CloudEventSpecVersion)
+	req.Header.Set(util.CloudEventSourceHeader, hostname)
+	req.Header.Set(util.CloudEventTypeHeader, util.CloudEventCompletionType)
+
+	resp, err := completionClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("sink responded with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// StartCloudEventsServer Serves invocations delivered as CloudEvents over HTTP, which is used in the Knative
+// asynchronous mode of the loader
+func StartCloudEventsServer(serverAddress string, serverPort int, functionType FunctionType) {
+	readEnvironmentalVariables()
+	serverSideCode = functionType
+
+	err := http.ListenAndServe(fmt.Sprintf("%s:%d", serverAddress, serverPort), http.HandlerFunc(cloudEventsHandler))
+	util.Check(err)
+}
@@ -0,0 +1,67 @@
+package standard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	util "github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+)
+
+// httpHandler Executes invocations posted as plain HTTP requests and replies with the execution statistics
+func httpHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var invocation util.HTTPInvocation
+	if err = json.Unmarshal(body, &invocation); err != nil {
+		log.Warnf("Received a malformed invocation - %s", body)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	reply, _ := (&funcServer{}).Execute(r.Context(), &proto.FaasRequest{
+		RuntimeInMilliSec: invocation.RuntimeInMilliSec,
+		MemoryInMebiBytes: invocation.MemoryInMebiBytes,
+	})
+
+	data, err := json.Marshal(util.HTTPInvocationReply{
+		Message:            reply.Message,
+		Hostname:           hostname,
+		DurationInMicroSec: reply.DurationInMicroSec,
+		MemoryUsageInKb:    reply.MemoryUsageInKb,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// StartHTTPServer Serves invocations as plain HTTP requests, which is used on platforms proxying invocations through
+// an HTTP gateway, i.e., OpenFaaS and Fission
+func StartHTTPServer(serverAddress string, serverPort int, functionType FunctionType) {
+	readEnvironmentalVariables()
+	serverSideCode = functionType
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", httpHandler)
+	// health checks of the OpenFaaS and Fission executors
+	mux.HandleFunc("/_/health", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	err := http.ListenAndServe(fmt.Sprintf("%s:%d", serverAddress, serverPort), mux)
+	util.Check(err)
+}
@@ -77,6 +77,10 @@ func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.
 		// NOTE: the following statement to make sure the compiler does not treat the allocation as dead code
 		//log.Debugf("Allocated memory size: %d\n", len(memory))
 		msg = util.TraceFunctionExecution(start, uint32(IterationsMultiplier), timeLeftMilliseconds)
+		if msg == "" {
+			// the hostname names the instance, by which the loader validates that the right function was invoked
+			msg = fmt.Sprintf("OK - %s", hostname)
+		}
 	} else {
 		msg = fmt.Sprintf("OK - EMPTY - %s", hostname)
 	}
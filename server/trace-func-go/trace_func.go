@@ -62,5 +62,14 @@ func main() {
 		log.Infof("Function type: EMPTY\n")
 	}
 
-	standard.StartGRPCServer("", serverPort, functionType, *zipkin)
+	switch os.Getenv("FUNC_PROTOCOL_ENV") {
+	case "cloudevents":
+		log.Infof("Protocol: CloudEvents\n")
+		standard.StartCloudEventsServer("", serverPort, functionType)
+	case "http":
+		log.Infof("Protocol: HTTP\n")
+		standard.StartHTTPServer("", serverPort, functionType)
+	default:
+		standard.StartGRPCServer("", serverPort, functionType, *zipkin)
+	}
 }